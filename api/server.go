@@ -2,10 +2,13 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/kubemq-io/kubemq-targets/binding"
+	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -15,7 +18,7 @@ type Server struct {
 	bindingService *binding.Service
 }
 
-func Start(ctx context.Context, port int, bs *binding.Service) (*Server, error) {
+func newServer(bs *binding.Service) *Server {
 	s := &Server{
 		echoWebServer:  echo.New(),
 		bindingService: bs,
@@ -49,6 +52,16 @@ func Start(ctx context.Context, port int, bs *binding.Service) (*Server, error)
 		}
 		return c.JSONPretty(200, s.bindingService.SendRequest(c.Request().Context(), req), "\t")
 	})
+	s.echoWebServer.POST("/bindings", s.addBinding)
+	s.echoWebServer.PUT("/bindings/:name", s.updateBinding)
+	s.echoWebServer.DELETE("/bindings/:name", s.deleteBinding)
+	s.echoWebServer.POST("/bindings/:name/pause", s.pauseBinding)
+	s.echoWebServer.POST("/bindings/:name/resume", s.resumeBinding)
+	return s
+}
+
+func Start(ctx context.Context, port int, bs *binding.Service) (*Server, error) {
+	s := newServer(bs)
 	errCh := make(chan error, 1)
 	go func() {
 		errCh <- s.echoWebServer.Start(fmt.Sprintf("0.0.0.0:%d", port))
@@ -72,3 +85,75 @@ func (s *Server) Stop() error {
 	defer cancel()
 	return s.echoWebServer.Shutdown(ctx)
 }
+
+type errorResponse struct {
+	Error string
+}
+
+func (s *Server) errorResult(c echo.Context, err error) error {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, binding.ErrBindingNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, binding.ErrBindingExists):
+		code = http.StatusConflict
+	}
+	return c.JSONPretty(code, &errorResponse{Error: err.Error()}, "\t")
+}
+
+// bindingResult persists the configuration when requested with persist=true and returns the binding status
+func (s *Server) bindingResult(c echo.Context, code int, name string) error {
+	if c.QueryParam("persist") == "true" {
+		if err := s.bindingService.Persist(); err != nil {
+			return c.JSONPretty(http.StatusInternalServerError, &errorResponse{Error: fmt.Sprintf("error persisting config, %s", err.Error())}, "\t")
+		}
+	}
+	status, err := s.bindingService.GetBindingStatus(name)
+	if err != nil {
+		return c.NoContent(code)
+	}
+	return c.JSONPretty(code, status, "\t")
+}
+
+func (s *Server) addBinding(c echo.Context) error {
+	cfg := config.BindingConfig{}
+	if err := c.Bind(&cfg); err != nil {
+		return s.errorResult(c, fmt.Errorf("invalid binding, %s", err.Error()))
+	}
+	if err := s.bindingService.AddBinding(cfg); err != nil {
+		return s.errorResult(c, err)
+	}
+	return s.bindingResult(c, http.StatusCreated, cfg.Name)
+}
+
+func (s *Server) updateBinding(c echo.Context) error {
+	cfg := config.BindingConfig{}
+	if err := c.Bind(&cfg); err != nil {
+		return s.errorResult(c, fmt.Errorf("invalid binding, %s", err.Error()))
+	}
+	if err := s.bindingService.UpdateBinding(c.Param("name"), cfg); err != nil {
+		return s.errorResult(c, err)
+	}
+	return s.bindingResult(c, http.StatusOK, c.Param("name"))
+}
+
+func (s *Server) deleteBinding(c echo.Context) error {
+	if err := s.bindingService.DeleteBinding(c.Param("name")); err != nil {
+		return s.errorResult(c, err)
+	}
+	return s.bindingResult(c, http.StatusOK, c.Param("name"))
+}
+
+func (s *Server) pauseBinding(c echo.Context) error {
+	if err := s.bindingService.PauseBinding(c.Param("name")); err != nil {
+		return s.errorResult(c, err)
+	}
+	return s.bindingResult(c, http.StatusOK, c.Param("name"))
+}
+
+func (s *Server) resumeBinding(c echo.Context) error {
+	if err := s.bindingService.ResumeBinding(c.Param("name")); err != nil {
+		return s.errorResult(c, err)
+	}
+	return s.bindingResult(c, http.StatusOK, c.Param("name"))
+}
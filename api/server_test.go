@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/kubemq-io/kubemq-targets/binding"
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+const testBinding = `{
+  "name": "%s",
+  "source": {
+    "kind": "schedule",
+    "properties": {
+      "interval_seconds": "%s",
+      "metadata": "{\"key\":\"value\"}"
+    }
+  },
+  "target": {
+    "kind": "echo",
+    "properties": {}
+  }
+}`
+
+var (
+	// the binding service registers its metrics collectors globally, so it is created once
+	testService     *binding.Service
+	testServiceErr  error
+	testServiceOnce sync.Once
+)
+
+func newTestBinding(name, interval string) string {
+	return fmt.Sprintf(testBinding, name, interval)
+}
+
+func TestServer_Bindings(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("bindings: []\n"), 0o600))
+	viper.SetConfigFile(filename)
+	defer viper.Reset()
+
+	testServiceOnce.Do(func() {
+		testService, testServiceErr = binding.New()
+	})
+	require.NoError(t, testServiceErr)
+	bs := testService
+	require.NoError(t, bs.Start(context.Background(), &config.Config{}))
+	defer bs.Stop()
+	s := newServer(bs)
+
+	steps := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantCode   int
+		wantStatus *binding.Status
+	}{
+		{
+			name:     "add - invalid json",
+			method:   http.MethodPost,
+			path:     "/bindings",
+			body:     `{"name":`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "add - invalid config",
+			method:   http.MethodPost,
+			path:     "/bindings",
+			body:     newTestBinding("", "3600"),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:       "add",
+			method:     http.MethodPost,
+			path:       "/bindings?persist=true",
+			body:       newTestBinding("b1", "3600"),
+			wantCode:   http.StatusCreated,
+			wantStatus: &binding.Status{Binding: "b1"},
+		},
+		{
+			name:     "add - binding exists",
+			method:   http.MethodPost,
+			path:     "/bindings",
+			body:     newTestBinding("b1", "3600"),
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "update - unknown binding",
+			method:   http.MethodPut,
+			path:     "/bindings/b2",
+			body:     newTestBinding("b2", "3600"),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "update - name mismatch",
+			method:   http.MethodPut,
+			path:     "/bindings/b1",
+			body:     newTestBinding("b2", "3600"),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:       "update",
+			method:     http.MethodPut,
+			path:       "/bindings/b1?persist=true",
+			body:       newTestBinding("b1", "7200"),
+			wantCode:   http.StatusOK,
+			wantStatus: &binding.Status{Binding: "b1"},
+		},
+		{
+			name:     "pause - unknown binding",
+			method:   http.MethodPost,
+			path:     "/bindings/b2/pause",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:       "pause",
+			method:     http.MethodPost,
+			path:       "/bindings/b1/pause",
+			wantCode:   http.StatusOK,
+			wantStatus: &binding.Status{Binding: "b1", Paused: true},
+		},
+		{
+			name:       "pause - already paused",
+			method:     http.MethodPost,
+			path:       "/bindings/b1/pause",
+			wantCode:   http.StatusOK,
+			wantStatus: &binding.Status{Binding: "b1", Paused: true},
+		},
+		{
+			name:     "resume - unknown binding",
+			method:   http.MethodPost,
+			path:     "/bindings/b2/resume",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:       "resume",
+			method:     http.MethodPost,
+			path:       "/bindings/b1/resume",
+			wantCode:   http.StatusOK,
+			wantStatus: &binding.Status{Binding: "b1"},
+		},
+		{
+			name:     "delete - unknown binding",
+			method:   http.MethodDelete,
+			path:     "/bindings/b2",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, step := range steps {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(step.method, step.path, strings.NewReader(step.body))
+		req.Header.Set("Content-Type", "application/json")
+		s.echoWebServer.ServeHTTP(rec, req)
+		require.EqualValues(t, step.wantCode, rec.Code, "%s: %s", step.name, rec.Body.String())
+		if step.wantStatus == nil {
+			continue
+		}
+		status := &binding.Status{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), status), step.name)
+		require.EqualValues(t, step.wantStatus.Binding, status.Binding, step.name)
+		require.EqualValues(t, step.wantStatus.Paused, status.Paused, step.name)
+	}
+
+	// the updated binding was persisted
+	data, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	saved := &config.Config{}
+	require.NoError(t, yaml.Unmarshal(data, saved))
+	require.Len(t, saved.Bindings, 1)
+	require.EqualValues(t, "b1", saved.Bindings[0].Name)
+	require.EqualValues(t, "7200", saved.Bindings[0].Source.Properties["interval_seconds"])
+
+	require.Eventually(t, func() bool {
+		status, err := bs.GetBindingStatus("b1")
+		return err == nil && status.Ready
+	}, 5*time.Second, 10*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	s.echoWebServer.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/bindings/b1?persist=true", nil))
+	require.EqualValues(t, http.StatusOK, rec.Code, rec.Body.String())
+	_, err = bs.GetBindingStatus("b1")
+	require.ErrorIs(t, err, binding.ErrBindingNotFound)
+	data, err = ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	saved = &config.Config{}
+	require.NoError(t, yaml.Unmarshal(data, saved))
+	require.Empty(t, saved.Bindings)
+}
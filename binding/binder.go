@@ -12,19 +12,30 @@ import (
 	"github.com/kubemq-io/kubemq-targets/targets"
 )
 
+type binderTarget struct {
+	route          string
+	target         targets.Target
+	circuitBreaker *middleware.CircuitBreakerMiddleware
+}
+
 type Binder struct {
-	name   string
-	log    *logger.Logger
-	source sources.Source
-	target targets.Target
-	md     middleware.Middleware
+	name    string
+	log     *logger.Logger
+	source  sources.Source
+	targets []*binderTarget
+	dedup   *middleware.DedupMiddleware
+	md      middleware.Middleware
 }
 
 func NewBinder() *Binder {
 	return &Binder{}
 }
 
-func (b *Binder) buildMiddleware(cfg config.BindingConfig, exporter *metrics.Exporter) (middleware.Middleware, error) {
+func (b *Binder) buildTargetMiddleware(cfg config.BindingConfig, bt *binderTarget, exporter *metrics.Exporter) (middleware.Middleware, error) {
+	met, err := middleware.NewMetricsMiddleware(cfg, exporter)
+	if err != nil {
+		return nil, err
+	}
 	retry, err := middleware.NewRetryMiddleware(cfg.Properties, b.log)
 	if err != nil {
 		return nil, err
@@ -33,33 +44,99 @@ func (b *Binder) buildMiddleware(cfg config.BindingConfig, exporter *metrics.Exp
 	if err != nil {
 		return nil, err
 	}
-	met, err := middleware.NewMetricsMiddleware(cfg, exporter)
+	bt.circuitBreaker, err = middleware.NewCircuitBreakerMiddleware(cfg, exporter, b.log)
 	if err != nil {
 		return nil, err
 	}
+	md := middleware.Chain(bt.target,
+		middleware.TargetSpan(cfg.Target.Kind),
+		middleware.Span("rate-limiter", middleware.RateLimiter(rateLimiter.WithMetrics(met))),
+		middleware.Span("retry", middleware.Retry(retry.WithMetrics(met))),
+		middleware.Span("circuit-breaker", middleware.CircuitBreaker(bt.circuitBreaker)),
+		middleware.Metric(met))
+	return md, nil
+}
+
+func (b *Binder) buildMiddleware(ctx context.Context, cfg config.BindingConfig, targetMd middleware.Middleware) (middleware.Middleware, error) {
 	meta, err := middleware.NewMetadataMiddleware(cfg.Properties)
 	if err != nil {
 		return nil, err
 	}
-	md := middleware.Chain(b.target, middleware.RateLimiter(rateLimiter), middleware.Retry(retry), middleware.Metric(met), middleware.Metadata(meta))
+	transform, err := middleware.NewTransformMiddleware(cfg.Properties)
+	if err != nil {
+		return nil, err
+	}
+	b.dedup, err = middleware.NewDedupMiddleware(ctx, cfg, b.log)
+	if err != nil {
+		return nil, err
+	}
+	md := middleware.Chain(targetMd,
+		middleware.Span("transform", middleware.Transform(transform)),
+		middleware.Span("metadata", middleware.Metadata(meta)),
+		middleware.Span("dedup", middleware.Dedup(b.dedup)),
+		middleware.BindingSpan(cfg.Name, cfg.Source.Kind))
 	return md, nil
 }
 
+func (b *Binder) initTarget(ctx context.Context, cfg config.BindingConfig, exporter *metrics.Exporter) (middleware.Middleware, error) {
+	bt := &binderTarget{
+		route: cfg.Route,
+	}
+	targetSpec, err := cfg.Target.ResolveSecrets()
+	if err != nil {
+		return nil, err
+	}
+	bt.target, err = targets.Init(ctx, targetSpec, b.log)
+	if err != nil {
+		return nil, err
+	}
+	b.targets = append(b.targets, bt)
+	return b.buildTargetMiddleware(cfg, bt, exporter)
+}
+
+func (b *Binder) initRoutes(ctx context.Context, cfg config.BindingConfig, exporter *metrics.Exporter) (middleware.Middleware, error) {
+	var routes []*middleware.Route
+	for _, routeCfg := range cfg.Routes {
+		targetMd, err := b.initTarget(ctx, cfg.RouteBinding(routeCfg), exporter)
+		if err != nil {
+			return nil, fmt.Errorf("route %s, %w", routeCfg.Name, err)
+		}
+		route, err := middleware.NewRoute(routeCfg, targetMd)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+		b.log.Infof("binding: %s route: %s target: initialized successfully", b.name, routeCfg.Name)
+	}
+	return middleware.NewRouterMiddleware(cfg.RoutingMode(), routes), nil
+}
+
 func (b *Binder) Init(ctx context.Context, cfg config.BindingConfig, exporter *metrics.Exporter, logLevel string) error {
 	b.name = cfg.Name
 	var err error
 	b.log = logger.NewLogger(fmt.Sprintf("binding-%s", cfg.Name), logLevel)
-
-	b.target, err = targets.Init(ctx, cfg.Target, b.log)
-	if err != nil {
-		return fmt.Errorf("error loading target conntector on binding %s, %w", b.name, err)
+	var targetMd middleware.Middleware
+	if len(cfg.Routes) == 0 {
+		targetMd, err = b.initTarget(ctx, cfg, exporter)
+		if err != nil {
+			return fmt.Errorf("error loading target conntector on binding %s, %w", b.name, err)
+		}
+		b.log.Infof("binding: %s target: initialized successfully", b.name)
+	} else {
+		targetMd, err = b.initRoutes(ctx, cfg, exporter)
+		if err != nil {
+			return fmt.Errorf("error loading target conntector on binding %s, %w", b.name, err)
+		}
 	}
-	b.log.Infof("binding: %s target: initialized successfully", b.name)
-	b.md, err = b.buildMiddleware(cfg, exporter)
+	b.md, err = b.buildMiddleware(ctx, cfg, targetMd)
 	if err != nil {
 		return fmt.Errorf("error loading middlewares on binding %s, %w", b.name, err)
 	}
-	b.source, err = sources.Init(ctx, cfg.Source, cfg.Name, b.log)
+	sourceSpec, err := cfg.Source.ResolveSecrets()
+	if err != nil {
+		return fmt.Errorf("error loading source conntector on binding %s, %w", b.name, err)
+	}
+	b.source, err = sources.Init(ctx, sourceSpec, cfg.Name, b.log)
 	if err != nil {
 		return fmt.Errorf("error loading source conntector on binding %s, %w", b.name, err)
 	}
@@ -68,6 +145,15 @@ func (b *Binder) Init(ctx context.Context, cfg config.BindingConfig, exporter *m
 	return nil
 }
 
+// circuitBreakerStates returns the circuit breaker state of each binding target keyed by route name
+func (b *Binder) circuitBreakerStates() map[string]string {
+	states := map[string]string{}
+	for _, bt := range b.targets {
+		states[bt.route] = bt.circuitBreaker.State()
+	}
+	return states
+}
+
 func (b *Binder) Start(ctx context.Context) error {
 	if b.md == nil {
 		return fmt.Errorf("error starting binding connector %s,no valid initialzed target middleware found", b.name)
@@ -89,9 +175,14 @@ func (b *Binder) Stop() error {
 		return err
 	}
 
-	err = b.target.Stop()
-	if err != nil {
-		return err
+	for _, bt := range b.targets {
+		err = bt.target.Stop()
+		if err != nil {
+			return err
+		}
+	}
+	if err := b.dedup.Close(); err != nil {
+		b.log.Errorf("error closing dedup store, %s", err.Error())
 	}
 	b.log.Infof("binding: %s, stopped successfully", b.name)
 
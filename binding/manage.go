@@ -0,0 +1,137 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+)
+
+var (
+	ErrBindingNotFound = errors.New("binding not found")
+	ErrBindingExists   = errors.New("binding already exists")
+)
+
+func (s *Service) findBinding(name string) (int, bool) {
+	for i, bindingCfg := range s.cfg.Bindings {
+		if bindingCfg.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// AddBinding validates and starts a new binding and adds it to the current configuration
+func (s *Service) AddBinding(cfg config.BindingConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.findBinding(cfg.Name); ok {
+		return fmt.Errorf("%w: %s", ErrBindingExists, cfg.Name)
+	}
+	s.cfg.Bindings = append(s.cfg.Bindings, cfg)
+	s.startBinding(cfg, s.cfg.LogLevel)
+	s.log.Infof("binding: %s added", cfg.Name)
+	return nil
+}
+
+// UpdateBinding validates and replaces an existing binding, the binding is restarted with the new configuration
+func (s *Service) UpdateBinding(name string, cfg config.BindingConfig) error {
+	if cfg.Name == "" {
+		cfg.Name = name
+	}
+	if cfg.Name != name {
+		return fmt.Errorf("binding name %s does not match %s", cfg.Name, name)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	index, ok := s.findBinding(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrBindingNotFound, name)
+	}
+	if err := s.stopBinding(name); err != nil {
+		return err
+	}
+	s.paused.Delete(name)
+	s.cfg.Bindings[index] = cfg
+	s.startBinding(cfg, s.cfg.LogLevel)
+	s.log.Infof("binding: %s updated, restarted", name)
+	return nil
+}
+
+// DeleteBinding stops a binding and removes it from the current configuration
+func (s *Service) DeleteBinding(name string) error {
+	s.Lock()
+	defer s.Unlock()
+	index, ok := s.findBinding(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrBindingNotFound, name)
+	}
+	if err := s.stopBinding(name); err != nil {
+		return err
+	}
+	s.paused.Delete(name)
+	s.cfg.Bindings = append(s.cfg.Bindings[:index], s.cfg.Bindings[index+1:]...)
+	s.log.Infof("binding: %s removed", name)
+	return nil
+}
+
+// PauseBinding stops a binding and keeps it in the current configuration until it is resumed
+func (s *Service) PauseBinding(name string) error {
+	s.Lock()
+	defer s.Unlock()
+	index, ok := s.findBinding(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrBindingNotFound, name)
+	}
+	if _, ok := s.paused.Load(name); ok {
+		return nil
+	}
+	if err := s.stopBinding(name); err != nil {
+		return err
+	}
+	status := newStatus(s.cfg.Bindings[index])
+	status.Paused = true
+	s.bindingStatus.Store(name, status)
+	s.paused.Store(name, true)
+	s.log.Infof("binding: %s paused", name)
+	return nil
+}
+
+// ResumeBinding starts a paused binding
+func (s *Service) ResumeBinding(name string) error {
+	s.Lock()
+	defer s.Unlock()
+	index, ok := s.findBinding(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrBindingNotFound, name)
+	}
+	if _, ok := s.paused.LoadAndDelete(name); !ok {
+		return nil
+	}
+	s.startBinding(s.cfg.Bindings[index], s.cfg.LogLevel)
+	s.log.Infof("binding: %s resumed", name)
+	return nil
+}
+
+// GetBindingStatus returns the status of a single binding
+func (s *Service) GetBindingStatus(name string) (*Status, error) {
+	for _, status := range s.GetStatus() {
+		if status.Binding == name {
+			return status, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrBindingNotFound, name)
+}
+
+// Persist saves the current configuration back to the config file
+func (s *Service) Persist() error {
+	s.Lock()
+	defer s.Unlock()
+	return config.Save(s.cfg)
+}
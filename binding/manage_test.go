@@ -0,0 +1,272 @@
+package binding
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/metrics"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// the exporter registers its collectors globally, so all the test services share one
+	testExporter     *metrics.Exporter
+	testExporterErr  error
+	testExporterOnce sync.Once
+)
+
+func newTestBinding(name string) config.BindingConfig {
+	return config.BindingConfig{
+		Name: name,
+		Source: config.Spec{
+			Name: "schedule",
+			Kind: "schedule",
+			Properties: map[string]string{
+				"interval_seconds": "3600",
+				"metadata":         `{"key":"value"}`,
+			},
+		},
+		Target: config.Spec{
+			Name:       "echo",
+			Kind:       "echo",
+			Properties: map[string]string{},
+		},
+		Properties: map[string]string{},
+	}
+}
+
+func newTestService(t *testing.T, bindings ...config.BindingConfig) *Service {
+	testExporterOnce.Do(func() {
+		testExporter, testExporterErr = metrics.NewExporter()
+	})
+	require.NoError(t, testExporterErr)
+	s := &Service{
+		log:      logger.NewLogger("binding-service"),
+		exporter: testExporter,
+	}
+	require.NoError(t, s.Start(context.Background(), &config.Config{Bindings: bindings}))
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func requireReady(t *testing.T, s *Service, name string) {
+	require.Eventually(t, func() bool {
+		status, err := s.GetBindingStatus(name)
+		return err == nil && status.Ready
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func bindingNames(s *Service) []string {
+	s.Lock()
+	defer s.Unlock()
+	var names []string
+	for _, bindingCfg := range s.cfg.Bindings {
+		names = append(names, bindingCfg.Name)
+	}
+	return names
+}
+
+func TestService_AddBinding(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.BindingConfig
+		wantErr   bool
+		wantErrIs error
+		wantNames []string
+	}{
+		{
+			name:      "add",
+			cfg:       newTestBinding("b2"),
+			wantNames: []string{"b1", "b2"},
+		},
+		{
+			name:      "invalid config - no name",
+			cfg:       newTestBinding(""),
+			wantErr:   true,
+			wantNames: []string{"b1"},
+		},
+		{
+			name: "invalid config - no target kind",
+			cfg: func() config.BindingConfig {
+				cfg := newTestBinding("b2")
+				cfg.Target.Kind = ""
+				return cfg
+			}(),
+			wantErr:   true,
+			wantNames: []string{"b1"},
+		},
+		{
+			name:      "binding exists",
+			cfg:       newTestBinding("b1"),
+			wantErr:   true,
+			wantErrIs: ErrBindingExists,
+			wantNames: []string{"b1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, newTestBinding("b1"))
+			err := s.AddBinding(tt.cfg)
+			require.EqualValues(t, tt.wantNames, bindingNames(s))
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					require.ErrorIs(t, err, tt.wantErrIs)
+				}
+				return
+			}
+			require.NoError(t, err)
+			requireReady(t, s, tt.cfg.Name)
+		})
+	}
+}
+
+func TestService_UpdateBinding(t *testing.T) {
+	updated := newTestBinding("b1")
+	updated.Source.Properties["interval_seconds"] = "7200"
+	tests := []struct {
+		name      string
+		bindName  string
+		cfg       config.BindingConfig
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name:     "update",
+			bindName: "b1",
+			cfg:      updated,
+		},
+		{
+			name:     "update - name from path",
+			bindName: "b1",
+			cfg: func() config.BindingConfig {
+				cfg := newTestBinding("")
+				cfg.Source.Properties["interval_seconds"] = "7200"
+				return cfg
+			}(),
+		},
+		{
+			name:     "name mismatch",
+			bindName: "b1",
+			cfg:      newTestBinding("b2"),
+			wantErr:  true,
+		},
+		{
+			name:     "invalid config - no source kind",
+			bindName: "b1",
+			cfg: func() config.BindingConfig {
+				cfg := newTestBinding("b1")
+				cfg.Source.Kind = ""
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name:      "unknown binding",
+			bindName:  "b2",
+			cfg:       newTestBinding("b2"),
+			wantErr:   true,
+			wantErrIs: ErrBindingNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, newTestBinding("b1"))
+			requireReady(t, s, "b1")
+			err := s.UpdateBinding(tt.bindName, tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					require.ErrorIs(t, err, tt.wantErrIs)
+				}
+				require.EqualValues(t, "3600", s.cfg.Bindings[0].Source.Properties["interval_seconds"])
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, []string{"b1"}, bindingNames(s))
+			require.EqualValues(t, "7200", s.cfg.Bindings[0].Source.Properties["interval_seconds"])
+			requireReady(t, s, "b1")
+			status, err := s.GetBindingStatus("b1")
+			require.NoError(t, err)
+			require.EqualValues(t, "7200", status.SourceConfig["interval_seconds"])
+		})
+	}
+}
+
+func TestService_DeleteBinding(t *testing.T) {
+	s := newTestService(t, newTestBinding("b1"), newTestBinding("b2"))
+	requireReady(t, s, "b1")
+	require.NoError(t, s.DeleteBinding("b1"))
+	require.EqualValues(t, []string{"b2"}, bindingNames(s))
+	_, err := s.GetBindingStatus("b1")
+	require.ErrorIs(t, err, ErrBindingNotFound)
+	_, ok := s.bindings.Load("b1")
+	require.False(t, ok)
+	require.ErrorIs(t, s.DeleteBinding("b1"), ErrBindingNotFound)
+}
+
+func TestService_PauseResumeBinding(t *testing.T) {
+	s := newTestService(t, newTestBinding("b1"))
+	requireReady(t, s, "b1")
+	require.ErrorIs(t, s.PauseBinding("b2"), ErrBindingNotFound)
+	require.ErrorIs(t, s.ResumeBinding("b2"), ErrBindingNotFound)
+
+	require.NoError(t, s.PauseBinding("b1"))
+	// pausing twice keeps the binding paused
+	require.NoError(t, s.PauseBinding("b1"))
+	status, err := s.GetBindingStatus("b1")
+	require.NoError(t, err)
+	require.True(t, status.Paused)
+	require.False(t, status.Ready)
+	_, ok := s.bindings.Load("b1")
+	require.False(t, ok)
+	require.EqualValues(t, []string{"b1"}, bindingNames(s))
+
+	require.NoError(t, s.ResumeBinding("b1"))
+	requireReady(t, s, "b1")
+	status, err = s.GetBindingStatus("b1")
+	require.NoError(t, err)
+	require.False(t, status.Paused)
+	// resuming a running binding does not restart it
+	val, ok := s.bindings.Load("b1")
+	require.True(t, ok)
+	require.NoError(t, s.ResumeBinding("b1"))
+	current, ok := s.bindings.Load("b1")
+	require.True(t, ok)
+	require.Same(t, val, current)
+}
+
+func TestService_Persist(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("bindings: []\n"), 0o600))
+	viper.SetConfigFile(filename)
+	t.Cleanup(viper.Reset)
+
+	s := newTestService(t, newTestBinding("b1"))
+	added := newTestBinding("b2")
+	added.Target.Properties["key"] = "value"
+	require.NoError(t, s.AddBinding(added))
+	require.NoError(t, s.DeleteBinding("b1"))
+	require.NoError(t, s.Persist())
+
+	data, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	saved := &config.Config{}
+	require.NoError(t, yaml.Unmarshal(data, saved))
+	require.Len(t, saved.Bindings, 1)
+	require.True(t, added.Equal(saved.Bindings[0]))
+}
+
+func TestService_Persist_NoConfigFile(t *testing.T) {
+	viper.Reset()
+	s := newTestService(t, newTestBinding("b1"))
+	require.Error(t, s.Persist())
+}
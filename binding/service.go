@@ -18,12 +18,15 @@ const (
 )
 
 type Service struct {
+	sync.Mutex
 	bindings          sync.Map
 	log               *logger.Logger
 	exporter          *metrics.Exporter
 	currentCtx        context.Context
 	currentCancelFunc context.CancelFunc
 	bindingStatus     sync.Map
+	bindingCancels    sync.Map
+	paused            sync.Map
 	cfg               *config.Config
 }
 
@@ -44,35 +47,93 @@ func New() (*Service, error) {
 func (s *Service) Start(ctx context.Context, cfg *config.Config) error {
 	s.currentCtx, s.currentCancelFunc = context.WithCancel(ctx)
 	s.cfg = cfg
-	if len(cfg.Bindings) == 0 {
+	for _, bindingCfg := range cfg.Bindings {
+		s.startBinding(bindingCfg, cfg.LogLevel)
+	}
+	return nil
+}
+
+func (s *Service) startBinding(cfg config.BindingConfig, logLevel string) {
+	ctx, cancel := context.WithCancel(s.currentCtx)
+	s.bindingCancels.Store(cfg.Name, cancel)
+	// the status is reported as not ready until the binding is initialized
+	status := newStatus(cfg)
+	s.bindingStatus.Store(cfg.Name, status)
+	go func(ctx context.Context, cfg config.BindingConfig, logLevel string) {
+		err := s.add(ctx, cfg, logLevel, status)
+		if err == nil {
+			return
+		} else {
+			s.log.Errorf("failed to initialized binding, %s", err.Error())
+		}
+		count := 0
+		for {
+			select {
+			case <-time.After(addRetryInterval):
+				count++
+				err := s.add(ctx, cfg, logLevel, status)
+				if err != nil {
+					s.log.Errorf("failed to initialized binding: %s, attempt: %d, error: %s", cfg.Name, count, err.Error())
+				} else {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}(ctx, cfg, logLevel)
+}
+
+func (s *Service) stopBinding(name string) error {
+	defer func() {
+		if val, ok := s.bindingCancels.LoadAndDelete(name); ok {
+			val.(context.CancelFunc)()
+		}
+	}()
+	if _, ok := s.bindings.Load(name); !ok {
+		s.bindingStatus.Delete(name)
 		return nil
 	}
+	return s.Remove(name)
+}
+
+// Update applies a new configuration, only bindings which were added, removed or changed are stopped and started
+func (s *Service) Update(cfg *config.Config) {
+	s.Lock()
+	defer s.Unlock()
+	oldBindings := map[string]config.BindingConfig{}
+	for _, bindingCfg := range s.cfg.Bindings {
+		oldBindings[bindingCfg.Name] = bindingCfg
+	}
+	newBindings := map[string]config.BindingConfig{}
 	for _, bindingCfg := range cfg.Bindings {
-		go func(ctx context.Context, cfg config.BindingConfig, logLevel string) {
-			err := s.Add(ctx, cfg, logLevel)
-			if err == nil {
-				return
-			} else {
-				s.log.Errorf("failed to initialized binding, %s", err.Error())
+		newBindings[bindingCfg.Name] = bindingCfg
+	}
+	for name := range oldBindings {
+		if _, ok := newBindings[name]; !ok {
+			s.paused.Delete(name)
+			if err := s.stopBinding(name); err != nil {
+				s.log.Errorf("failed to remove binding: %s, error: %s", name, err.Error())
 			}
-			count := 0
-			for {
-				select {
-				case <-time.After(addRetryInterval):
-					count++
-					err := s.Add(ctx, cfg, logLevel)
-					if err != nil {
-						s.log.Errorf("failed to initialized binding: %s, attempt: %d, error: %s", cfg.Name, count, err.Error())
-					} else {
-						return
-					}
-				case <-ctx.Done():
-					return
-				}
+			s.log.Infof("binding: %s removed", name)
+		}
+	}
+	for _, bindingCfg := range cfg.Bindings {
+		oldCfg, ok := oldBindings[bindingCfg.Name]
+		switch {
+		case !ok:
+			s.startBinding(bindingCfg, cfg.LogLevel)
+			s.log.Infof("binding: %s added", bindingCfg.Name)
+		case !oldCfg.Equal(bindingCfg) || s.cfg.LogLevel != cfg.LogLevel:
+			s.paused.Delete(bindingCfg.Name)
+			if err := s.stopBinding(bindingCfg.Name); err != nil {
+				s.log.Errorf("failed to stop changed binding: %s, error: %s", bindingCfg.Name, err.Error())
 			}
-		}(s.currentCtx, bindingCfg, cfg.LogLevel)
+			s.startBinding(bindingCfg, cfg.LogLevel)
+			s.log.Infof("binding: %s changed, restarted", bindingCfg.Name)
+		}
 	}
-	return nil
+	s.cfg = cfg
 }
 
 func (s *Service) Stop() {
@@ -85,12 +146,22 @@ func (s *Service) Stop() {
 		}
 		return true
 	})
+	s.bindingCancels.Range(func(key, value interface{}) bool {
+		s.bindingCancels.Delete(key)
+		return true
+	})
 }
 
 func (s *Service) Add(ctx context.Context, cfg config.BindingConfig, logLevel string) error {
-	binder := NewBinder()
 	status := newStatus(cfg)
 	s.bindingStatus.Store(cfg.Name, status)
+	return s.add(ctx, cfg, logLevel, status)
+}
+
+// add initializes and starts a binding, status is updated only while it is still the binding status,
+// so a canceled start does not override the status of a newer start of the same binding
+func (s *Service) add(ctx context.Context, cfg config.BindingConfig, logLevel string, status *Status) error {
+	binder := NewBinder()
 	err := binder.Init(ctx, cfg, s.exporter, logLevel)
 	if err != nil {
 		return err
@@ -99,9 +170,15 @@ func (s *Service) Add(ctx context.Context, cfg config.BindingConfig, logLevel st
 	if err != nil {
 		return err
 	}
+	if ctx.Err() != nil {
+		_ = binder.Stop()
+		s.bindingStatus.CompareAndDelete(cfg.Name, status)
+		return ctx.Err()
+	}
 	s.bindings.Store(cfg.Name, binder)
-	status.Ready = true
-	s.bindingStatus.Store(cfg.Name, status)
+	ready := *status
+	ready.Ready = true
+	s.bindingStatus.CompareAndSwap(cfg.Name, status, &ready)
 	return nil
 }
 
@@ -129,11 +206,26 @@ func (s *Service) Stats() []*metrics.Report {
 }
 
 func (s *Service) GetStatus() []*Status {
+	s.Lock()
+	bindings := append([]config.BindingConfig{}, s.cfg.Bindings...)
+	s.Unlock()
 	var list []*Status
-	for _, binding := range s.cfg.Bindings {
+	for _, binding := range bindings {
 		val, ok := s.bindingStatus.Load(binding.Name)
 		if ok {
-			list = append(list, val.(*Status))
+			status := *val.(*Status)
+			if binder, ok := s.bindings.Load(binding.Name); ok {
+				states := binder.(*Binder).circuitBreakerStates()
+				status.CircuitBreaker = states[""]
+				var routes []*RouteStatus
+				for _, route := range status.Routes {
+					routeStatus := *route
+					routeStatus.CircuitBreaker = states[route.Route]
+					routes = append(routes, &routeStatus)
+				}
+				status.Routes = routes
+			}
+			list = append(list, &status)
 		}
 	}
 	return list
@@ -9,11 +9,21 @@ import (
 type Status struct {
 	Binding          string            `json:"binding"`
 	Ready            bool              `json:"ready"`
+	Paused           bool              `json:"paused"`
 	SourceType       string            `json:"source_type"`
 	SourceConnection string            `json:"source_connection"`
 	SourceConfig     map[string]string `json:"source_config"`
 	TargetType       string            `json:"target_type"`
 	TargetConfig     map[string]string `json:"target_config"`
+	CircuitBreaker   string            `json:"circuit_breaker,omitempty"`
+	Routes           []*RouteStatus    `json:"routes,omitempty"`
+}
+
+type RouteStatus struct {
+	Route          string            `json:"route"`
+	TargetType     string            `json:"target_type"`
+	TargetConfig   map[string]string `json:"target_config"`
+	CircuitBreaker string            `json:"circuit_breaker,omitempty"`
 }
 
 func getSourceConnection(properties map[string]string) string {
@@ -21,13 +31,22 @@ func getSourceConnection(properties map[string]string) string {
 }
 
 func newStatus(cfg config.BindingConfig) *Status {
+	var routes []*RouteStatus
+	for _, route := range cfg.Routes {
+		routes = append(routes, &RouteStatus{
+			Route:        route.Name,
+			TargetType:   route.Target.Kind,
+			TargetConfig: config.RedactProperties(route.Target.Properties),
+		})
+	}
 	return &Status{
 		Binding:          cfg.Name,
 		Ready:            false,
 		SourceType:       cfg.Source.Kind,
 		SourceConnection: getSourceConnection(cfg.Source.Properties),
-		SourceConfig:     cfg.Source.Properties,
+		SourceConfig:     config.RedactProperties(cfg.Source.Properties),
 		TargetType:       cfg.Target.Kind,
-		TargetConfig:     cfg.Target.Properties,
+		TargetConfig:     config.RedactProperties(cfg.Target.Properties),
+		Routes:           routes,
 	}
 }
@@ -1,16 +1,26 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
+var routingModesMap = map[string]string{
+	"":      "first",
+	"first": "first",
+	"all":   "all",
+}
+
 type BindingConfig struct {
 	Name       string         `json:"name"`
 	Source     Spec           `json:"source"`
 	Target     Spec           `json:"target"`
+	Routes     []RouteConfig  `json:"routes,omitempty"`
 	Properties types.Metadata `json:"properties"`
+	// Route is set on binding configs derived for a single route, see RouteBinding
+	Route string `json:"-" mapstructure:"-"`
 }
 
 func (b BindingConfig) Validate() error {
@@ -20,8 +30,67 @@ func (b BindingConfig) Validate() error {
 	if err := b.Source.Validate(); err != nil {
 		return fmt.Errorf("binding source error, %w", err)
 	}
-	if err := b.Target.Validate(); err != nil {
-		return fmt.Errorf("binding target error, %w", err)
+	if len(b.Routes) == 0 {
+		if err := b.Target.Validate(); err != nil {
+			return fmt.Errorf("binding target error, %w", err)
+		}
+		return nil
+	}
+	if b.Target.Kind != "" {
+		return fmt.Errorf("binding cannot have both target and routes")
+	}
+	if _, err := b.Properties.ParseStringMap("routing_mode", routingModesMap); err != nil {
+		return fmt.Errorf("binding routing mode error, %w", err)
+	}
+	existedRoutes := map[string]string{}
+	for _, route := range b.Routes {
+		if err := route.Validate(); err != nil {
+			return fmt.Errorf("binding routes error, %w", err)
+		}
+		if _, ok := existedRoutes[route.Name]; ok {
+			return fmt.Errorf("duplicated route names found: %s", route.Name)
+		}
+		existedRoutes[route.Name] = route.Name
 	}
 	return nil
 }
+
+// Equal returns true when both binding configs are the same, including source, target, routes and properties
+func (b BindingConfig) Equal(other BindingConfig) bool {
+	bData, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	otherData, err := json.Marshal(other)
+	if err != nil {
+		return false
+	}
+	return string(bData) == string(otherData)
+}
+
+// RoutingMode returns "first" when a request is sent to the first matching route only, or "all" when it is sent to every matching route
+func (b BindingConfig) RoutingMode() string {
+	mode, err := b.Properties.ParseStringMap("routing_mode", routingModesMap)
+	if err != nil {
+		return "first"
+	}
+	return mode
+}
+
+// RouteBinding returns the binding config of a single route, the route target replaces the binding target and route properties override binding properties
+func (b BindingConfig) RouteBinding(route RouteConfig) BindingConfig {
+	properties := types.NewMetadata()
+	for key, value := range b.Properties {
+		properties.Set(key, value)
+	}
+	for key, value := range route.Properties {
+		properties.Set(key, value)
+	}
+	return BindingConfig{
+		Name:       b.Name,
+		Source:     b.Source,
+		Target:     route.Target,
+		Properties: properties,
+		Route:      route.Name,
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/ghodss/yaml"
@@ -24,13 +25,16 @@ const defaultApiPort = global.DefaultApiPort
 var (
 	configFile string
 	logr       = logger.NewLogger("config")
+	// lastConf is the last loaded or saved configuration, guarded by lastConfMu as the config watcher reads it
 	lastConf   *Config
+	lastConfMu sync.Mutex
 )
 
 type Config struct {
 	Bindings []BindingConfig `json:"bindings"`
 	ApiPort  int             `json:"apiPort"`
 	LogLevel string          `json:"logLevel"`
+	Tracing  TracingConfig   `json:"tracing"`
 }
 
 func SetConfigFile(filename string) {
@@ -59,6 +63,9 @@ func (c *Config) Validate() error {
 	if c.ApiPort == 0 {
 		c.ApiPort = defaultApiPort
 	}
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
 	exitedBindings := map[string]string{}
 	for _, binding := range c.Bindings {
 		if err := binding.Validate(); err != nil {
@@ -183,6 +190,7 @@ func Load(cfgCh chan *Config) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	setLastConf(cfg)
 	viper.WatchConfig()
 	viper.OnConfigChange(func(e fsnotify.Event) {
 		cfg, err := load()
@@ -190,11 +198,58 @@ func Load(cfgCh chan *Config) (*Config, error) {
 			logr.Errorf("error loading new configuration file: %s", err.Error())
 			return
 		}
-		if cfg.hash() != lastConf.hash() {
-			logr.Info("config file changed, reloading...")
-			lastConf = cfg.copy()
-			cfgCh <- cfg
+		if !swapLastConf(cfg) {
+			return
 		}
+		logr.Info("config file changed, reloading...")
+		cfgCh <- cfg
 	})
 	return cfg, err
 }
+
+func setLastConf(cfg *Config) {
+	lastConfMu.Lock()
+	defer lastConfMu.Unlock()
+	lastConf = cfg.copy()
+}
+
+// swapLastConf sets cfg as the last configuration and reports whether it differs from the previous one
+func swapLastConf(cfg *Config) bool {
+	lastConfMu.Lock()
+	defer lastConfMu.Unlock()
+	if lastConf != nil && cfg.hash() == lastConf.hash() {
+		return false
+	}
+	lastConf = cfg.copy()
+	return true
+}
+
+// Save writes the configuration back to the loaded config file, keeping its yaml or json format
+func Save(cfg *Config) error {
+	filename := viper.ConfigFileUsed()
+	if filename == "" {
+		return fmt.Errorf("no config file loaded")
+	}
+	var data []byte
+	var err error
+	if strings.HasSuffix(filename, ".json") {
+		data, err = json.MarshalIndent(cfg, "", "  ")
+	} else {
+		data, err = yaml.Marshal(cfg)
+	}
+	if err != nil {
+		return fmt.Errorf("error marshaling config, %w", err)
+	}
+	// lastConf is updated before the file is written, so the config watcher does not reload the saved configuration
+	lastConfMu.Lock()
+	defer lastConfMu.Unlock()
+	prev := lastConf
+	lastConf = cfg.copy()
+	/* #nosec */
+	err = ioutil.WriteFile(filename, data, 0o644)
+	if err != nil {
+		lastConf = prev
+		return fmt.Errorf("error saving config file, %w", err)
+	}
+	return nil
+}
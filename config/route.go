@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+var routeRuleOperatorsMap = map[string]string{
+	"":           "equals",
+	"equals":     "equals",
+	"not_equals": "not_equals",
+	"regex":      "regex",
+	"exists":     "exists",
+}
+
+type RouteRule struct {
+	Metadata string `json:"metadata,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+func (r RouteRule) Validate() error {
+	if r.Metadata == "" && r.Field == "" {
+		return fmt.Errorf("route rule must have metadata key or field path")
+	}
+	if r.Metadata != "" && r.Field != "" {
+		return fmt.Errorf("route rule cannot have both metadata key and field path")
+	}
+	if _, ok := routeRuleOperatorsMap[r.Operator]; !ok {
+		return fmt.Errorf("invalid route rule operator %s", r.Operator)
+	}
+	return nil
+}
+
+type RouteConfig struct {
+	Name       string         `json:"name"`
+	Match      []RouteRule    `json:"match"`
+	Target     Spec           `json:"target"`
+	Properties types.Metadata `json:"properties"`
+}
+
+func (r RouteConfig) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("route must have name")
+	}
+	for _, rule := range r.Match {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("route %s error, %w", r.Name, err)
+		}
+	}
+	if err := r.Target.Validate(); err != nil {
+		return fmt.Errorf("route %s target error, %w", r.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+const redactedValue = "******"
+
+var (
+	secretRefRegex    = regexp.MustCompile(`\$\{([a-zA-Z0-9_-]+):([^}]+)\}`)
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"env":   envSecretProvider{},
+		"file":  fileSecretProvider{},
+		"vault": NewVaultSecretProvider("", ""),
+	}
+	sensitiveKeys = []string{"password", "passwd", "token", "secret", "private_key", "credentials", "connection", "api_key", "access_key"}
+)
+
+// SecretProvider resolves the path part of a ${provider:path} secret reference to its value
+type SecretProvider interface {
+	Resolve(path string) (string, error)
+}
+
+// RegisterSecretProvider adds or replaces the provider used for ${name:path} references
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[name] = provider
+}
+
+func getSecretProvider(name string) (SecretProvider, bool) {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	provider, ok := secretProviders[name]
+	return provider, ok
+}
+
+type envSecretProvider struct{}
+
+func (envSecretProvider) Resolve(path string) (string, error) {
+	value, ok := os.LookupEnv(path)
+	if !ok {
+		return "", fmt.Errorf("env var %s was not found", path)
+	}
+	return value, nil
+}
+
+type fileSecretProvider struct{}
+
+func (fileSecretProvider) Resolve(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// VaultSecretProvider reads secrets from a Vault KV engine, references are in the form ${vault:secret/data/path#key}
+type VaultSecretProvider struct {
+	address string
+	token   string
+	client  *http.Client
+}
+
+// NewVaultSecretProvider creates a Vault provider, empty address and token are taken from VAULT_ADDR and VAULT_TOKEN env vars
+func NewVaultSecretProvider(address, token string) *VaultSecretProvider {
+	return &VaultSecretProvider{
+		address: address,
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (v *VaultSecretProvider) Resolve(path string) (string, error) {
+	address := v.address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	token := v.token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if address == "" {
+		return "", fmt.Errorf("vault address is not set")
+	}
+	secretPath, key := path, "value"
+	if idx := strings.LastIndex(path, "#"); idx >= 0 {
+		secretPath, key = path[:idx], path[idx+1:]
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s", strings.TrimRight(address, "/"), strings.TrimLeft(secretPath, "/")), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error reading vault secret %s, %w", secretPath, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error reading vault secret %s, status %s", secretPath, resp.Status)
+	}
+	result := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("error parsing vault secret %s, %w", secretPath, err)
+	}
+	data := result.Data
+	// kv version 2 engine nests the secret values in data.data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s was not found in vault secret %s", key, secretPath)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// IsSecretRef returns true when the value contains a secret reference
+func IsSecretRef(value string) bool {
+	return secretRefRegex.MatchString(value)
+}
+
+// ResolveSecretRefs replaces all the secret references in value, resolved values are redacted from the logs
+func ResolveSecretRefs(value string) (string, error) {
+	var resolveErr error
+	resolved := secretRefRegex.ReplaceAllStringFunc(value, func(ref string) string {
+		if resolveErr != nil {
+			return ref
+		}
+		parts := secretRefRegex.FindStringSubmatch(ref)
+		provider, ok := getSecretProvider(parts[1])
+		if !ok {
+			resolveErr = fmt.Errorf("unknown secret provider %s", parts[1])
+			return ref
+		}
+		secret, err := provider.Resolve(parts[2])
+		if err != nil {
+			resolveErr = fmt.Errorf("error resolving secret %s, %w", ref, err)
+			return ref
+		}
+		logger.AddSecret(secret)
+		return secret
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return resolved, nil
+}
+
+// ResolveSecrets returns a copy of the spec with all the secret references in its properties resolved
+func (s Spec) ResolveSecrets() (Spec, error) {
+	resolved := Spec{
+		Name:       s.Name,
+		Kind:       s.Kind,
+		Properties: types.NewMetadata(),
+	}
+	for key, value := range s.Properties {
+		if !IsSecretRef(value) {
+			resolved.Properties[key] = value
+			continue
+		}
+		secret, err := ResolveSecretRefs(value)
+		if err != nil {
+			return Spec{}, fmt.Errorf("error resolving property %s, %w", key, err)
+		}
+		resolved.Properties[key] = secret
+	}
+	return resolved, nil
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactProperties returns a copy of the properties where plain values of sensitive keys are masked, secret references are kept as is
+func RedactProperties(properties map[string]string) map[string]string {
+	if properties == nil {
+		return nil
+	}
+	redacted := map[string]string{}
+	for key, value := range properties {
+		if value != "" && isSensitiveKey(key) && !IsSecretRef(value) {
+			redacted[key] = redactedValue
+			continue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
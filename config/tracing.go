@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+)
+
+const defaultTracingServiceName = "kubemq-targets"
+
+type TracingConfig struct {
+	Enabled     bool    `json:"enabled"`
+	Endpoint    string  `json:"endpoint,omitempty"`
+	Insecure    bool    `json:"insecure,omitempty"`
+	ServiceName string  `json:"serviceName,omitempty"`
+	SampleRatio float64 `json:"sampleRatio,omitempty"`
+}
+
+func (t *TracingConfig) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.ServiceName == "" {
+		t.ServiceName = defaultTracingServiceName
+	}
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		return fmt.Errorf("invalid tracing sample ratio %v, must be between 0 and 1", t.SampleRatio)
+	}
+	if t.SampleRatio == 0 {
+		t.SampleRatio = 1
+	}
+	return nil
+}
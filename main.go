@@ -20,6 +20,7 @@ import (
 	"github.com/kubemq-io/kubemq-targets/pkg/browser"
 	"github.com/kubemq-io/kubemq-targets/pkg/builder"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/sources"
 	"github.com/kubemq-io/kubemq-targets/targets"
 )
@@ -91,6 +92,13 @@ func runInteractive(serviceExit chan bool) error {
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	tracingProvider, err := tracing.Start(ctx, cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tracingProvider.Stop(context.Background())
+	}()
 	bindingsService, err := binding.New()
 	if err != nil {
 		return err
@@ -111,22 +119,27 @@ func runInteractive(serviceExit chan bool) error {
 			if err != nil {
 				return fmt.Errorf("error on validation new config file: %s", err.Error())
 			}
-			bindingsService.Stop()
-			err = bindingsService.Start(ctx, newConfig)
-			if err != nil {
-				return fmt.Errorf("error on restarting service with new config file: %s", err.Error())
-			}
-			if apiServer != nil {
-				err = apiServer.Stop()
+			if newConfig.Tracing != cfg.Tracing {
+				_ = tracingProvider.Stop(ctx)
+				tracingProvider, err = tracing.Start(ctx, newConfig.Tracing)
 				if err != nil {
-					return fmt.Errorf("error on shutdown api server: %s", err.Error())
+					return fmt.Errorf("error on start tracing: %s", err.Error())
 				}
 			}
-
-			apiServer, err = api.Start(ctx, newConfig.ApiPort, bindingsService)
-			if err != nil {
-				return fmt.Errorf("error on start api server: %s", err.Error())
+			bindingsService.Update(newConfig)
+			if newConfig.ApiPort != cfg.ApiPort {
+				if apiServer != nil {
+					err = apiServer.Stop()
+					if err != nil {
+						return fmt.Errorf("error on shutdown api server: %s", err.Error())
+					}
+				}
+				apiServer, err = api.Start(ctx, newConfig.ApiPort, bindingsService)
+				if err != nil {
+					return fmt.Errorf("error on start api server: %s", err.Error())
+				}
 			}
+			cfg = newConfig
 		case <-gracefulShutdown:
 			_ = apiServer.Stop()
 			bindingsService.Stop()
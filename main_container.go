@@ -15,6 +15,7 @@ import (
 	"github.com/kubemq-io/kubemq-targets/binding"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 )
 
 var version = ""
@@ -40,6 +41,13 @@ func run() error {
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	tracingProvider, err := tracing.Start(ctx, cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tracingProvider.Stop(context.Background())
+	}()
 	bindingsService, err := binding.New()
 	if err != nil {
 		return err
@@ -60,22 +68,27 @@ func run() error {
 			if err != nil {
 				return fmt.Errorf("error on validation new config file: %s", err.Error())
 			}
-			bindingsService.Stop()
-			err = bindingsService.Start(ctx, newConfig)
-			if err != nil {
-				return fmt.Errorf("error on restarting service with new config file: %s", err.Error())
-			}
-			if apiServer != nil {
-				err = apiServer.Stop()
+			if newConfig.Tracing != cfg.Tracing {
+				_ = tracingProvider.Stop(ctx)
+				tracingProvider, err = tracing.Start(ctx, newConfig.Tracing)
 				if err != nil {
-					return fmt.Errorf("error on shutdown api server: %s", err.Error())
+					return fmt.Errorf("error on start tracing: %s", err.Error())
 				}
 			}
-
-			apiServer, err = api.Start(ctx, newConfig.ApiPort, bindingsService)
-			if err != nil {
-				return fmt.Errorf("error on start api server: %s", err.Error())
+			bindingsService.Update(newConfig)
+			if newConfig.ApiPort != cfg.ApiPort {
+				if apiServer != nil {
+					err = apiServer.Stop()
+					if err != nil {
+						return fmt.Errorf("error on shutdown api server: %s", err.Error())
+					}
+				}
+				apiServer, err = api.Start(ctx, newConfig.ApiPort, bindingsService)
+				if err != nil {
+					return fmt.Errorf("error on start api server: %s", err.Error())
+				}
 			}
+			cfg = newConfig
 		case <-gracefulShutdown:
 			_ = apiServer.Stop()
 			bindingsService.Stop()
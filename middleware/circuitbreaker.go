@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/pkg/circuitbreaker"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/metrics"
+)
+
+type CircuitBreakerMiddleware struct {
+	breaker    *circuitbreaker.Breaker
+	targetKind string
+}
+
+func NewCircuitBreakerMiddleware(cfg config.BindingConfig, exporter *metrics.Exporter, log *logger.Logger) (*CircuitBreakerMiddleware, error) {
+	meta := cfg.Properties
+	ratio, err := meta.ParseFloat64WithRange("circuit_breaker_failure_ratio", 0, 0, 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid circuit breaker failure ratio value, %w", err)
+	}
+	windowSize, err := meta.ParseIntWithRange("circuit_breaker_window_size", 10, 1, math.MaxInt16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid circuit breaker window size value, %w", err)
+	}
+	openDuration, err := meta.ParseIntWithRange("circuit_breaker_open_duration_seconds", 30, 1, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid circuit breaker open duration seconds value, %w", err)
+	}
+	probes, err := meta.ParseIntWithRange("circuit_breaker_half_open_probes", 1, 1, math.MaxInt16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid circuit breaker half open probes value, %w", err)
+	}
+	cb := &CircuitBreakerMiddleware{
+		targetKind: cfg.Target.Kind,
+	}
+	if ratio == 0 {
+		return cb, nil
+	}
+	report := &metrics.Report{
+		Binding:    cfg.Name,
+		SourceKind: cfg.Source.Kind,
+		TargetKind: cfg.Target.Kind,
+		Route:      cfg.Route,
+	}
+	cb.breaker = circuitbreaker.New(circuitbreaker.Settings{
+		FailureRatio:   ratio,
+		WindowSize:     windowSize,
+		OpenDuration:   time.Duration(openDuration) * time.Second,
+		HalfOpenProbes: probes,
+		OnStateChange: func(from, to circuitbreaker.State) {
+			if log != nil {
+				log.Infof("circuit breaker state changed from %s to %s", from, to)
+			}
+			if exporter != nil {
+				exporter.ReportCircuitBreakerTransition(report, from.String(), to.String(), float64(to))
+			}
+		},
+	})
+	return cb, nil
+}
+
+// State returns the current circuit breaker state or an empty string when disabled
+func (cb *CircuitBreakerMiddleware) State() string {
+	if cb == nil || cb.breaker == nil {
+		return ""
+	}
+	return cb.breaker.State().String()
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/pkg/dedup"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+var dedupKeySourcesMap = map[string]string{
+	"":         "",
+	"metadata": "metadata",
+	"body":     "body",
+}
+
+var dedupStoresMap = map[string]string{
+	"":          "memory",
+	"memory":    "memory",
+	"redis":     "redis",
+	"memcached": "memcached",
+}
+
+// maxMemcachedTTL is the longest relative expiration accepted by memcached
+const maxMemcachedTTL = 30 * 24 * 60 * 60
+
+type DedupMiddleware struct {
+	store       dedup.Store
+	keySource   string
+	metadataKey string
+	keyPrefix   string
+	ttl         time.Duration
+	log         *logger.Logger
+}
+
+func NewDedupMiddleware(ctx context.Context, cfg config.BindingConfig, log *logger.Logger) (*DedupMiddleware, error) {
+	meta := cfg.Properties
+	dm := &DedupMiddleware{
+		keyPrefix: fmt.Sprintf("kubemq-targets:dedup:%s:", cfg.Name),
+		log:       log,
+	}
+	var err error
+	dm.keySource, err = meta.ParseStringMap("dedup_key_source", dedupKeySourcesMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dedup key source value, %w", err)
+	}
+	if dm.keySource == "" {
+		return dm, nil
+	}
+	if dm.keySource == "metadata" {
+		dm.metadataKey, err = meta.MustParseString("dedup_key_metadata")
+		if err != nil {
+			return nil, fmt.Errorf("invalid dedup key metadata value, %w", err)
+		}
+	}
+	storeKind, err := meta.ParseStringMap("dedup_store", dedupStoresMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dedup store value, %w", err)
+	}
+	maxTTL := math.MaxInt32
+	if storeKind == "memcached" {
+		maxTTL = maxMemcachedTTL
+	}
+	ttl, err := meta.ParseIntWithRange("dedup_ttl_seconds", 3600, 1, maxTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dedup ttl seconds value, %w", err)
+	}
+	dm.ttl = time.Duration(ttl) * time.Second
+	switch storeKind {
+	case "redis":
+		url, err := meta.MustParseString("dedup_redis_url")
+		if err != nil {
+			return nil, fmt.Errorf("invalid dedup redis url value, %w", err)
+		}
+		url, err = config.ResolveSecretRefs(url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dedup redis url value, %w", err)
+		}
+		dm.store, err = dedup.NewRedisStore(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to dedup store, %w", err)
+		}
+	case "memcached":
+		hosts, err := meta.MustParseStringList("dedup_memcached_hosts")
+		if err != nil {
+			return nil, fmt.Errorf("invalid dedup memcached hosts value, %w", err)
+		}
+		dm.store, err = dedup.NewMemcachedStore(hosts...)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to dedup store, %w", err)
+		}
+	default:
+		maxKeys, err := meta.ParseIntWithRange("dedup_max_keys", 10000, 1, math.MaxInt32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dedup max keys value, %w", err)
+		}
+		dm.store = dedup.NewMemoryStore(maxKeys)
+	}
+	return dm, nil
+}
+
+// key returns the idempotency key of the request, an empty key means the request is not deduplicated
+func (dm *DedupMiddleware) key(request *types.Request) string {
+	switch dm.keySource {
+	case "metadata":
+		if request.Metadata == nil {
+			return ""
+		}
+		value := strings.TrimSpace(request.Metadata[dm.metadataKey])
+		if value == "" {
+			return ""
+		}
+		return dm.keyPrefix + value
+	case "body":
+		// the metadata is hashed with the data, requests which carry their target key in the metadata often have no data
+		h := sha256.New()
+		meta, _ := json.Marshal(request.Metadata)
+		h.Write(meta)
+		h.Write(request.Data)
+		return dm.keyPrefix + hex.EncodeToString(h.Sum(nil))
+	}
+	return ""
+}
+
+func (dm *DedupMiddleware) lookup(ctx context.Context, key string) *types.Response {
+	data, ok, err := dm.store.Get(ctx, key)
+	if err != nil {
+		if dm.log != nil {
+			dm.log.Errorf("error reading dedup store, %s", err.Error())
+		}
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	resp, err := types.ParseResponse(data)
+	if err != nil {
+		return nil
+	}
+	return resp
+}
+
+func (dm *DedupMiddleware) remember(ctx context.Context, key string, resp *types.Response) {
+	if err := dm.store.Set(ctx, key, resp.MarshalBinary(), dm.ttl); err != nil && dm.log != nil {
+		dm.log.Errorf("error writing dedup store, %s", err.Error())
+	}
+}
+
+func (dm *DedupMiddleware) Close() error {
+	if dm == nil || dm.store == nil {
+		return nil
+	}
+	return dm.store.Close()
+}
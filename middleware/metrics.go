@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/pkg/metrics"
@@ -10,19 +11,25 @@ import (
 type MetricsMiddleware struct {
 	exporter     *metrics.Exporter
 	metricReport *metrics.Report
+	baseReport   *metrics.Report
 }
 
 func NewMetricsMiddleware(cfg config.BindingConfig, exporter *metrics.Exporter) (*MetricsMiddleware, error) {
 	if exporter == nil {
 		return nil, fmt.Errorf("no valid exporter found")
 	}
+	key := fmt.Sprintf("%s-%s-%s", cfg.Name, cfg.Source.Kind, cfg.Target.Kind)
+	if cfg.Route != "" {
+		key = fmt.Sprintf("%s-%s", key, cfg.Route)
+	}
 	m := &MetricsMiddleware{
 		exporter: exporter,
 		metricReport: &metrics.Report{
-			Key:            fmt.Sprintf("%s-%s-%s", cfg.Name, cfg.Source.Kind, cfg.Target.Kind),
+			Key:            key,
 			Binding:        cfg.Name,
 			SourceKind:     cfg.Source.Kind,
 			TargetKind:     cfg.Target.Kind,
+			Route:          cfg.Route,
 			RequestCount:   0,
 			RequestVolume:  0,
 			ResponseCount:  0,
@@ -30,6 +37,7 @@ func NewMetricsMiddleware(cfg config.BindingConfig, exporter *metrics.Exporter)
 			ErrorsCount:    0,
 		},
 	}
+	m.baseReport = m.metricReport.Clone()
 	return m, nil
 }
 
@@ -40,3 +48,11 @@ func (m *MetricsMiddleware) clearReport() {
 	m.metricReport.RequestVolume = 0
 	m.metricReport.RequestCount = 0
 }
+
+func (m *MetricsMiddleware) reportRetry() {
+	m.exporter.ReportRetry(m.baseReport)
+}
+
+func (m *MetricsMiddleware) reportRateLimitWait(d time.Duration) {
+	m.exporter.ReportRateLimitWait(m.baseReport, d)
+}
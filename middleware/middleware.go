@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/kubemq-io/kubemq-targets/pkg/retry"
 	"github.com/kubemq-io/kubemq-targets/types"
@@ -69,7 +71,11 @@ func Log(log *LogMiddleware) MiddlewareFunc {
 func RateLimiter(rl *RateLimitMiddleware) MiddlewareFunc {
 	return func(df Middleware) Middleware {
 		return DoFunc(func(ctx context.Context, request *types.Request) (*types.Response, error) {
+			start := time.Now()
 			rl.Take()
+			if rl.metrics != nil {
+				rl.metrics.reportRateLimitWait(time.Since(start))
+			}
 			return df.Do(ctx, request)
 		})
 	}
@@ -92,10 +98,30 @@ func Retry(r *RetryMiddleware) MiddlewareFunc {
 	}
 }
 
+func CircuitBreaker(cb *CircuitBreakerMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		if cb.breaker == nil {
+			return df
+		}
+		return DoFunc(func(ctx context.Context, request *types.Request) (*types.Response, error) {
+			if err := cb.breaker.Allow(); err != nil {
+				return nil, fmt.Errorf("request to target %s rejected, %w", cb.targetKind, err)
+			}
+			resp, err := df.Do(ctx, request)
+			cb.breaker.Done(err == nil)
+			return resp, err
+		})
+	}
+}
+
 func Metric(m *MetricsMiddleware) MiddlewareFunc {
 	return func(df Middleware) Middleware {
 		return DoFunc(func(ctx context.Context, request *types.Request) (*types.Response, error) {
+			m.exporter.ReportInFlight(m.baseReport, 1)
+			start := time.Now()
 			resp, err := df.Do(ctx, request)
+			m.exporter.ReportDuration(m.baseReport, time.Since(start))
+			m.exporter.ReportInFlight(m.baseReport, -1)
 			m.clearReport()
 			if request != nil {
 				m.metricReport.RequestVolume = request.Size()
@@ -125,6 +151,53 @@ func Metadata(m *MetadataMiddleware) MiddlewareFunc {
 	}
 }
 
+func Transform(tm *TransformMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		if tm.isEmpty() {
+			return df
+		}
+		return DoFunc(func(ctx context.Context, request *types.Request) (*types.Response, error) {
+			reqContext, err := tm.transformRequest(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := df.Do(ctx, request)
+			if err != nil || resp == nil {
+				return resp, err
+			}
+			if err := tm.transformResponse(ctx, reqContext, resp); err != nil {
+				return nil, err
+			}
+			return resp, nil
+		})
+	}
+}
+
+func Dedup(dm *DedupMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		if dm.store == nil {
+			return df
+		}
+		return DoFunc(func(ctx context.Context, request *types.Request) (*types.Response, error) {
+			key := dm.key(request)
+			if key == "" {
+				return df.Do(ctx, request)
+			}
+			if resp := dm.lookup(ctx, key); resp != nil {
+				if resp.Metadata == nil {
+					resp.Metadata = types.NewMetadata()
+				}
+				return resp.SetMetadataKeyValue("deduplicated", "true"), nil
+			}
+			resp, err := df.Do(ctx, request)
+			if err == nil && resp != nil && !resp.IsError {
+				dm.remember(ctx, key, resp)
+			}
+			return resp, err
+		})
+	}
+}
+
 func Chain(md Middleware, list ...MiddlewareFunc) Middleware {
 	chain := md
 	for _, middleware := range list {
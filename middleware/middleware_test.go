@@ -12,6 +12,12 @@ import (
 	"github.com/kubemq-io/kubemq-targets/pkg/metrics"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type mockTarget struct {
@@ -564,3 +570,523 @@ func TestClient_Chain(t *testing.T) {
 	d := time.Since(start)
 	require.GreaterOrEqual(t, d.Milliseconds(), 2*time.Second.Milliseconds())
 }
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name          string
+		mock          *mockTarget
+		meta          types.Metadata
+		requests      int
+		wantExecuted  int
+		wantState     string
+		wantErr       bool
+		wantCreateErr bool
+	}{
+		{
+			name: "disabled",
+			mock: &mockTarget{
+				request:  types.NewRequest(),
+				response: nil,
+				err:      fmt.Errorf("some-error"),
+				delay:    0,
+				executed: 0,
+			},
+			meta:         map[string]string{},
+			requests:     10,
+			wantExecuted: 10,
+			wantState:    "",
+			wantErr:      true,
+		},
+		{
+			name: "open after failures",
+			mock: &mockTarget{
+				request:  types.NewRequest(),
+				response: nil,
+				err:      fmt.Errorf("some-error"),
+				delay:    0,
+				executed: 0,
+			},
+			meta: map[string]string{
+				"circuit_breaker_failure_ratio":         "0.5",
+				"circuit_breaker_window_size":           "4",
+				"circuit_breaker_open_duration_seconds": "60",
+			},
+			requests:     10,
+			wantExecuted: 4,
+			wantState:    "open",
+			wantErr:      true,
+		},
+		{
+			name: "stays closed on success",
+			mock: &mockTarget{
+				request:  types.NewRequest(),
+				response: types.NewResponse(),
+				err:      nil,
+				delay:    0,
+				executed: 0,
+			},
+			meta: map[string]string{
+				"circuit_breaker_failure_ratio": "0.5",
+				"circuit_breaker_window_size":   "4",
+			},
+			requests:     10,
+			wantExecuted: 10,
+			wantState:    "closed",
+			wantErr:      false,
+		},
+		{
+			name: "bad failure ratio",
+			mock: &mockTarget{},
+			meta: map[string]string{
+				"circuit_breaker_failure_ratio": "1.5",
+			},
+			wantCreateErr: true,
+		},
+		{
+			name: "bad half open probes",
+			mock: &mockTarget{},
+			meta: map[string]string{
+				"circuit_breaker_failure_ratio":    "0.5",
+				"circuit_breaker_half_open_probes": "-1",
+			},
+			wantCreateErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			cb, err := NewCircuitBreakerMiddleware(config.BindingConfig{
+				Name:       "b-1",
+				Target:     config.Spec{Kind: "tk"},
+				Properties: tt.meta,
+			}, nil, nil)
+			if tt.wantCreateErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			md := Chain(tt.mock, CircuitBreaker(cb))
+			for i := 0; i < tt.requests; i++ {
+				_, err = md.Do(ctx, tt.mock.request)
+				if tt.wantErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+			require.EqualValues(t, tt.wantExecuted, tt.mock.executed)
+			require.EqualValues(t, tt.wantState, cb.State())
+		})
+	}
+}
+
+func TestClient_Transform(t *testing.T) {
+	tests := []struct {
+		name          string
+		mock          *mockTarget
+		meta          types.Metadata
+		req           *types.Request
+		wantReqMeta   types.Metadata
+		wantReqData   []byte
+		wantResp      *types.Response
+		wantErr       bool
+		wantCreateErr bool
+	}{
+		{
+			name: "no transform",
+			mock: &mockTarget{
+				response: types.NewResponse().SetData([]byte("data")),
+			},
+			meta:        map[string]string{},
+			req:         types.NewRequest().SetMetadataKeyValue("method", "set").SetData([]byte("data")),
+			wantReqMeta: map[string]string{"method": "set"},
+			wantReqData: []byte("data"),
+			wantResp:    types.NewResponse().SetData([]byte("data")),
+		},
+		{
+			name: "request metadata and data",
+			mock: &mockTarget{
+				response: types.NewResponse().SetData([]byte("data")),
+			},
+			meta: map[string]string{
+				"transform_request_metadata": `{"key":"$.id","method":"set","item":"{{ .Metadata.prefix }}-{{ .Body.id }}"}`,
+				"transform_request_data":     `$.payload`,
+			},
+			req:         types.NewRequest().SetMetadataKeyValue("prefix", "p").SetData([]byte(`{"id":"some-id","payload":{"a":1}}`)),
+			wantReqMeta: map[string]string{"prefix": "p", "key": "some-id", "method": "set", "item": "p-some-id"},
+			wantReqData: []byte(`{"a":1}`),
+			wantResp:    types.NewResponse().SetData([]byte("data")),
+		},
+		{
+			name: "response metadata and data",
+			mock: &mockTarget{
+				response: types.NewResponse().SetMetadataKeyValue("result", "ok").SetData([]byte(`{"count":2}`)),
+			},
+			meta: map[string]string{
+				"transform_response_metadata": `{"count":"$.count","key":"{{ .Request.Metadata.key }}"}`,
+				"transform_response_data":     `{"result":"{{ .Metadata.result }}","total":{{ jsonpath "$.count" .Body }}}`,
+			},
+			req:         types.NewRequest().SetMetadataKeyValue("key", "some-key"),
+			wantReqMeta: map[string]string{"key": "some-key"},
+			wantResp: types.NewResponse().
+				SetMetadataKeyValue("result", "ok").
+				SetMetadataKeyValue("count", "2").
+				SetMetadataKeyValue("key", "some-key").
+				SetData([]byte(`{"result":"ok","total":2}`)),
+		},
+		{
+			name: "response with transformed request",
+			mock: &mockTarget{
+				response: types.NewResponse().SetData([]byte(`{"count":2}`)),
+			},
+			meta: map[string]string{
+				"transform_request_metadata":  `{"key":"$.id"}`,
+				"transform_request_data":      `$.payload`,
+				"transform_response_metadata": `{"key":"{{ .Request.Metadata.key }}","value":"{{ .Request.Body.a }}"}`,
+			},
+			req:         types.NewRequest().SetData([]byte(`{"id":"some-id","payload":{"a":1}}`)),
+			wantReqMeta: map[string]string{"key": "some-id"},
+			wantReqData: []byte(`{"a":1}`),
+			wantResp: types.NewResponse().
+				SetMetadataKeyValue("key", "some-id").
+				SetMetadataKeyValue("value", "1").
+				SetData([]byte(`{"count":2}`)),
+		},
+		{
+			name: "jsonpath on invalid json",
+			mock: &mockTarget{
+				response: types.NewResponse(),
+			},
+			meta: map[string]string{
+				"transform_request_metadata": `{"key":"$.id"}`,
+			},
+			req:         types.NewRequest().SetData([]byte("not-json")),
+			wantReqMeta: map[string]string{},
+			wantReqData: []byte("not-json"),
+			wantErr:     true,
+		},
+		{
+			name: "bad template",
+			mock: &mockTarget{},
+			meta: map[string]string{
+				"transform_request_data": `{{ .Body.id `,
+			},
+			wantCreateErr: true,
+		},
+		{
+			name: "bad metadata map",
+			mock: &mockTarget{},
+			meta: map[string]string{
+				"transform_request_metadata": `not-a-map`,
+			},
+			wantCreateErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			tm, err := NewTransformMiddleware(tt.meta)
+			if tt.wantCreateErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			md := Chain(tt.mock, Transform(tm))
+			resp, err := md.Do(ctx, tt.req)
+			require.EqualValues(t, tt.wantReqMeta, tt.req.Metadata)
+			require.EqualValues(t, tt.wantReqData, tt.req.Data)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.EqualValues(t, 0, tt.mock.executed)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tt.wantResp, resp)
+		})
+	}
+}
+
+func TestClient_Router(t *testing.T) {
+	newRoutes := func(t *testing.T, targets map[string]*mockTarget, cfgs []config.RouteConfig) []*Route {
+		var routes []*Route
+		for _, cfg := range cfgs {
+			route, err := NewRoute(cfg, targets[cfg.Name])
+			require.NoError(t, err)
+			routes = append(routes, route)
+		}
+		return routes
+	}
+	routeCfgs := []config.RouteConfig{
+		{
+			Name:  "orders",
+			Match: []config.RouteRule{{Metadata: "type", Value: "order"}},
+		},
+		{
+			Name:  "big-orders",
+			Match: []config.RouteRule{{Field: "amount", Operator: "regex", Value: "^[0-9]{4,}$"}},
+		},
+		{
+			Name:  "users",
+			Match: []config.RouteRule{{Field: "$.user.id", Operator: "exists"}},
+		},
+	}
+	tests := []struct {
+		name         string
+		mode         string
+		req          *types.Request
+		wantExecuted map[string]int
+		wantRoute    string
+	}{
+		{
+			name:         "first - metadata match",
+			mode:         "first",
+			req:          types.NewRequest().SetMetadataKeyValue("type", "order").SetData([]byte(`{"amount":10000}`)),
+			wantExecuted: map[string]int{"orders": 1, "big-orders": 0, "users": 0},
+			wantRoute:    "orders",
+		},
+		{
+			name:         "first - field match",
+			mode:         "first",
+			req:          types.NewRequest().SetData([]byte(`{"user":{"id":"u1"}}`)),
+			wantExecuted: map[string]int{"orders": 0, "big-orders": 0, "users": 1},
+			wantRoute:    "users",
+		},
+		{
+			name:         "all - multiple matches",
+			mode:         "all",
+			req:          types.NewRequest().SetMetadataKeyValue("type", "order").SetData([]byte(`{"amount":10000,"user":{"id":"u1"}}`)),
+			wantExecuted: map[string]int{"orders": 1, "big-orders": 1, "users": 1},
+			wantRoute:    "orders,big-orders,users",
+		},
+		{
+			name:         "no match",
+			mode:         "all",
+			req:          types.NewRequest().SetMetadataKeyValue("type", "invoice").SetData([]byte(`{"amount":10}`)),
+			wantExecuted: map[string]int{"orders": 0, "big-orders": 0, "users": 0},
+			wantRoute:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			targets := map[string]*mockTarget{}
+			for _, cfg := range routeCfgs {
+				targets[cfg.Name] = &mockTarget{response: types.NewResponse().SetData([]byte(`"ok"`))}
+			}
+			rm := NewRouterMiddleware(tt.mode, newRoutes(t, targets, routeCfgs))
+			resp, err := rm.Do(ctx, tt.req)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+			for name, executed := range tt.wantExecuted {
+				require.EqualValues(t, executed, targets[name].executed, name)
+			}
+			require.EqualValues(t, tt.wantRoute, resp.Metadata.Get("route"))
+		})
+	}
+}
+
+func TestClient_Router_Failures(t *testing.T) {
+	routeCfgs := []config.RouteConfig{
+		{
+			Name:  "orders",
+			Match: []config.RouteRule{{Metadata: "type", Value: "order"}},
+		},
+		{
+			Name:  "archive",
+			Match: []config.RouteRule{{Metadata: "type", Operator: "exists"}},
+		},
+	}
+	tests := []struct {
+		name           string
+		errors         map[string]error
+		wantErr        bool
+		wantFailed     string
+		wantRouteError map[string]bool
+	}{
+		{
+			name:           "partial failure",
+			errors:         map[string]error{"archive": fmt.Errorf("some-error")},
+			wantErr:        false,
+			wantFailed:     "archive",
+			wantRouteError: map[string]bool{"orders": false, "archive": true},
+		},
+		{
+			name:    "all routes failed",
+			errors:  map[string]error{"orders": fmt.Errorf("some-error"), "archive": fmt.Errorf("some-error")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			targets := map[string]*mockTarget{}
+			var routes []*Route
+			for _, cfg := range routeCfgs {
+				targets[cfg.Name] = &mockTarget{response: types.NewResponse().SetData([]byte(`"ok"`)), err: tt.errors[cfg.Name]}
+				route, err := NewRoute(cfg, targets[cfg.Name])
+				require.NoError(t, err)
+				routes = append(routes, route)
+			}
+			rm := NewRouterMiddleware("all", routes)
+			resp, err := rm.Do(ctx, types.NewRequest().SetMetadataKeyValue("type", "order"))
+			for name := range targets {
+				require.EqualValues(t, 1, targets[name].executed, name)
+			}
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, "orders,archive", resp.Metadata.Get("route"))
+			require.EqualValues(t, tt.wantFailed, resp.Metadata.Get("failed_routes"))
+			var results []*routeResponse
+			require.NoError(t, json.Unmarshal(resp.Data, &results))
+			require.Len(t, results, len(routeCfgs))
+			for _, result := range results {
+				require.EqualValues(t, tt.wantRouteError[result.Route], result.IsError, result.Route)
+			}
+		})
+	}
+}
+
+func TestClient_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	mock := &mockTarget{
+		response: types.NewResponse().SetError(fmt.Errorf("some-error")),
+	}
+	meta, err := NewMetadataMiddleware(map[string]string{"key": "value"})
+	require.NoError(t, err)
+	md := Chain(mock, TargetSpan("target.http"), Span("metadata", Metadata(meta)), BindingSpan("some-binding", "kubemq.queue"))
+	parentTraceId := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := types.NewRequest().SetMetadataKeyValue("traceparent", fmt.Sprintf("00-%s-00f067aa0ba902b7-01", parentTraceId))
+	_, err = md.Do(ctx, req)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	names := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		require.EqualValues(t, parentTraceId, span.SpanContext().TraceID().String())
+		names[span.Name()] = span
+	}
+	require.Contains(t, names, "target target.http")
+	require.Contains(t, names, "middleware metadata")
+	require.Contains(t, names, "binding some-binding")
+	require.Equal(t, codes.Error, names["target target.http"].Status().Code)
+	require.Equal(t, names["binding some-binding"].SpanContext().SpanID(), names["middleware metadata"].Parent().SpanID())
+	require.Equal(t, names["middleware metadata"].SpanContext().SpanID(), names["target target.http"].Parent().SpanID())
+	require.Contains(t, req.Metadata.Get("traceparent"), names["binding some-binding"].SpanContext().SpanID().String())
+}
+
+func TestClient_Dedup(t *testing.T) {
+	tests := []struct {
+		name          string
+		meta          types.Metadata
+		mock          *mockTarget
+		reqs          []*types.Request
+		wantExecuted  int
+		wantCreateErr bool
+	}{
+		{
+			name: "disabled",
+			meta: map[string]string{},
+			mock: &mockTarget{response: types.NewResponse().SetData([]byte("data"))},
+			reqs: []*types.Request{
+				types.NewRequest().SetMetadataKeyValue("id", "1"),
+				types.NewRequest().SetMetadataKeyValue("id", "1"),
+			},
+			wantExecuted: 2,
+		},
+		{
+			name: "metadata key",
+			meta: map[string]string{"dedup_key_source": "metadata", "dedup_key_metadata": "id"},
+			mock: &mockTarget{response: types.NewResponse().SetData([]byte("data"))},
+			reqs: []*types.Request{
+				types.NewRequest().SetMetadataKeyValue("id", "1"),
+				types.NewRequest().SetMetadataKeyValue("id", "1"),
+				types.NewRequest().SetMetadataKeyValue("id", "2"),
+				types.NewRequest(),
+			},
+			wantExecuted: 3,
+		},
+		{
+			name: "body hash",
+			meta: map[string]string{"dedup_key_source": "body"},
+			mock: &mockTarget{response: types.NewResponse().SetData([]byte("data"))},
+			reqs: []*types.Request{
+				types.NewRequest().SetData([]byte("a")),
+				types.NewRequest().SetData([]byte("a")),
+				types.NewRequest().SetData([]byte("b")),
+			},
+			wantExecuted: 2,
+		},
+		{
+			name: "body hash with metadata",
+			meta: map[string]string{"dedup_key_source": "body"},
+			mock: &mockTarget{response: types.NewResponse().SetData([]byte("data"))},
+			reqs: []*types.Request{
+				types.NewRequest().SetMetadataKeyValue("method", "get").SetMetadataKeyValue("key", "a"),
+				types.NewRequest().SetMetadataKeyValue("key", "a").SetMetadataKeyValue("method", "get"),
+				types.NewRequest().SetMetadataKeyValue("method", "get").SetMetadataKeyValue("key", "b"),
+				types.NewRequest().SetMetadataKeyValue("method", "set").SetData([]byte("a")),
+				types.NewRequest().SetMetadataKeyValue("method", "delete").SetData([]byte("a")),
+			},
+			wantExecuted: 4,
+		},
+		{
+			name: "error responses are not remembered",
+			meta: map[string]string{"dedup_key_source": "body"},
+			mock: &mockTarget{err: fmt.Errorf("some-error")},
+			reqs: []*types.Request{
+				types.NewRequest().SetData([]byte("a")),
+				types.NewRequest().SetData([]byte("a")),
+			},
+			wantExecuted: 2,
+		},
+		{
+			name:          "missing metadata key",
+			meta:          map[string]string{"dedup_key_source": "metadata"},
+			wantCreateErr: true,
+		},
+		{
+			name:          "bad store",
+			meta:          map[string]string{"dedup_key_source": "body", "dedup_store": "bad-store"},
+			wantCreateErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			dm, err := NewDedupMiddleware(ctx, config.BindingConfig{Name: "b", Properties: tt.meta}, nil)
+			if tt.wantCreateErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			md := Chain(tt.mock, Dedup(dm))
+			deduplicated := 0
+			for _, req := range tt.reqs {
+				resp, _ := md.Do(ctx, req)
+				if resp != nil && resp.Metadata.Get("deduplicated") == "true" {
+					require.EqualValues(t, "data", string(resp.Data))
+					deduplicated++
+				}
+			}
+			require.EqualValues(t, tt.wantExecuted, tt.mock.executed)
+			require.EqualValues(t, len(tt.reqs)-tt.wantExecuted, deduplicated)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 type RateLimitMiddleware struct {
 	rateLimiter ratelimit.Limiter
+	metrics     *MetricsMiddleware
 }
 
 func NewRateLimitMiddleware(meta types.Metadata) (*RateLimitMiddleware, error) {
@@ -29,3 +30,9 @@ func NewRateLimitMiddleware(meta types.Metadata) (*RateLimitMiddleware, error) {
 func (rl *RateLimitMiddleware) Take() {
 	_ = rl.rateLimiter.Take()
 }
+
+// WithMetrics reports rate limiter wait time to the metrics middleware exporter
+func (rl *RateLimitMiddleware) WithMetrics(m *MetricsMiddleware) *RateLimitMiddleware {
+	rl.metrics = m
+	return rl
+}
@@ -18,7 +18,8 @@ var delayTypeMap = map[string]string{
 }
 
 type RetryMiddleware struct {
-	opts []retry.Option
+	opts    []retry.Option
+	metrics *MetricsMiddleware
 }
 
 func parseRetryOptions(meta types.Metadata) ([]retry.Option, error) {
@@ -61,12 +62,21 @@ func NewRetryMiddleware(meta types.Metadata, log *logger.Logger) (*RetryMiddlewa
 	if err != nil {
 		return nil, fmt.Errorf("error parsing retry options, %w", err)
 	}
-	if log != nil {
-		opts = append(opts, retry.OnRetry(func(n uint, err error) {
+	r := &RetryMiddleware{}
+	opts = append(opts, retry.OnRetry(func(n uint, err error) {
+		if log != nil {
 			log.Errorf("retry %d failed, error: %s", n, err.Error())
-		}))
-	}
-	return &RetryMiddleware{
-		opts: opts,
-	}, nil
+		}
+		if r.metrics != nil {
+			r.metrics.reportRetry()
+		}
+	}))
+	r.opts = opts
+	return r, nil
+}
+
+// WithMetrics reports retries to the metrics middleware exporter
+func (r *RetryMiddleware) WithMetrics(m *MetricsMiddleware) *RetryMiddleware {
+	r.metrics = m
+	return r
 }
@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+type routeRule struct {
+	metadata string
+	field    gval.Evaluable
+	operator string
+	value    string
+	regex    *regexp.Regexp
+}
+
+func newRouteRule(cfg config.RouteRule) (*routeRule, error) {
+	rr := &routeRule{
+		metadata: cfg.Metadata,
+		operator: cfg.Operator,
+		value:    cfg.Value,
+	}
+	if rr.operator == "" {
+		rr.operator = "equals"
+	}
+	if cfg.Field != "" {
+		path := cfg.Field
+		if !strings.HasPrefix(path, "$") {
+			path = "$." + path
+		}
+		var err error
+		rr.field, err = jsonpath.New(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field path %s, %w", cfg.Field, err)
+		}
+	}
+	if rr.operator == "regex" {
+		var err error
+		rr.regex, err = regexp.Compile(cfg.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex value %s, %w", cfg.Value, err)
+		}
+	}
+	return rr, nil
+}
+
+func (rr *routeRule) lookup(ctx context.Context, request *types.Request, body interface{}) (string, bool) {
+	if rr.field == nil {
+		val, ok := request.Metadata[rr.metadata]
+		return val, ok
+	}
+	if body == nil {
+		return "", false
+	}
+	val, err := rr.field(ctx, body)
+	if err != nil || val == nil {
+		return "", false
+	}
+	switch v := val.(type) {
+	case string:
+		return v, true
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(data), true
+	}
+}
+
+func (rr *routeRule) match(ctx context.Context, request *types.Request, body interface{}) bool {
+	val, ok := rr.lookup(ctx, request, body)
+	switch rr.operator {
+	case "exists":
+		return ok
+	case "not_equals":
+		return !ok || val != rr.value
+	case "regex":
+		return ok && rr.regex.MatchString(val)
+	default:
+		return ok && val == rr.value
+	}
+}
+
+type Route struct {
+	Name   string
+	Target Middleware
+	rules  []*routeRule
+}
+
+func NewRoute(cfg config.RouteConfig, target Middleware) (*Route, error) {
+	r := &Route{
+		Name:   cfg.Name,
+		Target: target,
+	}
+	for _, ruleCfg := range cfg.Match {
+		rule, err := newRouteRule(ruleCfg)
+		if err != nil {
+			return nil, fmt.Errorf("error loading route %s rules, %w", cfg.Name, err)
+		}
+		r.rules = append(r.rules, rule)
+	}
+	return r, nil
+}
+
+// match returns true when all the route rules match the request, a route without rules matches every request
+func (r *Route) match(ctx context.Context, request *types.Request, body interface{}) bool {
+	for _, rule := range r.rules {
+		if !rule.match(ctx, request, body) {
+			return false
+		}
+	}
+	return true
+}
+
+type routeResponse struct {
+	Route    string            `json:"route"`
+	Metadata map[string]string `json:"metadata"`
+	Data     interface{}       `json:"data"`
+	IsError  bool              `json:"is_error"`
+	Error    string            `json:"error"`
+}
+
+type RouterMiddleware struct {
+	routes []*Route
+	mode   string
+}
+
+func NewRouterMiddleware(mode string, routes []*Route) *RouterMiddleware {
+	return &RouterMiddleware{
+		routes: routes,
+		mode:   mode,
+	}
+}
+
+func copyRequest(request *types.Request) *types.Request {
+	req := types.NewRequest()
+	for key, value := range request.Metadata {
+		req.SetMetadataKeyValue(key, value)
+	}
+	if request.Data != nil {
+		req.SetData(append([]byte{}, request.Data...))
+	}
+	return req
+}
+
+func (rm *RouterMiddleware) Do(ctx context.Context, request *types.Request) (*types.Response, error) {
+	var body interface{}
+	if len(request.Data) > 0 {
+		_ = json.Unmarshal(request.Data, &body)
+	}
+	var matched []*Route
+	for _, route := range rm.routes {
+		if route.match(ctx, request, body) {
+			matched = append(matched, route)
+			if rm.mode != "all" {
+				break
+			}
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return types.NewResponse().SetMetadataKeyValue("routed", "false"), nil
+	case 1:
+		resp, err := matched[0].Target.Do(ctx, request)
+		if resp != nil {
+			resp.SetMetadataKeyValue("route", matched[0].Name)
+		}
+		return resp, err
+	}
+	var responses []*routeResponse
+	var names, failed, errs []string
+	for _, route := range matched {
+		names = append(names, route.Name)
+		resp, err := route.Target.Do(ctx, copyRequest(request))
+		rr := &routeResponse{
+			Route: route.Name,
+		}
+		if resp != nil {
+			rr.Metadata = resp.Metadata
+			rr.Data = types.ParseResponseBody(resp.Data)
+			rr.IsError = resp.IsError
+			rr.Error = resp.Error
+		}
+		if err != nil {
+			rr.IsError = true
+			rr.Error = err.Error()
+			failed = append(failed, route.Name)
+			errs = append(errs, fmt.Sprintf("%s: %s", route.Name, err.Error()))
+		}
+		responses = append(responses, rr)
+	}
+	// the request fails only when no route executed it, otherwise a redelivery would send it again to the routes which succeeded
+	if len(failed) == len(matched) {
+		return nil, fmt.Errorf("error executing routes, %s", strings.Join(errs, ", "))
+	}
+	data, err := json.Marshal(responses)
+	if err != nil {
+		return nil, err
+	}
+	resp := types.NewResponse().
+		SetMetadataKeyValue("route", strings.Join(names, ",")).
+		SetData(data)
+	if len(failed) > 0 {
+		resp.SetMetadataKeyValue("failed_routes", strings.Join(failed, ","))
+	}
+	return resp, nil
+}
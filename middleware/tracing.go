@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
+	"github.com/kubemq-io/kubemq-targets/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func responseError(resp *types.Response, err error) error {
+	if err == nil && resp != nil && resp.IsError {
+		return fmt.Errorf("%s", resp.Error)
+	}
+	return err
+}
+
+// Span wraps a middleware func with a tracing span named after the middleware
+func Span(name string, mf MiddlewareFunc) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		next := mf(df)
+		return DoFunc(func(ctx context.Context, request *types.Request) (*types.Response, error) {
+			ctx, span := tracing.StartSpan(ctx, fmt.Sprintf("middleware %s", name), trace.SpanKindInternal)
+			resp, err := next.Do(ctx, request)
+			tracing.End(span, responseError(resp, err))
+			return resp, err
+		})
+	}
+}
+
+// TargetSpan wraps the target call with a client span
+func TargetSpan(kind string) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request *types.Request) (*types.Response, error) {
+			ctx, span := tracing.StartSpan(ctx, fmt.Sprintf("target %s", kind), trace.SpanKindClient,
+				attribute.String("target.kind", kind))
+			resp, err := df.Do(ctx, request)
+			tracing.End(span, responseError(resp, err))
+			return resp, err
+		})
+	}
+}
+
+// BindingSpan starts the binding span, the trace context is taken from the request metadata when the source did not provide one,
+// and is injected back to the request metadata so targets forwarding the metadata propagate it downstream
+func BindingSpan(binding, sourceKind string) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request *types.Request) (*types.Response, error) {
+			if !trace.SpanContextFromContext(ctx).IsValid() && request != nil && request.Metadata != nil {
+				ctx = tracing.Extract(ctx, propagation.MapCarrier(request.Metadata))
+			}
+			ctx, span := tracing.StartSpan(ctx, fmt.Sprintf("binding %s", binding), trace.SpanKindServer,
+				attribute.String("binding.name", binding),
+				attribute.String("source.kind", sourceKind))
+			if request != nil && span.SpanContext().IsValid() {
+				if request.Metadata == nil {
+					request.Metadata = types.NewMetadata()
+				}
+				tracing.Inject(ctx, propagation.MapCarrier(request.Metadata))
+			}
+			resp, err := df.Do(ctx, request)
+			tracing.End(span, responseError(resp, err))
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// transformContext is the input of transform expressions, templates access it with .Metadata, .Data, .Body and so on
+type transformContext struct {
+	Metadata map[string]string
+	Data     string
+	Body     interface{}
+	IsError  bool
+	Error    string
+	Request  *transformContext
+}
+
+func newTransformContext(meta types.Metadata, data []byte) *transformContext {
+	tc := &transformContext{
+		Metadata: meta,
+		Data:     string(data),
+	}
+	if len(data) > 0 {
+		var body interface{}
+		if err := json.Unmarshal(data, &body); err == nil {
+			tc.Body = body
+		}
+	}
+	return tc
+}
+
+// transformExpr is a single transform value, either a JSONPath expression (starts with $), a Go template or a literal string
+type transformExpr struct {
+	path     gval.Evaluable
+	template *template.Template
+	literal  string
+}
+
+func jsonPathFunc(path string, value interface{}) (interface{}, error) {
+	return jsonpath.Get(path, value)
+}
+
+func newTransformExpr(name, expr string) (*transformExpr, error) {
+	te := &transformExpr{}
+	switch {
+	case strings.HasPrefix(expr, "$"):
+		path, err := jsonpath.New(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jsonpath expression for %s, %w", name, err)
+		}
+		te.path = path
+	case strings.Contains(expr, "{{"):
+		funcs := sprig.TxtFuncMap()
+		funcs["jsonpath"] = jsonPathFunc
+		tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for %s, %w", name, err)
+		}
+		te.template = tmpl
+	default:
+		te.literal = expr
+	}
+	return te, nil
+}
+
+func (te *transformExpr) eval(ctx context.Context, tc *transformContext) ([]byte, error) {
+	switch {
+	case te.path != nil:
+		if tc.Body == nil {
+			return nil, fmt.Errorf("data is not a valid json document")
+		}
+		value, err := te.path(ctx, tc.Body)
+		if err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case string:
+			return []byte(v), nil
+		case nil:
+			return nil, nil
+		default:
+			return json.Marshal(v)
+		}
+	case te.template != nil:
+		buf := &bytes.Buffer{}
+		if err := te.template.Execute(buf, tc); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return []byte(te.literal), nil
+	}
+}
+
+type TransformMiddleware struct {
+	requestMetadata  map[string]*transformExpr
+	requestData      *transformExpr
+	responseMetadata map[string]*transformExpr
+	responseData     *transformExpr
+}
+
+func parseTransformMap(meta types.Metadata, key string) (map[string]*transformExpr, error) {
+	if meta.ParseString(key, "") == "" {
+		return nil, nil
+	}
+	values, err := meta.MustParseJsonMap(key)
+	if err != nil {
+		return nil, err
+	}
+	exprs := map[string]*transformExpr{}
+	for name, value := range values {
+		exprs[name], err = newTransformExpr(name, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return exprs, nil
+}
+
+func parseTransformValue(meta types.Metadata, key string) (*transformExpr, error) {
+	value := meta.ParseString(key, "")
+	if value == "" {
+		return nil, nil
+	}
+	return newTransformExpr(key, value)
+}
+
+func NewTransformMiddleware(meta types.Metadata) (*TransformMiddleware, error) {
+	tm := &TransformMiddleware{}
+	var err error
+	tm.requestMetadata, err = parseTransformMap(meta, "transform_request_metadata")
+	if err != nil {
+		return nil, fmt.Errorf("invalid transform request metadata value, %w", err)
+	}
+	tm.requestData, err = parseTransformValue(meta, "transform_request_data")
+	if err != nil {
+		return nil, fmt.Errorf("invalid transform request data value, %w", err)
+	}
+	tm.responseMetadata, err = parseTransformMap(meta, "transform_response_metadata")
+	if err != nil {
+		return nil, fmt.Errorf("invalid transform response metadata value, %w", err)
+	}
+	tm.responseData, err = parseTransformValue(meta, "transform_response_data")
+	if err != nil {
+		return nil, fmt.Errorf("invalid transform response data value, %w", err)
+	}
+	return tm, nil
+}
+
+func (tm *TransformMiddleware) isEmpty() bool {
+	return tm.requestMetadata == nil && tm.requestData == nil && tm.responseMetadata == nil && tm.responseData == nil
+}
+
+// transformRequest transforms the request and returns its context for the response transform
+func (tm *TransformMiddleware) transformRequest(ctx context.Context, request *types.Request) (*transformContext, error) {
+	tc := newTransformContext(request.Metadata, request.Data)
+	values := map[string]string{}
+	for key, expr := range tm.requestMetadata {
+		value, err := expr.eval(ctx, tc)
+		if err != nil {
+			return nil, fmt.Errorf("error transforming request metadata %s, %w", key, err)
+		}
+		values[key] = string(value)
+	}
+	if tm.requestData != nil {
+		data, err := tm.requestData.eval(ctx, tc)
+		if err != nil {
+			return nil, fmt.Errorf("error transforming request data, %w", err)
+		}
+		request.SetData(data)
+	}
+	if request.Metadata == nil {
+		request.Metadata = types.NewMetadata()
+	}
+	for key, value := range values {
+		request.SetMetadataKeyValue(key, value)
+	}
+	// the response templates access the request as it was sent to the target, so its context is rebuilt from the
+	// transformed request with a copy of the metadata
+	meta := make(map[string]string, len(request.Metadata))
+	for key, value := range request.Metadata {
+		meta[key] = value
+	}
+	return newTransformContext(meta, request.Data), nil
+}
+
+func (tm *TransformMiddleware) transformResponse(ctx context.Context, reqContext *transformContext, response *types.Response) error {
+	tc := newTransformContext(response.Metadata, response.Data)
+	tc.IsError = response.IsError
+	tc.Error = response.Error
+	tc.Request = reqContext
+	values := map[string]string{}
+	for key, expr := range tm.responseMetadata {
+		value, err := expr.eval(ctx, tc)
+		if err != nil {
+			return fmt.Errorf("error transforming response metadata %s, %w", key, err)
+		}
+		values[key] = string(value)
+	}
+	if tm.responseData != nil {
+		data, err := tm.responseData.eval(ctx, tc)
+		if err != nil {
+			return fmt.Errorf("error transforming response data, %w", err)
+		}
+		response.SetData(data)
+	}
+	if response.Metadata == nil {
+		response.Metadata = types.NewMetadata()
+	}
+	for key, value := range values {
+		response.SetMetadataKeyValue(key, value)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	ErrOpenState       = errors.New("circuit breaker is open")
+	ErrTooManyRequests = errors.New("circuit breaker is half-open, too many probe requests")
+)
+
+// OnStateChangeFunc is called on every transition of the breaker state
+type OnStateChangeFunc func(from, to State)
+
+type Settings struct {
+	// FailureRatio is the ratio of failed requests in the window which opens the breaker
+	FailureRatio float64
+	// WindowSize is the number of latest requests the failure ratio is calculated on
+	WindowSize int
+	// OpenDuration is how long the breaker stays open before allowing probe requests
+	OpenDuration time.Duration
+	// HalfOpenProbes is the number of successful probe requests required to close the breaker
+	HalfOpenProbes int
+	OnStateChange  OnStateChangeFunc
+}
+
+type Breaker struct {
+	sync.Mutex
+	settings       Settings
+	state          State
+	window         []bool
+	windowPos      int
+	windowCount    int
+	windowFailures int
+	probes         int
+	probeSuccesses int
+	openedAt       time.Time
+	now            func() time.Time
+}
+
+func New(settings Settings) *Breaker {
+	if settings.WindowSize <= 0 {
+		settings.WindowSize = 1
+	}
+	if settings.HalfOpenProbes <= 0 {
+		settings.HalfOpenProbes = 1
+	}
+	return &Breaker{
+		settings: settings,
+		state:    StateClosed,
+		window:   make([]bool, settings.WindowSize),
+		now:      time.Now,
+	}
+}
+
+// State returns the current state of the breaker, moving an expired open state to half-open
+func (b *Breaker) State() State {
+	b.Lock()
+	defer b.Unlock()
+	b.checkOpenExpiry()
+	return b.state
+}
+
+// Allow must be called before each request, a nil error means the request may proceed and Done must be called with its outcome
+func (b *Breaker) Allow() error {
+	b.Lock()
+	defer b.Unlock()
+	b.checkOpenExpiry()
+	switch b.state {
+	case StateOpen:
+		return ErrOpenState
+	case StateHalfOpen:
+		if b.probes >= b.settings.HalfOpenProbes {
+			return ErrTooManyRequests
+		}
+		b.probes++
+	}
+	return nil
+}
+
+// Done reports the outcome of a request which was allowed
+func (b *Breaker) Done(success bool) {
+	b.Lock()
+	defer b.Unlock()
+	switch b.state {
+	case StateClosed:
+		b.record(success)
+		if b.windowCount >= b.settings.WindowSize &&
+			float64(b.windowFailures)/float64(b.windowCount) >= b.settings.FailureRatio {
+			b.setState(StateOpen)
+		}
+	case StateHalfOpen:
+		if !success {
+			b.setState(StateOpen)
+			return
+		}
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.settings.HalfOpenProbes {
+			b.setState(StateClosed)
+		}
+	}
+}
+
+// Execute runs fn if the breaker allows it and records its outcome
+func (b *Breaker) Execute(fn func() error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	err := fn()
+	b.Done(err == nil)
+	return err
+}
+
+func (b *Breaker) record(success bool) {
+	if b.windowCount == b.settings.WindowSize {
+		if !b.window[b.windowPos] {
+			b.windowFailures--
+		}
+	} else {
+		b.windowCount++
+	}
+	b.window[b.windowPos] = success
+	if !success {
+		b.windowFailures++
+	}
+	b.windowPos = (b.windowPos + 1) % b.settings.WindowSize
+}
+
+func (b *Breaker) checkOpenExpiry() {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.settings.OpenDuration {
+		b.setState(StateHalfOpen)
+	}
+}
+
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+	prev := b.state
+	b.state = state
+	b.probes = 0
+	b.probeSuccesses = 0
+	switch state {
+	case StateOpen:
+		b.openedAt = b.now()
+	case StateClosed:
+		b.windowPos = 0
+		b.windowCount = 0
+		b.windowFailures = 0
+	}
+	if b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(prev, state)
+	}
+}
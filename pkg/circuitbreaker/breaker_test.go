@@ -0,0 +1,74 @@
+package circuitbreaker
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker_Transitions(t *testing.T) {
+	var transitions []string
+	b := New(Settings{
+		FailureRatio:   0.5,
+		WindowSize:     4,
+		OpenDuration:   time.Minute,
+		HalfOpenProbes: 2,
+		OnStateChange: func(from, to State) {
+			transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+		},
+	})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	failFn := func() error { return fmt.Errorf("some-error") }
+	okFn := func() error { return nil }
+
+	require.NoError(t, b.Execute(okFn))
+	require.NoError(t, b.Execute(okFn))
+	require.Error(t, b.Execute(failFn))
+	require.Equal(t, StateClosed, b.State())
+	require.Error(t, b.Execute(failFn))
+	require.Equal(t, StateOpen, b.State())
+	require.ErrorIs(t, b.Execute(okFn), ErrOpenState)
+
+	now = now.Add(time.Minute)
+	require.Equal(t, StateHalfOpen, b.State())
+	require.NoError(t, b.Allow())
+	require.NoError(t, b.Allow())
+	require.ErrorIs(t, b.Allow(), ErrTooManyRequests)
+	b.Done(true)
+	require.Equal(t, StateHalfOpen, b.State())
+	b.Done(false)
+	require.Equal(t, StateOpen, b.State())
+
+	now = now.Add(time.Minute)
+	require.NoError(t, b.Execute(okFn))
+	require.NoError(t, b.Execute(okFn))
+	require.Equal(t, StateClosed, b.State())
+	require.Equal(t, []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}, transitions)
+}
+
+func TestBreaker_RollingWindow(t *testing.T) {
+	b := New(Settings{
+		FailureRatio:   0.75,
+		WindowSize:     4,
+		OpenDuration:   time.Minute,
+		HalfOpenProbes: 1,
+	})
+	outcomes := []bool{false, false, true, true, false, true, false}
+	for _, success := range outcomes {
+		require.NoError(t, b.Allow())
+		b.Done(success)
+		require.Equal(t, StateClosed, b.State())
+	}
+	require.NoError(t, b.Allow())
+	b.Done(false)
+	require.Equal(t, StateOpen, b.State())
+}
@@ -0,0 +1,51 @@
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+type MemcachedStore struct {
+	client *memcache.Client
+}
+
+func NewMemcachedStore(hosts ...string) (*MemcachedStore, error) {
+	client := memcache.New(hosts...)
+	if err := client.Ping(); err != nil {
+		return nil, err
+	}
+	return &MemcachedStore{client: client}, nil
+}
+
+// memcachedKey hashes the key, memcached keys are limited to 250 characters without spaces
+func memcachedKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+func (m *MemcachedStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	item, err := m.client.Get(memcachedKey(key))
+	if err == memcache.ErrCacheMiss {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return item.Value, true, nil
+}
+
+func (m *MemcachedStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return m.client.Set(&memcache.Item{
+		Key:        memcachedKey(key),
+		Value:      value,
+		Expiration: int32(ttl.Seconds()),
+	})
+}
+
+func (m *MemcachedStore) Close() error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	key      string
+	value    []byte
+	expireAt time.Time
+}
+
+// MemoryStore is an in-memory LRU store, the least recently used key is evicted when the store is full
+type MemoryStore struct {
+	sync.Mutex
+	maxKeys int
+	items   map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	if maxKeys <= 0 {
+		maxKeys = 1
+	}
+	return &MemoryStore{
+		maxKeys: maxKeys,
+		items:   map[string]*list.Element{},
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.Lock()
+	defer m.Unlock()
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item := el.Value.(*memoryItem)
+	if !m.now().Before(item.expireAt) {
+		m.remove(el)
+		return nil, false, nil
+	}
+	m.order.MoveToFront(el)
+	return item.value, true, nil
+}
+
+func (m *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+	expireAt := m.now().Add(ttl)
+	if el, ok := m.items[key]; ok {
+		item := el.Value.(*memoryItem)
+		item.value = value
+		item.expireAt = expireAt
+		m.order.MoveToFront(el)
+		return nil
+	}
+	m.items[key] = m.order.PushFront(&memoryItem{
+		key:      key,
+		value:    value,
+		expireAt: expireAt,
+	})
+	for m.order.Len() > m.maxKeys {
+		m.remove(m.order.Back())
+	}
+	return nil
+}
+
+func (m *MemoryStore) Len() int {
+	m.Lock()
+	defer m.Unlock()
+	return m.order.Len()
+}
+
+func (m *MemoryStore) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.items, el.Value.(*memoryItem).key)
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,42 @@
+package dedup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Eviction(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(2)
+	require.NoError(t, s.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, s.Set(ctx, "b", []byte("2"), time.Minute))
+	_, ok, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, s.Set(ctx, "c", []byte("3"), time.Minute))
+	require.Equal(t, 2, s.Len())
+	_, ok, _ = s.Get(ctx, "b")
+	require.False(t, ok)
+	value, ok, _ := s.Get(ctx, "a")
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), value)
+	_, ok, _ = s.Get(ctx, "c")
+	require.True(t, ok)
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(10)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.Set(ctx, "a", []byte("1"), time.Minute))
+	_, ok, _ := s.Get(ctx, "a")
+	require.True(t, ok)
+	now = now.Add(time.Minute)
+	_, ok, _ = s.Get(ctx, "a")
+	require.False(t, ok)
+	require.Equal(t, 0, s.Len())
+}
@@ -0,0 +1,52 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(ctx context.Context, url string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		// the url may hold the redis password, so the parse error is reported without it
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("error parsing redis url: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if _, err := client.WithContext(ctx).Ping().Result(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("error connecting to redis at %s: %w", opts.Addr, err)
+	}
+	return &RedisStore{client: client}, nil
+}
+
+func (r *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.WithContext(ctx).Get(key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.WithContext(ctx).Set(key, value, ttl).Err()
+}
+
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,22 @@
+package dedup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedisStore_RedactsURL(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, url := range []string{
+		"redis://:secret-password@localhost:bad-port",
+		"redis://:secret-password@localhost:1/0",
+	} {
+		_, err := NewRedisStore(ctx, url)
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "secret-password")
+	}
+}
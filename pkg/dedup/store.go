@@ -0,0 +1,14 @@
+package dedup
+
+import (
+	"context"
+	"time"
+)
+
+// Store remembers processed idempotency keys and their responses for a limited time
+type Store interface {
+	// Get returns the value stored for the key, false when the key is unknown or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Close() error
+}
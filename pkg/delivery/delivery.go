@@ -0,0 +1,166 @@
+// Package delivery sends the requests of a source to its binding target, failed requests are retried with a growing
+// delay and, for KubeMQ sources, moved to a dead letter queue once their attempts are exhausted
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-targets/middleware"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
+	"github.com/kubemq-io/kubemq-targets/types"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// MaxRetryInterval caps the delay between attempts
+const MaxRetryInterval = 30 * time.Second
+
+// Retry is the retry policy of a request
+type Retry struct {
+	// MaxAttempts is the number of attempts of a request, 0 retries the request until it succeeds or ctx is done
+	MaxAttempts int
+	// Interval is the delay after the first failed attempt, it doubles after each failed attempt up to MaxRetryInterval
+	Interval time.Duration
+	// OnRetry, when set, is called before waiting for the next attempt
+	OnRetry func(attempts int, delay time.Duration, err error)
+}
+
+// Delay returns the wait before the next attempt, doubling the retry interval after each failed attempt
+func (r Retry) Delay(attempts int) time.Duration {
+	delay := r.Interval
+	for i := 1; i < attempts && delay < MaxRetryInterval; i++ {
+		delay *= 2
+	}
+	if delay > MaxRetryInterval {
+		return MaxRetryInterval
+	}
+	return delay
+}
+
+// Send sends the request to the target until it succeeds or the attempts are exhausted, a response with an error is a failed attempt.
+// It returns the number of attempts, when ctx is done while waiting for the next attempt ctx error is returned
+func Send(ctx context.Context, target middleware.Middleware, req *types.Request, retry Retry) (*types.Response, int, error) {
+	attempts := 0
+	for {
+		attempts++
+		resp, err := target.Do(ctx, req)
+		if err == nil && resp != nil && resp.IsError {
+			err = errors.New(resp.Error)
+		}
+		if err == nil {
+			return resp, attempts, nil
+		}
+		if retry.MaxAttempts > 0 && attempts >= retry.MaxAttempts {
+			return nil, attempts, err
+		}
+		delay := retry.Delay(attempts)
+		if retry.OnRetry != nil {
+			retry.OnRetry(attempts, delay, err)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, attempts, ctx.Err()
+		}
+	}
+}
+
+// Sender sends a message to the dead letter queue
+type Sender func(ctx context.Context, msg *kubemq.QueueMessage) error
+
+// QueueSender returns a Sender sending the dead letter messages with the KubeMQ client
+func QueueSender(client *kubemq.Client) Sender {
+	return func(ctx context.Context, msg *kubemq.QueueMessage) error {
+		result, err := client.SetQueueMessage(msg).Send(ctx)
+		if err == nil && result.IsError {
+			err = errors.New(result.Error)
+		}
+		return err
+	}
+}
+
+// Message is a message received by a KubeMQ source
+type Message struct {
+	Metadata string
+	Body     []byte
+	Tags     map[string]string
+}
+
+// Processor sends the messages of a KubeMQ source to the target. When a dead letter channel is set, failed requests are retried
+// and the messages which could not be parsed or exhausted their attempts are moved to the dead letter channel
+type Processor struct {
+	Target            middleware.Middleware
+	Log               *logger.Logger
+	Binding           string
+	Channel           string
+	DeadLetterChannel string
+	MaxAttempts       int
+	RetryInterval     time.Duration
+	DoNotParsePayload bool
+}
+
+// Process sends the message request to the target, a message which is still retried when ctx is done, i.e. on the
+// binding stop or reload, is not moved to the dead letter channel
+func (p *Processor) Process(ctx context.Context, msg Message, send Sender) (*types.Response, error) {
+	var req *types.Request
+	var err error
+	if p.DoNotParsePayload {
+		req = types.NewRequest().SetData(msg.Body)
+	} else {
+		req, err = types.ParseRequest(msg.Body)
+		if err != nil {
+			err = fmt.Errorf("invalid request format, %w", err)
+			if p.DeadLetterChannel != "" {
+				p.deadLetter(ctx, send, msg, 0, err)
+			}
+			return nil, err
+		}
+	}
+	if p.DeadLetterChannel == "" {
+		resp, _, err := Send(ctx, p.Target, req, Retry{MaxAttempts: 1})
+		return resp, err
+	}
+	resp, attempts, err := Send(ctx, p.Target, req, Retry{
+		MaxAttempts: p.MaxAttempts,
+		Interval:    p.RetryInterval,
+		OnRetry: func(attempts int, delay time.Duration, err error) {
+			p.Log.Errorf("error processing request, attempt %d of %d, %s, retrying in %s", attempts, p.MaxAttempts, err.Error(), delay)
+		},
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		p.deadLetter(ctx, send, msg, attempts, err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *Processor) deadLetter(ctx context.Context, send Sender, msg Message, attempts int, processErr error) {
+	err := send(ctx, kubemq.NewQueueMessage().
+		SetChannel(p.DeadLetterChannel).
+		SetMetadata(msg.Metadata).
+		SetBody(msg.Body).
+		SetTags(msg.Tags).
+		AddTag("dead_letter_error", processErr.Error()).
+		AddTag("dead_letter_attempts", fmt.Sprintf("%d", attempts)).
+		AddTag("dead_letter_binding", p.Binding).
+		AddTag("dead_letter_source_channel", p.Channel))
+	if err != nil {
+		p.Log.Errorf("error sending request to dead letter queue %s, %s", p.DeadLetterChannel, err.Error())
+		return
+	}
+	p.Log.Errorf("error processing request after %d attempts, %s, moved to dead letter queue %s", attempts, processErr.Error(), p.DeadLetterChannel)
+}
+
+// TraceTags returns the message tags carrying the trace context of ctx
+func TraceTags(ctx context.Context) map[string]string {
+	tags := map[string]string{}
+	tracing.Inject(ctx, propagation.MapCarrier(tags))
+	return tags
+}
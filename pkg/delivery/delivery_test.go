@@ -0,0 +1,194 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/stretchr/testify/require"
+)
+
+type failingTarget struct {
+	failures int
+	// errorResponses makes the failed attempts return a response with an error instead of an error
+	errorResponses bool
+	calls          int
+}
+
+func (f *failingTarget) Do(ctx context.Context, request *types.Request) (*types.Response, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		if f.errorResponses {
+			return types.NewResponse().SetError(fmt.Errorf("target error %d", f.calls)), nil
+		}
+		return nil, fmt.Errorf("target error %d", f.calls)
+	}
+	return types.NewResponse().SetData(request.Data), nil
+}
+
+func TestProcessor_Process(t *testing.T) {
+	tests := []struct {
+		name            string
+		target          *failingTarget
+		body            []byte
+		processor       Processor
+		deadLetterErr   error
+		cancelAfter     time.Duration
+		wantResp        *types.Response
+		wantErr         bool
+		wantCalls       int
+		wantDeadLetters []map[string]string
+	}{
+		{
+			name:      "succeeds after retries",
+			target:    &failingTarget{failures: 2},
+			body:      types.NewRequest().SetData([]byte("some-data")).MarshalBinary(),
+			processor: Processor{DeadLetterChannel: "dead-letter", MaxAttempts: 3, RetryInterval: 10 * time.Millisecond},
+			wantResp:  types.NewResponse().SetData([]byte("some-data")),
+			wantCalls: 3,
+		},
+		{
+			name:      "moved to dead letter after max attempts",
+			target:    &failingTarget{failures: 3},
+			body:      types.NewRequest().SetData([]byte("some-data")).MarshalBinary(),
+			processor: Processor{DeadLetterChannel: "dead-letter", MaxAttempts: 3, RetryInterval: 10 * time.Millisecond},
+			wantErr:   true,
+			wantCalls: 3,
+			wantDeadLetters: []map[string]string{
+				{
+					"dead_letter_error":          "target error 3",
+					"dead_letter_attempts":       "3",
+					"dead_letter_binding":        "binding",
+					"dead_letter_source_channel": "events",
+				},
+			},
+		},
+		{
+			name:      "error responses are failed attempts",
+			target:    &failingTarget{failures: 3, errorResponses: true},
+			body:      types.NewRequest().SetData([]byte("some-data")).MarshalBinary(),
+			processor: Processor{DeadLetterChannel: "dead-letter", MaxAttempts: 3, RetryInterval: 10 * time.Millisecond},
+			wantErr:   true,
+			wantCalls: 3,
+			wantDeadLetters: []map[string]string{
+				{
+					"dead_letter_error":    "target error 3",
+					"dead_letter_attempts": "3",
+				},
+			},
+		},
+		{
+			name:      "invalid request moved to dead letter",
+			target:    &failingTarget{},
+			body:      []byte("bad-request"),
+			processor: Processor{DeadLetterChannel: "dead-letter", MaxAttempts: 3, RetryInterval: 10 * time.Millisecond},
+			wantErr:   true,
+			wantCalls: 0,
+			wantDeadLetters: []map[string]string{
+				{
+					"dead_letter_attempts":       "0",
+					"dead_letter_binding":        "binding",
+					"dead_letter_source_channel": "events",
+				},
+			},
+		},
+		{
+			name:          "dead letter send error",
+			target:        &failingTarget{failures: 1},
+			body:          types.NewRequest().SetData([]byte("some-data")).MarshalBinary(),
+			processor:     Processor{DeadLetterChannel: "dead-letter", MaxAttempts: 1, RetryInterval: 10 * time.Millisecond},
+			deadLetterErr: fmt.Errorf("send error"),
+			wantErr:       true,
+			wantCalls:     1,
+			wantDeadLetters: []map[string]string{
+				{
+					"dead_letter_error":    "target error 1",
+					"dead_letter_attempts": "1",
+				},
+			},
+		},
+		{
+			name:        "not moved to dead letter on shutdown",
+			target:      &failingTarget{failures: 3},
+			body:        types.NewRequest().SetData([]byte("some-data")).MarshalBinary(),
+			processor:   Processor{DeadLetterChannel: "dead-letter", MaxAttempts: 3, RetryInterval: time.Second},
+			cancelAfter: 50 * time.Millisecond,
+			wantErr:     true,
+			wantCalls:   1,
+		},
+		{
+			name:      "no dead letter channel",
+			target:    &failingTarget{failures: 1},
+			body:      types.NewRequest().SetData([]byte("some-data")).MarshalBinary(),
+			processor: Processor{},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if tt.cancelAfter > 0 {
+				time.AfterFunc(tt.cancelAfter, cancel)
+			}
+			p := tt.processor
+			p.Target = tt.target
+			p.Log = logger.NewLogger("test")
+			p.Binding = "binding"
+			p.Channel = "events"
+			var deadLetters []*kubemq.QueueMessage
+			send := func(ctx context.Context, msg *kubemq.QueueMessage) error {
+				deadLetters = append(deadLetters, msg)
+				return tt.deadLetterErr
+			}
+			start := time.Now()
+			gotResp, err := p.Process(ctx, Message{Metadata: "some-metadata", Body: tt.body, Tags: map[string]string{"key": "value"}}, send)
+			require.EqualValues(t, tt.wantCalls, tt.target.calls)
+			if tt.wantCalls > 1 {
+				// 10ms and 20ms backoff between the three attempts
+				require.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+			}
+			require.Len(t, deadLetters, len(tt.wantDeadLetters))
+			for i, wantTags := range tt.wantDeadLetters {
+				require.EqualValues(t, "dead-letter", deadLetters[i].Channel)
+				require.EqualValues(t, tt.body, deadLetters[i].Body)
+				require.EqualValues(t, "some-metadata", deadLetters[i].Metadata)
+				require.EqualValues(t, "value", deadLetters[i].Tags["key"])
+				for key, value := range wantTags {
+					require.EqualValues(t, value, deadLetters[i].Tags[key], key)
+				}
+				require.NotEmpty(t, deadLetters[i].Tags["dead_letter_error"])
+			}
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tt.wantResp, gotResp)
+		})
+	}
+}
+
+func TestRetry_Delay(t *testing.T) {
+	r := Retry{Interval: time.Second}
+	require.EqualValues(t, time.Second, r.Delay(1))
+	require.EqualValues(t, 2*time.Second, r.Delay(2))
+	require.EqualValues(t, 8*time.Second, r.Delay(4))
+	require.EqualValues(t, MaxRetryInterval, r.Delay(10))
+	require.EqualValues(t, MaxRetryInterval, r.Delay(1000))
+	r.Interval = 0
+	require.EqualValues(t, 0, r.Delay(3))
+}
+
+func TestSend_Unlimited(t *testing.T) {
+	target := &failingTarget{failures: 5, errorResponses: true}
+	resp, attempts, err := Send(context.Background(), target, types.NewRequest().SetData([]byte("some-data")), Retry{Interval: time.Millisecond})
+	require.NoError(t, err)
+	require.Equal(t, 6, attempts)
+	require.EqualValues(t, "some-data", string(resp.Data))
+}
@@ -0,0 +1,109 @@
+package elasticengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/olivere/elastic/v7"
+)
+
+// BulkItem is the result of a bulk action
+type BulkItem struct {
+	Action string          `json:"action"`
+	Index  string          `json:"index,omitempty"`
+	ID     string          `json:"id,omitempty"`
+	Status int             `json:"status"`
+	Result string          `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+type bulkResponse struct {
+	Took   int64                        `json:"took"`
+	Errors bool                         `json:"errors"`
+	Items  []map[string]json.RawMessage `json:"items"`
+}
+
+type bulkItemResponse struct {
+	Index  string          `json:"_index"`
+	ID     string          `json:"_id"`
+	Status int             `json:"status"`
+	Result string          `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// bulk sends the request ndjson data to the bulk api, the request index is the default index of the actions,
+// failed actions do not fail the request and are counted in the failed metadata
+func (e *Engine) bulk(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("bulk error, ndjson actions are missing")
+	}
+	path := "/_bulk"
+	if meta.index != "" {
+		path = "/" + url.PathEscape(meta.index) + "/_bulk"
+	}
+	params := url.Values{}
+	if meta.refresh != "" {
+		params.Set("refresh", meta.refresh)
+	}
+	// the bulk api requires the last action to end with a new line
+	res, err := e.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method:      "POST",
+		Path:        path,
+		Params:      params,
+		Body:        string(data) + "\n",
+		ContentType: "application/x-ndjson",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bulk error, %w", err)
+	}
+	bulkResp := bulkResponse{}
+	if err := json.Unmarshal(res.Body, &bulkResp); err != nil {
+		return nil, fmt.Errorf("bulk response parsing error, %w", err)
+	}
+	items, failed, err := parseBulkItems(bulkResp.Items)
+	if err != nil {
+		return nil, err
+	}
+	result, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("bulk result parsing error, %w", err)
+	}
+	return types.NewResponse().
+			SetData(result).
+			SetMetadataKeyValue("took", fmt.Sprintf("%d", bulkResp.Took)).
+			SetMetadataKeyValue("count", fmt.Sprintf("%d", len(items))).
+			SetMetadataKeyValue("failed", fmt.Sprintf("%d", failed)).
+			SetMetadataKeyValue("errors", fmt.Sprintf("%t", bulkResp.Errors)).
+			SetMetadataKeyValue("result", "ok"),
+		nil
+}
+
+func parseBulkItems(responseItems []map[string]json.RawMessage) ([]BulkItem, int, error) {
+	items := make([]BulkItem, 0, len(responseItems))
+	failed := 0
+	for _, responseItem := range responseItems {
+		for action, raw := range responseItem {
+			itemResp := bulkItemResponse{}
+			if err := json.Unmarshal(raw, &itemResp); err != nil {
+				return nil, 0, fmt.Errorf("bulk item parsing error, %w", err)
+			}
+			if len(itemResp.Error) > 0 {
+				failed++
+			}
+			items = append(items, BulkItem{
+				Action: action,
+				Index:  itemResp.Index,
+				ID:     itemResp.ID,
+				Status: itemResp.Status,
+				Result: itemResp.Result,
+				Error:  itemResp.Error,
+			})
+		}
+	}
+	return items, failed, nil
+}
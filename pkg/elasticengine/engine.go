@@ -0,0 +1,130 @@
+package elasticengine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/olivere/elastic/v7"
+)
+
+// Engine executes the elasticsearch target requests
+type Engine struct {
+	client *elastic.Client
+	opts   Options
+}
+
+// New connects to elasticsearch and pings the first url
+func New(ctx context.Context, opts Options) (*Engine, error) {
+	if len(opts.URLs) == 0 {
+		return nil, fmt.Errorf("no elasticsearch urls found")
+	}
+	e := &Engine{
+		opts: opts,
+	}
+	elasticOpts := []elastic.ClientOptionFunc{
+		elastic.SetURL(opts.URLs...),
+		elastic.SetSniff(opts.Sniff),
+		elastic.SetBasicAuth(opts.Username, opts.Password),
+	}
+	if opts.HTTPClient != nil {
+		elasticOpts = append(elasticOpts, elastic.SetHttpClient(opts.HTTPClient))
+	}
+	var err error
+	e.client, err = elastic.NewClient(elasticOpts...)
+	if err != nil {
+		return nil, err
+	}
+	_, _, err = e.client.Ping(opts.URLs[0]).Do(ctx)
+	if err != nil {
+		e.client.Stop()
+		return nil, err
+	}
+	return e, nil
+}
+
+// Do executes a request according to its method
+func (e *Engine) Do(ctx context.Context, req *types.Request) (*types.Response, error) {
+	meta, err := parseMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	switch meta.method {
+	case "get":
+		return e.get(ctx, meta)
+	case "set":
+		return e.set(ctx, meta, req.Data)
+	case "delete":
+		return e.delete(ctx, meta)
+	case "index.exists":
+		return e.indexExists(ctx, meta)
+	case "index.create":
+		return e.indexCreate(ctx, meta, req.Data)
+	case "index.delete":
+		return e.indexDelete(ctx, meta)
+	case "search":
+		return e.search(ctx, meta, req.Data)
+	case "count":
+		return e.count(ctx, meta, req.Data)
+	case "bulk":
+		return e.bulk(ctx, meta, req.Data)
+	case "update_by_query":
+		return e.updateByQuery(ctx, meta, req.Data)
+	case "delete_by_query":
+		return e.deleteByQuery(ctx, meta, req.Data)
+	case "template.create":
+		return e.templateCreate(ctx, meta, req.Data)
+	case "template.get":
+		return e.templateGet(ctx, meta)
+	case "template.delete":
+		return e.templateDelete(ctx, meta)
+	case "alias.create":
+		return e.aliasCreate(ctx, meta, req.Data)
+	case "alias.delete":
+		return e.aliasDelete(ctx, meta)
+	case "alias.get":
+		return e.aliasGet(ctx, meta)
+	}
+	return nil, fmt.Errorf("invalid method")
+}
+
+func (e *Engine) get(ctx context.Context, meta metadata) (*types.Response, error) {
+	getResp, err := e.client.Get().Index(meta.index).Id(meta.id).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewResponse().
+		SetData(getResp.Source).
+		SetMetadataKeyValue("id", meta.id), nil
+}
+
+func (e *Engine) set(ctx context.Context, meta metadata, value []byte) (*types.Response, error) {
+	setResp, err := e.client.Index().Index(meta.index).Id(meta.id).Refresh(meta.refresh).BodyString(string(value)).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set document id %s: %s", meta.id, err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("id", setResp.Id).
+			SetMetadataKeyValue("result", setResp.Result),
+		nil
+}
+
+func (e *Engine) delete(ctx context.Context, meta metadata) (*types.Response, error) {
+	delResp, err := e.client.Delete().Index(meta.index).Id(meta.id).Refresh(meta.refresh).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete id '%s',%w", meta.id, err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("id", delResp.Id).
+			SetMetadataKeyValue("result", delResp.Result),
+		nil
+}
+
+// Close stops the elasticsearch client
+func (e *Engine) Close() error {
+	if e == nil || e.client == nil {
+		return nil
+	}
+	e.client.Stop()
+	return nil
+}
@@ -0,0 +1,279 @@
+package elasticengine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	method      string
+	path        string
+	query       string
+	contentType string
+	body        string
+}
+
+// fakeServer answers the elasticsearch api paths used by the engine with canned responses and records the requests
+type fakeServer struct {
+	mu        sync.Mutex
+	requests  []recordedRequest
+	responses map[string]string
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == "/" {
+		_, _ = w.Write([]byte(`{"version":{"number":"7.10.0"}}`))
+		return
+	}
+	f.mu.Lock()
+	f.requests = append(f.requests, recordedRequest{
+		method:      r.Method,
+		path:        r.URL.Path,
+		query:       r.URL.RawQuery,
+		contentType: r.Header.Get("Content-Type"),
+		body:        string(body),
+	})
+	resp, ok := f.responses[r.Method+" "+r.URL.Path]
+	f.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"type":"resource_not_found_exception"},"status":404}`))
+		return
+	}
+	_, _ = w.Write([]byte(resp))
+}
+
+func (f *fakeServer) last(t *testing.T) recordedRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	require.NotEmpty(t, f.requests)
+	return f.requests[len(f.requests)-1]
+}
+
+func newTestEngine(t *testing.T, responses map[string]string) (*Engine, *fakeServer) {
+	fake := &fakeServer{responses: responses}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	opts, err := ParseOptions(map[string]string{
+		"urls":  server.URL,
+		"sniff": "false",
+	})
+	require.NoError(t, err)
+	e, err := New(context.Background(), opts)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = e.Close()
+	})
+	return e, fake
+}
+
+func do(t *testing.T, e *Engine, meta map[string]string, data string) *types.Response {
+	req := types.NewRequest().SetMetadata(meta)
+	if data != "" {
+		req.SetData([]byte(data))
+	}
+	resp, err := e.Do(context.Background(), req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestEngine_Metadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "get",
+			meta: map[string]string{"method": "get", "index": "log", "id": "1"},
+		},
+		{
+			name:    "get - no id",
+			meta:    map[string]string{"method": "get", "index": "log"},
+			wantErr: true,
+		},
+		{
+			name:    "set - no index",
+			meta:    map[string]string{"method": "set", "id": "1"},
+			wantErr: true,
+		},
+		{
+			name: "search - all indices",
+			meta: map[string]string{"method": "search", "size": "10", "search_after": `[1, "a"]`},
+		},
+		{
+			name:    "search - bad search after",
+			meta:    map[string]string{"method": "search", "search_after": `{"a":1}`},
+			wantErr: true,
+		},
+		{
+			name:    "search - scroll with search after",
+			meta:    map[string]string{"method": "search", "scroll": "1m", "search_after": `[1]`},
+			wantErr: true,
+		},
+		{
+			name:    "update_by_query - no index",
+			meta:    map[string]string{"method": "update_by_query"},
+			wantErr: true,
+		},
+		{
+			name:    "update_by_query - bad conflicts",
+			meta:    map[string]string{"method": "update_by_query", "index": "log", "conflicts": "ignore"},
+			wantErr: true,
+		},
+		{
+			name:    "bulk - bad refresh",
+			meta:    map[string]string{"method": "bulk", "refresh": "now"},
+			wantErr: true,
+		},
+		{
+			name:    "template.create - no name",
+			meta:    map[string]string{"method": "template.create"},
+			wantErr: true,
+		},
+		{
+			name:    "alias.create - no alias",
+			meta:    map[string]string{"method": "alias.create", "index": "log"},
+			wantErr: true,
+		},
+		{
+			name:    "bad method",
+			meta:    map[string]string{"method": "reindex", "index": "log"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseMetadata(tt.meta)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEngine_Search(t *testing.T) {
+	e, fake := newTestEngine(t, map[string]string{
+		"POST /log/_search": `{"took":3,"hits":{"total":{"value":5,"relation":"eq"},"hits":[{"_index":"log","_id":"1","_source":{"data":"a"},"sort":[1672531200000,"1"]}]}}`,
+	})
+	resp := do(t, e, map[string]string{
+		"method":       "search",
+		"index":        "log",
+		"size":         "1",
+		"search_after": `[1672531199000,"0"]`,
+	}, `{"query":{"match_all":{}},"sort":[{"time":"asc"},{"id":"asc"}]}`)
+	require.Equal(t, "5", resp.Metadata["total"])
+	require.Equal(t, "1", resp.Metadata["count"])
+	require.Equal(t, `[1672531200000,"1"]`, resp.Metadata["search_after"])
+	require.Contains(t, string(resp.Data), `"_id":"1"`)
+	require.JSONEq(t, `{"query":{"match_all":{}},"sort":[{"time":"asc"},{"id":"asc"}],"size":1,"search_after":[1672531199000,"0"]}`, fake.last(t).body)
+}
+
+func TestEngine_Scroll(t *testing.T) {
+	e, fake := newTestEngine(t, map[string]string{
+		"POST /log/_search":      `{"_scroll_id":"scroll-1","hits":{"total":{"value":2,"relation":"eq"},"hits":[{"_index":"log","_id":"1","_source":{}}]}}`,
+		"POST /_search/scroll":   `{"_scroll_id":"scroll-1","hits":{"total":{"value":2,"relation":"eq"},"hits":[]}}`,
+		"DELETE /_search/scroll": `{"succeeded":true}`,
+	})
+	resp := do(t, e, map[string]string{"method": "search", "index": "log", "scroll": "30s", "size": "1"}, `{"query":{"match_all":{}}}`)
+	require.Equal(t, "scroll-1", resp.Metadata["scroll_id"])
+	require.Contains(t, fake.last(t).query, "scroll=30s")
+
+	resp = do(t, e, map[string]string{"method": "search", "scroll_id": "scroll-1"}, "")
+	require.Equal(t, "0", resp.Metadata["count"])
+	require.Empty(t, resp.Metadata["scroll_id"])
+	require.Equal(t, "DELETE", fake.last(t).method)
+}
+
+func TestEngine_Count(t *testing.T) {
+	e, fake := newTestEngine(t, map[string]string{
+		"POST /log,audit/_count": `{"count":42}`,
+	})
+	resp := do(t, e, map[string]string{"method": "count", "index": "log, audit"}, `{"query":{"term":{"level":"error"}}}`)
+	require.Equal(t, "42", resp.Metadata["count"])
+	require.JSONEq(t, `{"query":{"term":{"level":"error"}}}`, fake.last(t).body)
+}
+
+func TestEngine_Bulk(t *testing.T) {
+	e, fake := newTestEngine(t, map[string]string{
+		"POST /log/_bulk": `{"took":7,"errors":true,"items":[{"index":{"_index":"log","_id":"1","status":201,"result":"created"}},{"delete":{"_index":"log","_id":"2","status":404,"result":"not_found"}},{"create":{"_index":"log","_id":"3","status":409,"error":{"type":"version_conflict_engine_exception"}}}]}`,
+	})
+	actions := `{"index":{"_id":"1"}}
+{"data":"a"}
+{"delete":{"_id":"2"}}
+{"create":{"_id":"3"}}
+{"data":"c"}`
+	resp := do(t, e, map[string]string{"method": "bulk", "index": "log", "refresh": "wait_for"}, actions)
+	require.Equal(t, "3", resp.Metadata["count"])
+	require.Equal(t, "1", resp.Metadata["failed"])
+	require.Equal(t, "true", resp.Metadata["errors"])
+	var items []BulkItem
+	require.NoError(t, json.Unmarshal(resp.Data, &items))
+	require.Len(t, items, 3)
+	require.Equal(t, "create", items[2].Action)
+	require.NotEmpty(t, items[2].Error)
+	req := fake.last(t)
+	require.Equal(t, "application/x-ndjson", req.contentType)
+	require.Equal(t, "refresh=wait_for", req.query)
+	require.Equal(t, actions+"\n", req.body)
+
+	_, err := e.Do(context.Background(), types.NewRequest().SetMetadataKeyValue("method", "bulk"))
+	require.Error(t, err)
+}
+
+func TestEngine_ByQuery(t *testing.T) {
+	e, fake := newTestEngine(t, map[string]string{
+		"POST /log/_update_by_query": `{"took":5,"total":3,"updated":3,"version_conflicts":0}`,
+		"POST /log/_delete_by_query": `{"took":5,"total":2,"deleted":2,"version_conflicts":1}`,
+	})
+	resp := do(t, e, map[string]string{"method": "update_by_query", "index": "log", "conflicts": "proceed"}, `{"query":{"term":{"level":"error"}},"script":{"source":"ctx._source.seen = true"}}`)
+	require.Equal(t, "3", resp.Metadata["updated"])
+	require.Contains(t, fake.last(t).query, "conflicts=proceed")
+
+	resp = do(t, e, map[string]string{"method": "delete_by_query", "index": "log"}, `{"query":{"term":{"level":"debug"}}}`)
+	require.Equal(t, "2", resp.Metadata["deleted"])
+	require.Equal(t, "1", resp.Metadata["version_conflicts"])
+
+	_, err := e.Do(context.Background(), types.NewRequest().SetMetadata(map[string]string{"method": "delete_by_query", "index": "log"}))
+	require.Error(t, err)
+}
+
+func TestEngine_TemplatesAndAliases(t *testing.T) {
+	e, fake := newTestEngine(t, map[string]string{
+		"PUT /_index_template/logs":    `{"acknowledged":true}`,
+		"GET /_index_template/logs":    `{"index_templates":[{"name":"logs"}]}`,
+		"DELETE /_index_template/logs": `{"acknowledged":true}`,
+		"POST /_aliases":               `{"acknowledged":true}`,
+		"GET /log-1/_alias/logs":       `{"log-1":{"aliases":{"logs":{}}}}`,
+	})
+	resp := do(t, e, map[string]string{"method": "template.create", "name": "logs"}, `{"index_patterns":["log-*"]}`)
+	require.Equal(t, "true", resp.Metadata["acknowledged"])
+	require.JSONEq(t, `{"index_patterns":["log-*"]}`, fake.last(t).body)
+	resp = do(t, e, map[string]string{"method": "template.get", "name": "logs"}, "")
+	require.JSONEq(t, `{"index_templates":[{"name":"logs"}]}`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "template.delete", "name": "logs"}, "")
+	require.Equal(t, "true", resp.Metadata["acknowledged"])
+	_, err := e.Do(context.Background(), types.NewRequest().SetMetadata(map[string]string{"method": "template.get", "name": "missing"}))
+	require.Error(t, err)
+
+	resp = do(t, e, map[string]string{"method": "alias.create", "index": "log-1", "alias": "logs"}, `{"term":{"level":"error"}}`)
+	require.Equal(t, "true", resp.Metadata["acknowledged"])
+	require.JSONEq(t, `{"actions":[{"add":{"alias":"logs","filter":{"term":{"level":"error"}},"index":"log-1"}}]}`, fake.last(t).body)
+	resp = do(t, e, map[string]string{"method": "alias.get", "index": "log-1", "alias": "logs"}, "")
+	require.JSONEq(t, `{"log-1":["logs"]}`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "alias.delete", "index": "log-1", "alias": "logs"}, "")
+	require.Equal(t, "true", resp.Metadata["acknowledged"])
+	require.JSONEq(t, `{"actions":[{"remove":{"alias":"logs","index":"log-1"}}]}`, fake.last(t).body)
+}
@@ -0,0 +1,154 @@
+package elasticengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/olivere/elastic/v7"
+)
+
+func (e *Engine) indexExists(ctx context.Context, meta metadata) (*types.Response, error) {
+	exists, err := e.client.IndexExists(meta.index).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute index exist '%s',%w", meta.index, err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("exists", fmt.Sprintf("%t", exists)),
+		nil
+}
+
+func (e *Engine) indexCreate(ctx context.Context, meta metadata, value []byte) (*types.Response, error) {
+	result, err := e.client.CreateIndex(meta.index).BodyString(string(value)).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create index'%s',%w", meta.index, err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("acknowledged", fmt.Sprintf("%t", result.Acknowledged)).
+			SetMetadataKeyValue("shards_acknowledged", fmt.Sprintf("%t", result.ShardsAcknowledged)).
+			SetMetadataKeyValue("index", result.Index),
+		nil
+}
+
+func (e *Engine) indexDelete(ctx context.Context, meta metadata) (*types.Response, error) {
+	result, err := e.client.DeleteIndex(meta.index).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete index'%s',%w", meta.index, err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("acknowledged", fmt.Sprintf("%t", result.Acknowledged)),
+		nil
+}
+
+// templateRequest sends a composable index template request, the template api is not covered by the elastic client
+func (e *Engine) templateRequest(ctx context.Context, method, name string, body interface{}) (*elastic.Response, error) {
+	return e.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: method,
+		Path:   "/_index_template/" + url.PathEscape(name),
+		Body:   body,
+	})
+}
+
+func (e *Engine) templateCreate(ctx context.Context, meta metadata, value []byte) (*types.Response, error) {
+	if len(value) == 0 {
+		return nil, fmt.Errorf("failed to create template '%s', template body is missing", meta.name)
+	}
+	res, err := e.templateRequest(ctx, "PUT", meta.name, string(value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template '%s',%w", meta.name, err)
+	}
+	return newAcknowledgedResponse(res)
+}
+
+func (e *Engine) templateGet(ctx context.Context, meta metadata) (*types.Response, error) {
+	res, err := e.templateRequest(ctx, "GET", meta.name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template '%s',%w", meta.name, err)
+	}
+	return types.NewResponse().
+			SetData(res.Body).
+			SetMetadataKeyValue("name", meta.name).
+			SetMetadataKeyValue("result", "ok"),
+		nil
+}
+
+func (e *Engine) templateDelete(ctx context.Context, meta metadata) (*types.Response, error) {
+	res, err := e.templateRequest(ctx, "DELETE", meta.name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete template '%s',%w", meta.name, err)
+	}
+	return newAcknowledgedResponse(res)
+}
+
+func newAcknowledgedResponse(res *elastic.Response) (*types.Response, error) {
+	result := elastic.AcknowledgedResponse{}
+	if err := json.Unmarshal(res.Body, &result); err != nil {
+		return nil, fmt.Errorf("response parsing error, %w", err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("acknowledged", fmt.Sprintf("%t", result.Acknowledged)),
+		nil
+}
+
+// aliasCreate adds an alias to the request indices, the request data is an optional filter query of the alias
+func (e *Engine) aliasCreate(ctx context.Context, meta metadata, value []byte) (*types.Response, error) {
+	service := e.client.Alias()
+	for _, index := range meta.indices() {
+		if len(value) > 0 {
+			service = service.AddWithFilter(index, meta.alias, elastic.NewRawStringQuery(string(value)))
+		} else {
+			service = service.Add(index, meta.alias)
+		}
+	}
+	result, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alias '%s',%w", meta.alias, err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("acknowledged", fmt.Sprintf("%t", result.Acknowledged)),
+		nil
+}
+
+func (e *Engine) aliasDelete(ctx context.Context, meta metadata) (*types.Response, error) {
+	service := e.client.Alias()
+	for _, index := range meta.indices() {
+		service = service.Remove(index, meta.alias)
+	}
+	result, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete alias '%s',%w", meta.alias, err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("acknowledged", fmt.Sprintf("%t", result.Acknowledged)),
+		nil
+}
+
+// aliasGet returns the aliases of the request indices as a json object of index name to alias names
+func (e *Engine) aliasGet(ctx context.Context, meta metadata) (*types.Response, error) {
+	service := e.client.Aliases().Index(meta.indices()...)
+	if meta.alias != "" {
+		service = service.Alias(meta.alias)
+	}
+	result, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aliases,%w", err)
+	}
+	aliases := map[string][]string{}
+	for index, indexResult := range result.Indices {
+		names := []string{}
+		for _, alias := range indexResult.Aliases {
+			names = append(names, alias.AliasName)
+		}
+		aliases[index] = names
+	}
+	data, err := json.Marshal(aliases)
+	if err != nil {
+		return nil, fmt.Errorf("aliases parsing error, %w", err)
+	}
+	return types.NewResponse().
+			SetData(data).
+			SetMetadataKeyValue("result", "ok"),
+		nil
+}
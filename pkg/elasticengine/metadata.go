@@ -0,0 +1,186 @@
+package elasticengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+var methodsMap = map[string]string{
+	"get":             "get",
+	"set":             "set",
+	"delete":          "delete",
+	"index.exists":    "index.exists",
+	"index.create":    "index.create",
+	"index.delete":    "index.delete",
+	"search":          "search",
+	"count":           "count",
+	"bulk":            "bulk",
+	"update_by_query": "update_by_query",
+	"delete_by_query": "delete_by_query",
+	"template.create": "template.create",
+	"template.get":    "template.get",
+	"template.delete": "template.delete",
+	"alias.create":    "alias.create",
+	"alias.delete":    "alias.delete",
+	"alias.get":       "alias.get",
+}
+
+// optionalIndexMethods are the methods which run on all indices when the request does not set an index
+var optionalIndexMethods = map[string]bool{
+	"search":          true,
+	"count":           true,
+	"bulk":            true,
+	"template.create": true,
+	"template.get":    true,
+	"template.delete": true,
+	"alias.get":       true,
+}
+
+var refreshMap = map[string]string{
+	"":         "",
+	"true":     "true",
+	"false":    "false",
+	"wait_for": "wait_for",
+}
+
+var conflictsMap = map[string]string{
+	"":        "",
+	"abort":   "abort",
+	"proceed": "proceed",
+}
+
+// defaultScrollKeepAlive is the scroll keep alive when a scroll id is sent without the scroll metadata
+const defaultScrollKeepAlive = "1m"
+
+type metadata struct {
+	method      string
+	index       string
+	id          string
+	name        string
+	alias       string
+	size        int
+	from        int
+	searchAfter []interface{}
+	scroll      string
+	scrollID    string
+	refresh     string
+	conflicts   string
+}
+
+// indices returns the request comma separated indices
+func (m metadata) indices() []string {
+	var indices []string
+	for _, index := range strings.Split(m.index, ",") {
+		if index = strings.TrimSpace(index); index != "" {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
+
+func parseMetadata(meta types.Metadata) (metadata, error) {
+	m := metadata{}
+	var err error
+	m.method, err = meta.ParseStringMap("method", methodsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing method, %w", err)
+	}
+	if optionalIndexMethods[m.method] {
+		m.index = meta.ParseString("index", "")
+	} else {
+		m.index, err = meta.MustParseString("index")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing index value, %w", err)
+		}
+	}
+	switch m.method {
+	case "set", "get", "delete":
+		m.id, err = meta.MustParseString("id")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing id value, %w", err)
+		}
+	case "template.create", "template.get", "template.delete":
+		m.name, err = meta.MustParseString("name")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing template name, %w", err)
+		}
+	case "alias.create", "alias.delete":
+		m.alias, err = meta.MustParseString("alias")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing alias, %w", err)
+		}
+	case "alias.get":
+		m.alias = meta.ParseString("alias", "")
+	case "search":
+		m.size, err = meta.ParseIntWithRange("size", 0, 0, math.MaxInt32)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing size, %w", err)
+		}
+		m.from, err = meta.ParseIntWithRange("from", 0, 0, math.MaxInt32)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing from, %w", err)
+		}
+		if searchAfter := meta.ParseString("search_after", ""); searchAfter != "" {
+			m.searchAfter, err = parseJSONArray(searchAfter)
+			if err != nil {
+				return metadata{}, fmt.Errorf("error parsing search_after, a json array is expected, %w", err)
+			}
+		}
+		m.scroll = meta.ParseString("scroll", "")
+		m.scrollID = meta.ParseString("scroll_id", "")
+		if m.scrollID != "" && m.scroll == "" {
+			m.scroll = defaultScrollKeepAlive
+		}
+		if m.scroll != "" && m.searchAfter != nil {
+			return metadata{}, fmt.Errorf("error parsing search_after, search_after cannot be set with scroll")
+		}
+	}
+	m.refresh, err = meta.ParseStringMap("refresh", refreshMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing refresh, %w", err)
+	}
+	m.conflicts, err = meta.ParseStringMap("conflicts", conflictsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing conflicts, %w", err)
+	}
+	return m, nil
+}
+
+// parseJSONArray parses a json array keeping the numbers as is, e.g. long sort values
+func parseJSONArray(value string) ([]interface{}, error) {
+	var array []interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&array); err != nil {
+		return nil, err
+	}
+	if len(array) == 0 {
+		return nil, fmt.Errorf("empty array")
+	}
+	return array, nil
+}
+
+// parseBody parses the request json object body, an empty body returns an empty object
+func parseBody(data []byte) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return body, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		return nil, fmt.Errorf("a json object is expected, %w", err)
+	}
+	return body, nil
+}
+
+// IsMethod reports whether the method is executed by the engine
+func IsMethod(method string) bool {
+	_, ok := methodsMap[method]
+	return ok
+}
@@ -0,0 +1,34 @@
+package elasticengine
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+// Options holds the connection settings shared by the elasticsearch targets
+type Options struct {
+	// URLs are the elasticsearch nodes, the first url is pinged on connect
+	URLs []string
+	// Sniff enables discovering the cluster nodes, managed services usually require it disabled
+	Sniff    bool
+	Username string
+	Password string
+	// HTTPClient sends the requests when set, e.g. a client signing the requests of a managed service
+	HTTPClient *http.Client
+}
+
+// ParseOptions parses the connection properties
+func ParseOptions(props types.Metadata) (Options, error) {
+	o := Options{}
+	var err error
+	o.URLs, err = props.MustParseStringList("urls")
+	if err != nil {
+		return Options{}, fmt.Errorf("error parsing urls, %w", err)
+	}
+	o.Sniff = props.ParseBool("sniff", true)
+	o.Username = props.ParseString("username", "")
+	o.Password = props.ParseString("password", "")
+	return o, nil
+}
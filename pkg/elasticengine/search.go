@@ -0,0 +1,150 @@
+package elasticengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/olivere/elastic/v7"
+)
+
+// search runs a query dsl search, the size, from and search_after metadata override the request body values,
+// a search with the scroll metadata returns the scroll id of the next page until the last page
+func (e *Engine) search(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	body, err := parseBody(data)
+	if err != nil {
+		return nil, fmt.Errorf("search body parsing error, %w", err)
+	}
+	if meta.size > 0 {
+		body["size"] = meta.size
+	}
+	if meta.from > 0 {
+		body["from"] = meta.from
+	}
+	if meta.searchAfter != nil {
+		body["search_after"] = meta.searchAfter
+	}
+	var result *elastic.SearchResult
+	if meta.scroll != "" {
+		scroll := e.client.Scroll(meta.indices()...).Scroll(meta.scroll)
+		if meta.scrollID != "" {
+			scroll = scroll.ScrollId(meta.scrollID)
+		} else {
+			scroll = scroll.Body(body)
+		}
+		result, err = scroll.Do(ctx)
+		if errors.Is(err, io.EOF) {
+			// the last page was returned by the previous request, the scroll is cleared
+			_ = scroll.Clear(ctx)
+			if result == nil {
+				result = &elastic.SearchResult{}
+			}
+			result.ScrollId = ""
+			err = nil
+		}
+	} else {
+		result, err = e.client.Search(meta.indices()...).Source(body).Do(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("search error, %w", err)
+	}
+	return newSearchResponse(result)
+}
+
+func newSearchResponse(result *elastic.SearchResult) (*types.Response, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("search result parsing error, %w", err)
+	}
+	resp := types.NewResponse().
+		SetData(data).
+		SetMetadataKeyValue("took", fmt.Sprintf("%d", result.TookInMillis))
+	count := 0
+	if result.Hits != nil {
+		count = len(result.Hits.Hits)
+		if result.Hits.TotalHits != nil {
+			resp.SetMetadataKeyValue("total", fmt.Sprintf("%d", result.Hits.TotalHits.Value))
+		}
+		// search_after holds the sort values of the last hit, to be sent by the request of the next page
+		if count > 0 && len(result.Hits.Hits[count-1].Sort) > 0 {
+			searchAfter, err := json.Marshal(result.Hits.Hits[count-1].Sort)
+			if err != nil {
+				return nil, fmt.Errorf("search after parsing error, %w", err)
+			}
+			resp.SetMetadataKeyValue("search_after", string(searchAfter))
+		}
+	}
+	resp.SetMetadataKeyValue("count", fmt.Sprintf("%d", count))
+	if result.ScrollId != "" {
+		resp.SetMetadataKeyValue("scroll_id", result.ScrollId)
+	}
+	return resp.SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (e *Engine) count(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	body, err := parseBody(data)
+	if err != nil {
+		return nil, fmt.Errorf("count body parsing error, %w", err)
+	}
+	service := e.client.Count(meta.indices()...)
+	if len(body) > 0 {
+		service = service.BodyJson(body)
+	}
+	count, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count error, %w", err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("count", fmt.Sprintf("%d", count)).
+			SetMetadataKeyValue("result", "ok"),
+		nil
+}
+
+// updateByQuery updates the documents matching the request body query with the request body script
+func (e *Engine) updateByQuery(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	service := e.client.UpdateByQuery(meta.indices()...).
+		Conflicts(meta.conflicts).
+		Refresh(meta.refresh)
+	if len(data) > 0 {
+		service = service.Body(string(data))
+	}
+	result, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("update by query error, %w", err)
+	}
+	return newByQueryResponse(result)
+}
+
+// deleteByQuery deletes the documents matching the request body query
+func (e *Engine) deleteByQuery(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("delete by query error, query body is missing")
+	}
+	result, err := e.client.DeleteByQuery(meta.indices()...).
+		Conflicts(meta.conflicts).
+		Refresh(meta.refresh).
+		Body(string(data)).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("delete by query error, %w", err)
+	}
+	return newByQueryResponse(result)
+}
+
+func newByQueryResponse(result *elastic.BulkIndexByScrollResponse) (*types.Response, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("by query result parsing error, %w", err)
+	}
+	return types.NewResponse().
+			SetData(data).
+			SetMetadataKeyValue("total", fmt.Sprintf("%d", result.Total)).
+			SetMetadataKeyValue("updated", fmt.Sprintf("%d", result.Updated)).
+			SetMetadataKeyValue("deleted", fmt.Sprintf("%d", result.Deleted)).
+			SetMetadataKeyValue("version_conflicts", fmt.Sprintf("%d", result.VersionConflicts)).
+			SetMetadataKeyValue("result", "ok"),
+		nil
+}
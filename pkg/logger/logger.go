@@ -56,7 +56,7 @@ func initCore() zapcore.Core {
 	}
 	return zapcore.NewCore(
 		enc,
-		zapcore.AddSync(w),
+		redactWriteSyncer{WriteSyncer: zapcore.AddSync(w)},
 		zapcore.DebugLevel)
 }
 
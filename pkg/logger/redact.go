@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	secretsMu sync.RWMutex
+	secrets   = map[string][]byte{}
+	redacted  = []byte("******")
+)
+
+// AddSecret registers a secret value which is masked in every log line
+func AddSecret(secret string) {
+	if secret == "" {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets[secret] = []byte(secret)
+}
+
+func redact(p []byte) []byte {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, secret := range secrets {
+		if bytes.Contains(p, secret) {
+			p = bytes.ReplaceAll(p, secret, redacted)
+		}
+	}
+	return p
+}
+
+// redactWriteSyncer masks registered secrets before writing the log entry
+type redactWriteSyncer struct {
+	zapcore.WriteSyncer
+}
+
+func (r redactWriteSyncer) Write(p []byte) (int, error) {
+	if _, err := r.WriteSyncer.Write(redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -2,12 +2,16 @@ package metrics
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-var labels = []string{"binding", "source_kind", "target_kind"}
+var (
+	labels           = []string{"binding", "source_kind", "target_kind", "route"}
+	transitionLabels = []string{"binding", "source_kind", "target_kind", "route", "from", "to"}
+)
 
 type Exporter struct {
 	Store                    *Store
@@ -16,6 +20,12 @@ type Exporter struct {
 	requestsVolumeCollector  *promCounterMetric
 	responsesVolumeCollector *promCounterMetric
 	errorsCollector          *promCounterMetric
+	cbTransitionsCollector   *promCounterMetric
+	cbStateCollector         *promGaugeMetric
+	durationCollector        *promHistogramMetric
+	inFlightCollector        *promGaugeMetric
+	retriesCollector         *promCounterMetric
+	rateLimitWaitCollector   *promHistogramMetric
 }
 
 func (e *Exporter) PrometheusHandler() http.Handler {
@@ -30,6 +40,12 @@ func NewExporter() (*Exporter, error) {
 		requestsVolumeCollector:  nil,
 		responsesVolumeCollector: nil,
 		errorsCollector:          nil,
+		cbTransitionsCollector:   nil,
+		cbStateCollector:         nil,
+		durationCollector:        nil,
+		inFlightCollector:        nil,
+		retriesCollector:         nil,
+		rateLimitWaitCollector:   nil,
 	}
 	if err := e.initPromMetrics(); err != nil {
 		return nil, err
@@ -41,31 +57,69 @@ func (e *Exporter) initPromMetrics() error {
 	e.requestsCollector = newPromCounterMetric(
 		"requests",
 		"count",
-		"counts requests per binding,source and target types",
+		"counts requests per binding,source,target types and route",
 		labels...,
 	)
 	e.responsesCollector = newPromCounterMetric(
 		"responses",
 		"count",
-		"counts responses per binding,source and target types",
+		"counts responses per binding,source,target types and route",
 		labels...,
 	)
 	e.requestsVolumeCollector = newPromCounterMetric(
 		"requests",
 		"volume",
-		"sum requests volume per binding,source and target types",
+		"sum requests volume per binding,source,target types and route",
 		labels...,
 	)
 	e.responsesVolumeCollector = newPromCounterMetric(
 		"responses",
 		"volume",
-		"sum responses volume per binding,source and target types",
+		"sum responses volume per binding,source,target types and route",
 		labels...,
 	)
 	e.errorsCollector = newPromCounterMetric(
 		"errors",
 		"count",
-		"counts error requests per binding,source and target types",
+		"counts error requests per binding,source,target types and route",
+		labels...,
+	)
+	e.cbTransitionsCollector = newPromCounterMetric(
+		"circuit_breaker",
+		"transitions",
+		"counts circuit breaker state transitions per binding,source,target types and route",
+		transitionLabels...,
+	)
+	e.cbStateCollector = newPromGaugeMetric(
+		"circuit_breaker",
+		"state",
+		"circuit breaker state per binding,source,target types and route (0 - closed, 1 - half-open, 2 - open)",
+		labels...,
+	)
+	e.durationCollector = newPromHistogramMetric(
+		"requests",
+		"duration_seconds",
+		"requests duration in seconds per binding,source,target types and route",
+		prometheus.DefBuckets,
+		labels...,
+	)
+	e.inFlightCollector = newPromGaugeMetric(
+		"requests",
+		"in_flight",
+		"requests in flight per binding,source,target types and route",
+		labels...,
+	)
+	e.retriesCollector = newPromCounterMetric(
+		"retries",
+		"count",
+		"counts target execution retries per binding,source,target types and route",
+		labels...,
+	)
+	e.rateLimitWaitCollector = newPromHistogramMetric(
+		"rate_limiter",
+		"wait_seconds",
+		"rate limiter wait time in seconds per binding,source,target types and route",
+		prometheus.DefBuckets,
 		labels...,
 	)
 
@@ -89,6 +143,30 @@ func (e *Exporter) initPromMetrics() error {
 	if err != nil {
 		return err
 	}
+	err = prometheus.Register(e.cbTransitionsCollector.metric)
+	if err != nil {
+		return err
+	}
+	err = prometheus.Register(e.cbStateCollector.metric)
+	if err != nil {
+		return err
+	}
+	err = prometheus.Register(e.durationCollector.metric)
+	if err != nil {
+		return err
+	}
+	err = prometheus.Register(e.inFlightCollector.metric)
+	if err != nil {
+		return err
+	}
+	err = prometheus.Register(e.retriesCollector.metric)
+	if err != nil {
+		return err
+	}
+	err = prometheus.Register(e.rateLimitWaitCollector.metric)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -102,3 +180,45 @@ func (e *Exporter) Report(m *Report) {
 	e.errorsCollector.add(m.ErrorsCount, lbs)
 	e.Store.Add(m)
 }
+
+func (e *Exporter) ReportCircuitBreakerTransition(m *Report, from, to string, state float64) {
+	lbs := m.labels()
+	e.cbStateCollector.set(state, lbs)
+	lbs["from"] = from
+	lbs["to"] = to
+	e.cbTransitionsCollector.add(1, lbs)
+}
+
+func (e *Exporter) ReportDuration(m *Report, d time.Duration) {
+	e.durationCollector.observe(d.Seconds(), m.labels())
+	e.Store.AddLatency(m, float64(d)/float64(time.Millisecond))
+}
+
+func (e *Exporter) ReportInFlight(m *Report, delta float64) {
+	e.inFlightCollector.add(delta, m.labels())
+	e.Store.AddInFlight(m, delta)
+}
+
+func (e *Exporter) ReportRetry(m *Report) {
+	e.retriesCollector.add(1, m.labels())
+	e.Store.Add(&Report{
+		Key:          m.Key,
+		Binding:      m.Binding,
+		SourceKind:   m.SourceKind,
+		TargetKind:   m.TargetKind,
+		Route:        m.Route,
+		RetriesCount: 1,
+	})
+}
+
+func (e *Exporter) ReportRateLimitWait(m *Report, d time.Duration) {
+	e.rateLimitWaitCollector.observe(d.Seconds(), m.labels())
+	e.Store.Add(&Report{
+		Key:           m.Key,
+		Binding:       m.Binding,
+		SourceKind:    m.SourceKind,
+		TargetKind:    m.TargetKind,
+		Route:         m.Route,
+		RateLimitWait: float64(d) / float64(time.Millisecond),
+	})
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+const latencyWindowSize = 1024
+
+// latencyWindow keeps the latest request durations of a report key for percentiles calculation
+type latencyWindow struct {
+	sync.Mutex
+	samples []float64
+	pos     int
+}
+
+func newLatencyWindow() *latencyWindow {
+	return &latencyWindow{
+		samples: make([]float64, 0, latencyWindowSize),
+	}
+}
+
+func (l *latencyWindow) add(value float64) {
+	l.Lock()
+	defer l.Unlock()
+	if len(l.samples) < latencyWindowSize {
+		l.samples = append(l.samples, value)
+		return
+	}
+	l.samples[l.pos] = value
+	l.pos = (l.pos + 1) % latencyWindowSize
+}
+
+// percentiles returns the values of the requested percentiles (0-100) using nearest rank
+func (l *latencyWindow) percentiles(ps ...float64) []float64 {
+	l.Lock()
+	sorted := append([]float64{}, l.samples...)
+	l.Unlock()
+	result := make([]float64, len(ps))
+	if len(sorted) == 0 {
+		return result
+	}
+	sort.Float64s(sorted)
+	for i, p := range ps {
+		rank := int(p/100*float64(len(sorted))+0.5) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		if rank >= len(sorted) {
+			rank = len(sorted) - 1
+		}
+		result[i] = sorted[rank]
+	}
+	return result
+}
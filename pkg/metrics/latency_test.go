@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyWindow_Percentiles(t *testing.T) {
+	l := newLatencyWindow()
+	require.Equal(t, []float64{0, 0}, l.percentiles(50, 99))
+	for i := 1; i <= 100; i++ {
+		l.add(float64(i))
+	}
+	require.Equal(t, []float64{50, 90, 99, 100}, l.percentiles(50, 90, 99, 100))
+	for i := 0; i < latencyWindowSize; i++ {
+		l.add(1000)
+	}
+	require.Len(t, l.samples, latencyWindowSize)
+	require.Equal(t, []float64{1000}, l.percentiles(50))
+}
@@ -27,3 +27,52 @@ func (c *promCounterMetric) add(value float64, labels prometheus.Labels) {
 		c.metric.With(labels).Add(value)
 	}
 }
+
+type promGaugeMetric struct {
+	metric *prometheus.GaugeVec
+}
+
+func newPromGaugeMetric(subsystem, name, help string, labels ...string) *promGaugeMetric {
+	opts := prometheus.GaugeOpts{
+		Namespace:   "kubemq_targets",
+		Subsystem:   subsystem,
+		Name:        name,
+		Help:        help,
+		ConstLabels: nil,
+	}
+
+	c := &promGaugeMetric{}
+	c.metric = prometheus.NewGaugeVec(opts, labels)
+	return c
+}
+
+func (c *promGaugeMetric) set(value float64, labels prometheus.Labels) {
+	c.metric.With(labels).Set(value)
+}
+
+func (c *promGaugeMetric) add(value float64, labels prometheus.Labels) {
+	c.metric.With(labels).Add(value)
+}
+
+type promHistogramMetric struct {
+	metric *prometheus.HistogramVec
+}
+
+func newPromHistogramMetric(subsystem, name, help string, buckets []float64, labels ...string) *promHistogramMetric {
+	opts := prometheus.HistogramOpts{
+		Namespace:   "kubemq_targets",
+		Subsystem:   subsystem,
+		Name:        name,
+		Help:        help,
+		ConstLabels: nil,
+		Buckets:     buckets,
+	}
+
+	c := &promHistogramMetric{}
+	c.metric = prometheus.NewHistogramVec(opts, labels)
+	return c
+}
+
+func (c *promHistogramMetric) observe(value float64, labels prometheus.Labels) {
+	c.metric.With(labels).Observe(value)
+}
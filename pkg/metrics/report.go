@@ -9,11 +9,18 @@ type Report struct {
 	Binding        string  `json:"binding"`
 	SourceKind     string  `json:"source_kind"`
 	TargetKind     string  `json:"target_kind"`
+	Route          string  `json:"route,omitempty"`
 	RequestCount   float64 `json:"request_count"`
 	RequestVolume  float64 `json:"request_volume"`
 	ResponseCount  float64 `json:"response_count"`
 	ResponseVolume float64 `json:"response_volume"`
 	ErrorsCount    float64 `json:"errors_count"`
+	RetriesCount   float64 `json:"retries_count"`
+	RateLimitWait  float64 `json:"rate_limit_wait_ms"`
+	InFlight       float64 `json:"in_flight"`
+	LatencyP50     float64 `json:"latency_p50_ms"`
+	LatencyP90     float64 `json:"latency_p90_ms"`
+	LatencyP99     float64 `json:"latency_p99_ms"`
 }
 
 func (m *Report) labels() prometheus.Labels {
@@ -21,6 +28,7 @@ func (m *Report) labels() prometheus.Labels {
 		"binding":     m.Binding,
 		"source_kind": m.SourceKind,
 		"target_kind": m.TargetKind,
+		"route":       m.Route,
 	}
 }
 
@@ -30,10 +38,17 @@ func (m *Report) Clone() *Report {
 		Binding:        m.Binding,
 		SourceKind:     m.SourceKind,
 		TargetKind:     m.TargetKind,
+		Route:          m.Route,
 		RequestCount:   m.RequestCount,
 		RequestVolume:  m.RequestVolume,
 		ResponseCount:  m.ResponseCount,
 		ResponseVolume: m.ResponseVolume,
 		ErrorsCount:    m.ErrorsCount,
+		RetriesCount:   m.RetriesCount,
+		RateLimitWait:  m.RateLimitWait,
+		InFlight:       m.InFlight,
+		LatencyP50:     m.LatencyP50,
+		LatencyP90:     m.LatencyP90,
+		LatencyP99:     m.LatencyP99,
 	}
 }
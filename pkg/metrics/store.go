@@ -3,41 +3,82 @@ package metrics
 import "sync"
 
 type Store struct {
-	store sync.Map
+	sync.Mutex
+	store     sync.Map
+	latencies sync.Map
+	inFlight  sync.Map
 }
 
 func NewStore() *Store {
 	return &Store{
-		store: sync.Map{},
+		store:     sync.Map{},
+		latencies: sync.Map{},
+		inFlight:  sync.Map{},
 	}
 }
 
+func (s *Store) load(report *Report) *Report {
+	val, _ := s.store.LoadOrStore(report.Key, &Report{
+		Key:        report.Key,
+		Binding:    report.Binding,
+		SourceKind: report.SourceKind,
+		TargetKind: report.TargetKind,
+		Route:      report.Route,
+	})
+	return val.(*Report)
+}
+
 func (s *Store) Add(report *Report) {
-	val, ok := s.store.Load(report.Key)
-	if ok {
-		loaded := val.(*Report)
-		loaded.ErrorsCount += report.ErrorsCount
-		loaded.ResponseVolume += report.ResponseVolume
-		loaded.ResponseCount += report.ResponseCount
-		loaded.RequestVolume += report.RequestVolume
-		loaded.RequestCount += report.RequestCount
-	} else {
-		s.store.Store(report.Key, report.Clone())
-	}
+	s.Lock()
+	defer s.Unlock()
+	loaded := s.load(report)
+	loaded.ErrorsCount += report.ErrorsCount
+	loaded.ResponseVolume += report.ResponseVolume
+	loaded.ResponseCount += report.ResponseCount
+	loaded.RequestVolume += report.RequestVolume
+	loaded.RequestCount += report.RequestCount
+	loaded.RetriesCount += report.RetriesCount
+	loaded.RateLimitWait += report.RateLimitWait
+}
+
+func (s *Store) AddLatency(report *Report, milliseconds float64) {
+	val, _ := s.latencies.LoadOrStore(report.Key, newLatencyWindow())
+	val.(*latencyWindow).add(milliseconds)
+}
+
+func (s *Store) AddInFlight(report *Report, value float64) {
+	s.Lock()
+	defer s.Unlock()
+	val, _ := s.inFlight.LoadOrStore(report.Key, new(float64))
+	*val.(*float64) += value
 }
 
 func (s *Store) Get(key string) *Report {
 	val, ok := s.store.Load(key)
 	if ok {
-		return val.(*Report)
+		return s.withLatency(val.(*Report))
 	}
 	return nil
 }
 
+func (s *Store) withLatency(report *Report) *Report {
+	s.Lock()
+	r := report.Clone()
+	if val, ok := s.inFlight.Load(report.Key); ok {
+		r.InFlight = *val.(*float64)
+	}
+	s.Unlock()
+	if val, ok := s.latencies.Load(report.Key); ok {
+		ps := val.(*latencyWindow).percentiles(50, 90, 99)
+		r.LatencyP50, r.LatencyP90, r.LatencyP99 = ps[0], ps[1], ps[2]
+	}
+	return r
+}
+
 func (s *Store) List() []*Report {
 	var list []*Report
 	s.store.Range(func(key, value interface{}) bool {
-		list = append(list, value.(*Report))
+		list = append(list, s.withLatency(value.(*Report)))
 		return true
 	})
 	return list
@@ -0,0 +1,448 @@
+package redisengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	redisClient "github.com/go-redis/redis/v7"
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+// ScoredMember is a sorted set member with its score
+type ScoredMember struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+}
+
+// StreamMessage is a stream entry with its id and fields
+type StreamMessage struct {
+	ID     string                 `json:"id"`
+	Values map[string]interface{} `json:"values"`
+}
+
+func parseStringList(data []byte) ([]string, error) {
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("error parsing data, a json array of strings is expected, %w", err)
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("error parsing data, no values found")
+	}
+	return list, nil
+}
+
+func parseStringMap(data []byte) (map[string]string, error) {
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("error parsing data, a json object of strings is expected, %w", err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("error parsing data, no values found")
+	}
+	return values, nil
+}
+
+func toInterfaces(list []string) []interface{} {
+	values := make([]interface{}, len(list))
+	for i, value := range list {
+		values[i] = value
+	}
+	return values
+}
+
+func jsonResponse(key string, value interface{}) (*types.Response, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	resp := types.NewResponse().SetData(data)
+	if key != "" {
+		resp.SetMetadataKeyValue("key", key)
+	}
+	return resp, nil
+}
+
+func countResponse(key string, count int64) *types.Response {
+	resp := types.NewResponse().
+		SetMetadataKeyValue("count", strconv.FormatInt(count, 10)).
+		SetMetadataKeyValue("result", "ok")
+	if key != "" {
+		resp.SetMetadataKeyValue("key", key)
+	}
+	return resp
+}
+
+// expire sets the ttl of a key when a ttl is set in the request
+func expire(pipe redisClient.Pipeliner, meta metadata) {
+	if meta.ttl > 0 {
+		pipe.Expire(meta.key, meta.ttl)
+	}
+}
+
+// mGet reads the keys in a single pipeline, the values written by set are read from their hash like get does, missing keys are returned as null
+func (e *Engine) mGet(ctx context.Context, data []byte) (*types.Response, error) {
+	keys, err := parseStringList(data)
+	if err != nil {
+		return nil, err
+	}
+	cmds := make([]*redisClient.Cmd, len(keys))
+	_, err = e.cmd(ctx).Pipelined(func(pipe redisClient.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Eval(getQuery, []string{key})
+		}
+		return nil
+	})
+	if err != nil && err != redisClient.Nil {
+		return nil, fmt.Errorf("failed to get keys, %w", err)
+	}
+	values := make(map[string]interface{}, len(keys))
+	for i, key := range keys {
+		res, err := cmds[i].Result()
+		switch err {
+		case nil:
+			values[key] = nil
+			if vals, ok := res.([]interface{}); ok && len(vals) > 0 {
+				if value, ok := vals[0].(string); ok {
+					values[key] = value
+				}
+			}
+		case redisClient.Nil:
+			values[key] = nil
+		default:
+			return nil, fmt.Errorf("failed to get key %s, %w", key, err)
+		}
+	}
+	return jsonResponse("", values)
+}
+
+// mSet sets the keys in a single transaction, the request ttl applies to all of the keys
+func (e *Engine) mSet(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	values, err := parseStringMap(data)
+	if err != nil {
+		return nil, err
+	}
+	_, err = e.cmd(ctx).TxPipelined(func(pipe redisClient.Pipeliner) error {
+		for key, value := range values {
+			pipe.Set(key, value, meta.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set keys, %w", err)
+	}
+	return countResponse("", int64(len(values))), nil
+}
+
+func (e *Engine) incrBy(ctx context.Context, meta metadata, value int64) (*types.Response, error) {
+	var cmd *redisClient.IntCmd
+	_, err := e.cmd(ctx).TxPipelined(func(pipe redisClient.Pipeliner) error {
+		cmd = pipe.IncrBy(meta.key, value)
+		expire(pipe, meta)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to increment key %s, %w", meta.key, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("key", meta.key).
+		SetMetadataKeyValue("value", strconv.FormatInt(cmd.Val(), 10)).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (e *Engine) hSet(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	values, err := parseStringMap(data)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]interface{}, 0, len(values)*2)
+	for field, value := range values {
+		fields = append(fields, field, value)
+	}
+	var cmd *redisClient.IntCmd
+	_, err = e.cmd(ctx).TxPipelined(func(pipe redisClient.Pipeliner) error {
+		cmd = pipe.HSet(meta.key, fields...)
+		expire(pipe, meta)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set hash %s, %w", meta.key, err)
+	}
+	return countResponse(meta.key, cmd.Val()), nil
+}
+
+func (e *Engine) hGet(ctx context.Context, meta metadata) (*types.Response, error) {
+	value, err := e.cmd(ctx).HGet(meta.key, meta.field).Result()
+	if err == redisClient.Nil {
+		return nil, errNoData
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash %s field %s, %w", meta.key, meta.field, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("key", meta.key).
+		SetMetadataKeyValue("field", meta.field).
+		SetData([]byte(value)), nil
+}
+
+func (e *Engine) hGetAll(ctx context.Context, meta metadata) (*types.Response, error) {
+	values, err := e.cmd(ctx).HGetAll(meta.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash %s, %w", meta.key, err)
+	}
+	return jsonResponse(meta.key, values)
+}
+
+func (e *Engine) hDel(ctx context.Context, meta metadata) (*types.Response, error) {
+	count, err := e.cmd(ctx).HDel(meta.key, meta.field).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete hash %s field %s, %w", meta.key, meta.field, err)
+	}
+	return countResponse(meta.key, count), nil
+}
+
+func (e *Engine) push(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	values, err := parseStringList(data)
+	if err != nil {
+		return nil, err
+	}
+	var cmd *redisClient.IntCmd
+	_, err = e.cmd(ctx).TxPipelined(func(pipe redisClient.Pipeliner) error {
+		if meta.method == "lpush" {
+			cmd = pipe.LPush(meta.key, toInterfaces(values)...)
+		} else {
+			cmd = pipe.RPush(meta.key, toInterfaces(values)...)
+		}
+		expire(pipe, meta)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to push to list %s, %w", meta.key, err)
+	}
+	return countResponse(meta.key, cmd.Val()), nil
+}
+
+func (e *Engine) pop(ctx context.Context, meta metadata) (*types.Response, error) {
+	var cmd *redisClient.StringCmd
+	if meta.method == "lpop" {
+		cmd = e.cmd(ctx).LPop(meta.key)
+	} else {
+		cmd = e.cmd(ctx).RPop(meta.key)
+	}
+	value, err := cmd.Result()
+	if err == redisClient.Nil {
+		return nil, errNoData
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pop from list %s, %w", meta.key, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("key", meta.key).
+		SetData([]byte(value)), nil
+}
+
+func (e *Engine) lRange(ctx context.Context, meta metadata) (*types.Response, error) {
+	start, _ := strconv.ParseInt(meta.start, 10, 64)
+	stop, _ := strconv.ParseInt(meta.stop, 10, 64)
+	values, err := e.cmd(ctx).LRange(meta.key, start, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read list %s, %w", meta.key, err)
+	}
+	return jsonResponse(meta.key, values)
+}
+
+func (e *Engine) sAdd(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	members, err := parseStringList(data)
+	if err != nil {
+		return nil, err
+	}
+	var cmd *redisClient.IntCmd
+	_, err = e.cmd(ctx).TxPipelined(func(pipe redisClient.Pipeliner) error {
+		cmd = pipe.SAdd(meta.key, toInterfaces(members)...)
+		expire(pipe, meta)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add to set %s, %w", meta.key, err)
+	}
+	return countResponse(meta.key, cmd.Val()), nil
+}
+
+func (e *Engine) sRem(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	members, err := parseStringList(data)
+	if err != nil {
+		return nil, err
+	}
+	count, err := e.cmd(ctx).SRem(meta.key, toInterfaces(members)...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove from set %s, %w", meta.key, err)
+	}
+	return countResponse(meta.key, count), nil
+}
+
+func (e *Engine) sMembers(ctx context.Context, meta metadata) (*types.Response, error) {
+	members, err := e.cmd(ctx).SMembers(meta.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read set %s, %w", meta.key, err)
+	}
+	return jsonResponse(meta.key, members)
+}
+
+func (e *Engine) sIsMember(ctx context.Context, meta metadata) (*types.Response, error) {
+	isMember, err := e.cmd(ctx).SIsMember(meta.key, meta.member).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read set %s, %w", meta.key, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("key", meta.key).
+		SetMetadataKeyValue("is_member", strconv.FormatBool(isMember)), nil
+}
+
+func (e *Engine) zAdd(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	var scored []ScoredMember
+	if err := json.Unmarshal(data, &scored); err != nil {
+		return nil, fmt.Errorf("error parsing data, a json array of members and scores is expected, %w", err)
+	}
+	if len(scored) == 0 {
+		return nil, fmt.Errorf("error parsing data, no values found")
+	}
+	members := make([]*redisClient.Z, len(scored))
+	for i, m := range scored {
+		members[i] = &redisClient.Z{Score: m.Score, Member: m.Member}
+	}
+	var cmd *redisClient.IntCmd
+	_, err := e.cmd(ctx).TxPipelined(func(pipe redisClient.Pipeliner) error {
+		cmd = pipe.ZAdd(meta.key, members...)
+		expire(pipe, meta)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add to sorted set %s, %w", meta.key, err)
+	}
+	return countResponse(meta.key, cmd.Val()), nil
+}
+
+func (e *Engine) zRem(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	members, err := parseStringList(data)
+	if err != nil {
+		return nil, err
+	}
+	count, err := e.cmd(ctx).ZRem(meta.key, toInterfaces(members)...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove from sorted set %s, %w", meta.key, err)
+	}
+	return countResponse(meta.key, count), nil
+}
+
+// zRange reads the sorted set members with their scores by rank, or by score for the zrangebyscore method
+func (e *Engine) zRange(ctx context.Context, meta metadata) (*types.Response, error) {
+	var cmd *redisClient.ZSliceCmd
+	if meta.method == "zrangebyscore" {
+		opt := &redisClient.ZRangeBy{
+			Min: meta.start,
+			Max: meta.stop,
+		}
+		if meta.count > 0 {
+			opt.Count = meta.count
+		}
+		cmd = e.cmd(ctx).ZRangeByScoreWithScores(meta.key, opt)
+	} else {
+		start, _ := strconv.ParseInt(meta.start, 10, 64)
+		stop, _ := strconv.ParseInt(meta.stop, 10, 64)
+		cmd = e.cmd(ctx).ZRangeWithScores(meta.key, start, stop)
+	}
+	values, err := cmd.Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sorted set %s, %w", meta.key, err)
+	}
+	members := make([]ScoredMember, len(values))
+	for i, value := range values {
+		members[i] = ScoredMember{Member: fmt.Sprint(value.Member), Score: value.Score}
+	}
+	return jsonResponse(meta.key, members)
+}
+
+func (e *Engine) xAdd(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	fields, err := parseStringMap(data)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		values[field] = value
+	}
+	id, err := e.cmd(ctx).XAdd(&redisClient.XAddArgs{
+		Stream:       meta.key,
+		MaxLenApprox: meta.maxLen,
+		ID:           meta.id,
+		Values:       values,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to add to stream %s, %w", meta.key, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("key", meta.key).
+		SetMetadataKeyValue("id", id).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (e *Engine) xRange(ctx context.Context, meta metadata) (*types.Response, error) {
+	var cmd *redisClient.XMessageSliceCmd
+	if meta.count > 0 {
+		cmd = e.cmd(ctx).XRangeN(meta.key, meta.start, meta.stop, meta.count)
+	} else {
+		cmd = e.cmd(ctx).XRange(meta.key, meta.start, meta.stop)
+	}
+	values, err := cmd.Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream %s, %w", meta.key, err)
+	}
+	messages := make([]StreamMessage, len(values))
+	for i, value := range values {
+		messages[i] = StreamMessage{ID: value.ID, Values: value.Values}
+	}
+	return jsonResponse(meta.key, messages)
+}
+
+func (e *Engine) publish(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	receivers, err := e.cmd(ctx).Publish(meta.channel, data).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish to channel %s, %w", meta.channel, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("channel", meta.channel).
+		SetMetadataKeyValue("receivers", strconv.FormatInt(receivers, 10)).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+// command runs a raw command given as a json array of the command name and its arguments, only the allowed commands may run
+func (e *Engine) command(ctx context.Context, data []byte) (*types.Response, error) {
+	var args []interface{}
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, fmt.Errorf("error parsing data, a json array of the command and its arguments is expected, %w", err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("error parsing data, no command found")
+	}
+	name, ok := args[0].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("error parsing data, invalid command name")
+	}
+	if !e.opts.isCommandAllowed(name) {
+		return nil, fmt.Errorf("command %s is not allowed", name)
+	}
+	for i, arg := range args {
+		if _, ok := arg.(map[string]interface{}); ok {
+			return nil, fmt.Errorf("error parsing data, invalid argument %d", i)
+		}
+		if _, ok := arg.([]interface{}); ok {
+			return nil, fmt.Errorf("error parsing data, invalid argument %d", i)
+		}
+	}
+	result, err := e.client.DoContext(ctx, args...).Result()
+	if err != nil && err != redisClient.Nil {
+		return nil, fmt.Errorf("failed to run command %s, %w", name, err)
+	}
+	return jsonResponse("", result)
+}
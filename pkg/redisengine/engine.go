@@ -0,0 +1,260 @@
+package redisengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strconv"
+	"strings"
+
+	redisClient "github.com/go-redis/redis/v7"
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+const (
+	getQuery                 = "local t = redis.call(\"TYPE\", KEYS[1])[\"ok\"]; if t == \"hash\" then local v = redis.call(\"HMGET\", KEYS[1], \"data\", \"version\"); if not v[1] or not v[2] then return redis.error_reply(\"required hash field 'data' or 'version' was not found\") end; return v elseif t == \"string\" then return {redis.call(\"GET\", KEYS[1])} elseif t == \"none\" then return nil else return redis.error_reply(\"key \" .. KEYS[1] .. \" holds a \" .. t .. \" value\") end"
+	setQuery                 = "local var1 = redis.pcall(\"HGET\", KEYS[1], \"version\"); if type(var1) == \"table\" then redis.call(\"DEL\", KEYS[1]); end; if not var1 or type(var1)==\"table\" or var1 == \"\" or var1 == ARGV[1] or ARGV[1] == \"0\" then redis.call(\"HSET\", KEYS[1], \"data\", ARGV[2]); local version = redis.call(\"HINCRBY\", KEYS[1], \"version\", 1); if tonumber(ARGV[3]) > 0 then redis.call(\"PEXPIRE\", KEYS[1], ARGV[3]) else redis.call(\"PERSIST\", KEYS[1]) end; return version else return error(\"failed to set key \" .. KEYS[1]) end"
+	delQuery                 = "local var1 = redis.pcall(\"HGET\", KEYS[1], \"version\"); if not var1 or type(var1)==\"table\" or var1 == ARGV[1] or var1 == \"\" or ARGV[1] == \"0\" then return redis.call(\"DEL\", KEYS[1]) else return error(\"failed to delete \" .. KEYS[1]) end"
+	connectedSlavesReplicas  = "connected_slaves:"
+	infoReplicationDelimiter = "\r\n"
+)
+
+var errNoData = errors.New("no data found for this key")
+
+// Engine executes the redis target requests over a standalone, cluster or sentinel connection
+type Engine struct {
+	client   redisClient.UniversalClient
+	opts     Options
+	replicas int
+}
+
+// New connects to redis according to the connection mode
+func New(ctx context.Context, opts Options) (*Engine, error) {
+	e := &Engine{
+		opts: opts,
+	}
+	var addr string
+	switch opts.Mode {
+	case "cluster":
+		addr = strings.Join(opts.Addresses, ",")
+		e.client = redisClient.NewClusterClient(&redisClient.ClusterOptions{
+			Addrs:     opts.Addresses,
+			Username:  opts.Username,
+			Password:  opts.Password,
+			TLSConfig: opts.tlsConfig(),
+		})
+	case "sentinel":
+		addr = strings.Join(opts.Addresses, ",")
+		e.client = redisClient.NewFailoverClient(&redisClient.FailoverOptions{
+			MasterName:    opts.MasterName,
+			SentinelAddrs: opts.Addresses,
+			Username:      opts.Username,
+			Password:      opts.Password,
+			DB:            opts.DB,
+			TLSConfig:     opts.tlsConfig(),
+		})
+	default:
+		redisInfo, err := redisClient.ParseURL(opts.URL)
+		if err != nil {
+			// the url may hold the redis password, so the parse error is reported without it
+			var urlErr *neturl.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return nil, fmt.Errorf("error parsing redis url: %w", err)
+		}
+		addr = redisInfo.Addr
+		e.client = redisClient.NewClient(redisInfo)
+	}
+	_, err := e.cmd(ctx).Ping().Result()
+	if err != nil {
+		_ = e.client.Close()
+		return nil, fmt.Errorf("error connecting to redis at %s: %w", addr, err)
+	}
+	// replicas acknowledgment is not supported over a cluster connection, the WAIT command reaches a single node,
+	// servers restricting the INFO command are treated as having no replicas
+	if opts.Mode != "cluster" {
+		e.replicas, _ = e.getConnectedSlaves(ctx)
+	}
+	return e, nil
+}
+
+// Client returns the redis connection of the engine
+func (e *Engine) Client() redisClient.UniversalClient {
+	return e.client
+}
+
+// cmd returns the commands of the connection bound to the request context
+func (e *Engine) cmd(ctx context.Context) redisClient.Cmdable {
+	switch c := e.client.(type) {
+	case *redisClient.Client:
+		return c.WithContext(ctx)
+	case *redisClient.ClusterClient:
+		return c.WithContext(ctx)
+	}
+	return e.client
+}
+
+// Do executes a request according to its method
+func (e *Engine) Do(ctx context.Context, req *types.Request) (*types.Response, error) {
+	meta, err := parseMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	switch meta.method {
+	case "get":
+		return e.get(ctx, meta)
+	case "set":
+		return e.set(ctx, meta, req.Data)
+	case "delete":
+		return e.delete(ctx, meta)
+	case "mget":
+		return e.mGet(ctx, req.Data)
+	case "mset":
+		return e.mSet(ctx, meta, req.Data)
+	case "incr":
+		return e.incrBy(ctx, meta, meta.value)
+	case "decr":
+		return e.incrBy(ctx, meta, -meta.value)
+	case "hset":
+		return e.hSet(ctx, meta, req.Data)
+	case "hget":
+		return e.hGet(ctx, meta)
+	case "hgetall":
+		return e.hGetAll(ctx, meta)
+	case "hdel":
+		return e.hDel(ctx, meta)
+	case "lpush", "rpush":
+		return e.push(ctx, meta, req.Data)
+	case "lpop", "rpop":
+		return e.pop(ctx, meta)
+	case "lrange":
+		return e.lRange(ctx, meta)
+	case "sadd":
+		return e.sAdd(ctx, meta, req.Data)
+	case "srem":
+		return e.sRem(ctx, meta, req.Data)
+	case "smembers":
+		return e.sMembers(ctx, meta)
+	case "sismember":
+		return e.sIsMember(ctx, meta)
+	case "zadd":
+		return e.zAdd(ctx, meta, req.Data)
+	case "zrem":
+		return e.zRem(ctx, meta, req.Data)
+	case "zrange", "zrangebyscore":
+		return e.zRange(ctx, meta)
+	case "xadd":
+		return e.xAdd(ctx, meta, req.Data)
+	case "xrange":
+		return e.xRange(ctx, meta)
+	case "publish":
+		return e.publish(ctx, meta, req.Data)
+	case "command":
+		return e.command(ctx, req.Data)
+	case "lock":
+		return e.lock(ctx, meta)
+	case "unlock":
+		return e.unlock(ctx, meta)
+	case "renew":
+		return e.renew(ctx, meta)
+	}
+	return nil, fmt.Errorf("invalid method %s", meta.method)
+}
+
+func (e *Engine) getConnectedSlaves(ctx context.Context) (int, error) {
+	res, err := e.client.DoContext(ctx, "INFO", "replication").Result()
+	if err != nil {
+		return 0, err
+	}
+
+	s, _ := strconv.Unquote(fmt.Sprintf("%q", res))
+	if len(s) == 0 {
+		return 0, nil
+	}
+
+	return parseConnectedSlaves(s), nil
+}
+
+func parseConnectedSlaves(res string) int {
+	infos := strings.Split(res, infoReplicationDelimiter)
+	for _, info := range infos {
+		if strings.Contains(info, connectedSlavesReplicas) {
+			parsedReplicas, _ := strconv.ParseUint(info[len(connectedSlavesReplicas):], 10, 32)
+			return int(parsedReplicas)
+		}
+	}
+
+	return 0
+}
+
+// get reads a key set by the set method, plain string keys are read as well
+func (e *Engine) get(ctx context.Context, meta metadata) (*types.Response, error) {
+	res, err := e.cmd(ctx).Eval(getQuery, []string{meta.key}).Result()
+	if err == redisClient.Nil {
+		return nil, errNoData
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error found for get this key, %w", err)
+	}
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) == 0 {
+		return nil, errNoData
+	}
+	data, _ := vals[0].(string)
+	resp := types.NewResponse().
+		SetData([]byte(data)).
+		SetMetadataKeyValue("key", meta.key)
+	if len(vals) > 1 {
+		if version, ok := vals[1].(string); ok {
+			resp.SetMetadataKeyValue("etag", version)
+		}
+	}
+	return resp, nil
+}
+
+// set writes the value and its etag version in a hash, the write is rejected when the request etag does not match the stored version
+func (e *Engine) set(ctx context.Context, meta metadata, value []byte) (*types.Response, error) {
+	if meta.concurrency == "last-write" {
+		meta.etag = 0
+	}
+
+	version, err := e.cmd(ctx).Eval(setQuery, []string{meta.key}, meta.etag, value, meta.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set key %s: %s", meta.key, err)
+	}
+
+	if meta.consistency == "strong" && e.replicas > 0 {
+		_, err = e.client.DoContext(ctx, "WAIT", e.replicas, 1000).Result()
+		if err != nil {
+			return nil, fmt.Errorf("timed out while waiting for %v replicas to acknowledge write", e.replicas)
+		}
+	}
+
+	return types.NewResponse().
+			SetMetadataKeyValue("key", meta.key).
+			SetMetadataKeyValue("etag", strconv.FormatInt(version, 10)).
+			SetMetadataKeyValue("result", "ok"),
+		nil
+}
+
+// delete deletes a key, a key set by the set method is deleted only when the request etag matches the stored version
+func (e *Engine) delete(ctx context.Context, meta metadata) (*types.Response, error) {
+	_, err := e.cmd(ctx).Eval(delQuery, []string{meta.key}, meta.etag).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete key '%s',%w", meta.key, err)
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("key", meta.key).
+			SetMetadataKeyValue("result", "ok"),
+		nil
+}
+
+// Close closes the redis connection
+func (e *Engine) Close() error {
+	if e == nil || e.client == nil {
+		return nil
+	}
+	return e.client.Close()
+}
@@ -0,0 +1,332 @@
+package redisengine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEngine(t *testing.T, props map[string]string) (*Engine, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	props["url"] = "redis://" + server.Addr()
+	opts, err := ParseOptions(props)
+	require.NoError(t, err)
+	e, err := New(context.Background(), opts)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = e.Close()
+	})
+	return e, server
+}
+
+func do(t *testing.T, e *Engine, meta map[string]string, data string) *types.Response {
+	req := types.NewRequest().SetMetadata(meta)
+	if data != "" {
+		req.SetData([]byte(data))
+	}
+	resp, err := e.Do(context.Background(), req)
+	require.NoError(t, err)
+	return resp
+}
+
+func doErr(t *testing.T, e *Engine, meta map[string]string, data string) error {
+	req := types.NewRequest().SetMetadata(meta).SetData([]byte(data))
+	_, err := e.Do(context.Background(), req)
+	require.Error(t, err)
+	return err
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		props   map[string]string
+		want    Options
+		wantErr bool
+	}{
+		{
+			name:  "standalone",
+			props: map[string]string{"url": "redis://localhost:6379"},
+			want:  Options{Mode: "standalone", URL: "redis://localhost:6379"},
+		},
+		{
+			name: "cluster",
+			props: map[string]string{
+				"mode":             "cluster",
+				"addresses":        "node-1:6379, node-2:6379",
+				"password":         "pass",
+				"allowed_commands": "ping, info",
+			},
+			want: Options{
+				Mode:            "cluster",
+				Addresses:       []string{"node-1:6379", "node-2:6379"},
+				Password:        "pass",
+				AllowedCommands: []string{"PING", "INFO"},
+			},
+		},
+		{
+			name: "sentinel",
+			props: map[string]string{
+				"mode":        "sentinel",
+				"addresses":   "sentinel:26379",
+				"master_name": "mymaster",
+				"db":          "2",
+				"tls":         "true",
+			},
+			want: Options{
+				Mode:       "sentinel",
+				Addresses:  []string{"sentinel:26379"},
+				MasterName: "mymaster",
+				DB:         2,
+				TLS:        true,
+			},
+		},
+		{
+			name:    "standalone - no url",
+			props:   map[string]string{},
+			wantErr: true,
+		},
+		{
+			name:    "cluster - no addresses",
+			props:   map[string]string{"mode": "cluster"},
+			wantErr: true,
+		},
+		{
+			name:    "sentinel - no master name",
+			props:   map[string]string{"mode": "sentinel", "addresses": "sentinel:26379"},
+			wantErr: true,
+		},
+		{
+			name:    "bad mode",
+			props:   map[string]string{"mode": "bad", "url": "redis://localhost:6379"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOptions(tt.props)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEngine_GetSetDelete(t *testing.T) {
+	e, server := newTestEngine(t, map[string]string{})
+	resp := do(t, e, map[string]string{"method": "set", "key": "key-1"}, "value-1")
+	require.Equal(t, "1", resp.Metadata["etag"])
+	resp = do(t, e, map[string]string{"method": "get", "key": "key-1"}, "")
+	require.Equal(t, []byte("value-1"), resp.Data)
+	require.Equal(t, "1", resp.Metadata["etag"])
+
+	resp = do(t, e, map[string]string{"method": "set", "key": "key-1", "etag": "1", "ttl_seconds": "10"}, "value-2")
+	require.Equal(t, "2", resp.Metadata["etag"])
+	require.Equal(t, 10*time.Second, server.TTL("key-1"))
+	doErr(t, e, map[string]string{"method": "set", "key": "key-1", "etag": "1"}, "value-3")
+	doErr(t, e, map[string]string{"method": "delete", "key": "key-1", "etag": "1"}, "")
+	do(t, e, map[string]string{"method": "set", "key": "key-1", "concurrency": "last-write"}, "value-3")
+	require.Zero(t, server.TTL("key-1"))
+
+	do(t, e, map[string]string{"method": "delete", "key": "key-1", "etag": "3"}, "")
+	doErr(t, e, map[string]string{"method": "get", "key": "key-1"}, "")
+
+	require.NoError(t, server.Set("plain", "plain-value"))
+	resp = do(t, e, map[string]string{"method": "get", "key": "plain"}, "")
+	require.Equal(t, []byte("plain-value"), resp.Data)
+	require.Empty(t, resp.Metadata["etag"])
+
+	_, err := server.Lpush("list", "a")
+	require.NoError(t, err)
+	doErr(t, e, map[string]string{"method": "get", "key": "list"}, "")
+}
+
+func TestEngine_Strings(t *testing.T) {
+	e, server := newTestEngine(t, map[string]string{})
+	resp := do(t, e, map[string]string{"method": "mset", "ttl_seconds": "60"}, `{"a":"1","b":"2"}`)
+	require.Equal(t, "2", resp.Metadata["count"])
+	require.Equal(t, time.Minute, server.TTL("a"))
+	resp = do(t, e, map[string]string{"method": "mget"}, `["a","b","c"]`)
+	require.JSONEq(t, `{"a":"1","b":"2","c":null}`, string(resp.Data))
+	do(t, e, map[string]string{"method": "set", "key": "d"}, "4")
+	resp = do(t, e, map[string]string{"method": "mget"}, `["a","d"]`)
+	require.JSONEq(t, `{"a":"1","d":"4"}`, string(resp.Data))
+	doErr(t, e, map[string]string{"method": "mget"}, `[]`)
+	doErr(t, e, map[string]string{"method": "mset"}, `["a"]`)
+
+	resp = do(t, e, map[string]string{"method": "incr", "key": "counter"}, "")
+	require.Equal(t, "1", resp.Metadata["value"])
+	resp = do(t, e, map[string]string{"method": "incr", "key": "counter", "value": "10"}, "")
+	require.Equal(t, "11", resp.Metadata["value"])
+	resp = do(t, e, map[string]string{"method": "decr", "key": "counter", "value": "5", "ttl_seconds": "5"}, "")
+	require.Equal(t, "6", resp.Metadata["value"])
+	require.Equal(t, 5*time.Second, server.TTL("counter"))
+}
+
+func TestEngine_Hash(t *testing.T) {
+	e, _ := newTestEngine(t, map[string]string{})
+	resp := do(t, e, map[string]string{"method": "hset", "key": "hash"}, `{"f1":"v1","f2":"v2"}`)
+	require.Equal(t, "2", resp.Metadata["count"])
+	resp = do(t, e, map[string]string{"method": "hget", "key": "hash", "field": "f1"}, "")
+	require.Equal(t, []byte("v1"), resp.Data)
+	doErr(t, e, map[string]string{"method": "hget", "key": "hash", "field": "f3"}, "")
+	doErr(t, e, map[string]string{"method": "hget", "key": "hash"}, "")
+	resp = do(t, e, map[string]string{"method": "hdel", "key": "hash", "field": "f2"}, "")
+	require.Equal(t, "1", resp.Metadata["count"])
+	resp = do(t, e, map[string]string{"method": "hgetall", "key": "hash"}, "")
+	require.JSONEq(t, `{"f1":"v1"}`, string(resp.Data))
+}
+
+func TestEngine_List(t *testing.T) {
+	e, _ := newTestEngine(t, map[string]string{})
+	resp := do(t, e, map[string]string{"method": "rpush", "key": "list"}, `["b","c"]`)
+	require.Equal(t, "2", resp.Metadata["count"])
+	resp = do(t, e, map[string]string{"method": "lpush", "key": "list"}, `["a"]`)
+	require.Equal(t, "3", resp.Metadata["count"])
+	resp = do(t, e, map[string]string{"method": "lrange", "key": "list"}, "")
+	require.JSONEq(t, `["a","b","c"]`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "lrange", "key": "list", "start": "1", "stop": "1"}, "")
+	require.JSONEq(t, `["b"]`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "lpop", "key": "list"}, "")
+	require.Equal(t, []byte("a"), resp.Data)
+	resp = do(t, e, map[string]string{"method": "rpop", "key": "list"}, "")
+	require.Equal(t, []byte("c"), resp.Data)
+	do(t, e, map[string]string{"method": "rpop", "key": "list"}, "")
+	doErr(t, e, map[string]string{"method": "rpop", "key": "list"}, "")
+}
+
+func TestEngine_Sets(t *testing.T) {
+	e, _ := newTestEngine(t, map[string]string{})
+	resp := do(t, e, map[string]string{"method": "sadd", "key": "set"}, `["a","b","a"]`)
+	require.Equal(t, "2", resp.Metadata["count"])
+	resp = do(t, e, map[string]string{"method": "sismember", "key": "set", "member": "a"}, "")
+	require.Equal(t, "true", resp.Metadata["is_member"])
+	resp = do(t, e, map[string]string{"method": "srem", "key": "set"}, `["a"]`)
+	require.Equal(t, "1", resp.Metadata["count"])
+	resp = do(t, e, map[string]string{"method": "smembers", "key": "set"}, "")
+	require.JSONEq(t, `["b"]`, string(resp.Data))
+
+	resp = do(t, e, map[string]string{"method": "zadd", "key": "zset"}, `[{"member":"a","score":3},{"member":"b","score":1},{"member":"c","score":2}]`)
+	require.Equal(t, "3", resp.Metadata["count"])
+	resp = do(t, e, map[string]string{"method": "zrange", "key": "zset", "start": "0", "stop": "1"}, "")
+	require.JSONEq(t, `[{"member":"b","score":1},{"member":"c","score":2}]`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "zrangebyscore", "key": "zset", "min": "2", "count": "1"}, "")
+	require.JSONEq(t, `[{"member":"c","score":2}]`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "zrem", "key": "zset"}, `["a","d"]`)
+	require.Equal(t, "1", resp.Metadata["count"])
+	doErr(t, e, map[string]string{"method": "zadd", "key": "zset"}, `["a"]`)
+}
+
+func TestEngine_StreamsAndPublish(t *testing.T) {
+	e, server := newTestEngine(t, map[string]string{})
+	resp := do(t, e, map[string]string{"method": "xadd", "key": "stream", "id": "1-1"}, `{"field":"value-1"}`)
+	require.Equal(t, "1-1", resp.Metadata["id"])
+	resp = do(t, e, map[string]string{"method": "xadd", "key": "stream"}, `{"field":"value-2"}`)
+	require.NotEmpty(t, resp.Metadata["id"])
+	resp = do(t, e, map[string]string{"method": "xrange", "key": "stream"}, "")
+	var messages []StreamMessage
+	require.NoError(t, json.Unmarshal(resp.Data, &messages))
+	require.Len(t, messages, 2)
+	require.Equal(t, StreamMessage{ID: "1-1", Values: map[string]interface{}{"field": "value-1"}}, messages[0])
+	resp = do(t, e, map[string]string{"method": "xrange", "key": "stream", "count": "1"}, "")
+	require.NoError(t, json.Unmarshal(resp.Data, &messages))
+	require.Len(t, messages, 1)
+
+	sub := server.NewSubscriber()
+	defer sub.Close()
+	sub.Subscribe("channel")
+	received := make(chan miniredis.PubsubMessage, 1)
+	go func() {
+		received <- <-sub.Messages()
+	}()
+	resp = do(t, e, map[string]string{"method": "publish", "channel": "channel"}, "message")
+	require.Equal(t, "1", resp.Metadata["receivers"])
+	require.Equal(t, "message", (<-received).Message)
+	doErr(t, e, map[string]string{"method": "publish"}, "message")
+}
+
+func TestEngine_Command(t *testing.T) {
+	e, _ := newTestEngine(t, map[string]string{})
+	err := doErr(t, e, map[string]string{"method": "command"}, `["SET","key","value"]`)
+	require.Contains(t, err.Error(), "not allowed")
+
+	e, _ = newTestEngine(t, map[string]string{"allowed_commands": "set,get,rpush,lrange"})
+	resp := do(t, e, map[string]string{"method": "command"}, `["set","key","value"]`)
+	require.JSONEq(t, `"OK"`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "command"}, `["GET","key"]`)
+	require.JSONEq(t, `"value"`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "command"}, `["GET","missing"]`)
+	require.JSONEq(t, `null`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "command"}, `["RPUSH","list","a",1]`)
+	require.JSONEq(t, `2`, string(resp.Data))
+	resp = do(t, e, map[string]string{"method": "command"}, `["LRANGE","list",0,-1]`)
+	require.JSONEq(t, `["a","1"]`, string(resp.Data))
+	doErr(t, e, map[string]string{"method": "command"}, `["FLUSHALL"]`)
+	doErr(t, e, map[string]string{"method": "command"}, `[]`)
+	doErr(t, e, map[string]string{"method": "command"}, `["GET",["key"]]`)
+
+	e, _ = newTestEngine(t, map[string]string{"allowed_commands": "*"})
+	resp = do(t, e, map[string]string{"method": "command"}, `["PING"]`)
+	require.JSONEq(t, `"PONG"`, string(resp.Data))
+}
+
+func TestEngine_Metadata(t *testing.T) {
+	e, _ := newTestEngine(t, map[string]string{})
+	doErr(t, e, map[string]string{"method": "bad", "key": "key"}, "")
+	doErr(t, e, map[string]string{"method": "get"}, "")
+	doErr(t, e, map[string]string{"method": "set", "key": "key", "ttl_seconds": "-1"}, "")
+}
+
+func TestEngine_Lock(t *testing.T) {
+	e, server := newTestEngine(t, map[string]string{})
+	resp := do(t, e, map[string]string{"method": "lock", "key": "lock-1", "ttl_seconds": "10"}, "")
+	token := resp.Metadata["token"]
+	require.NotEmpty(t, token)
+	expiresAt, err := time.Parse(time.RFC3339Nano, resp.Metadata["expires_at"])
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(10*time.Second), expiresAt, time.Second)
+	require.Equal(t, 10*time.Second, server.TTL("lock-1"))
+
+	err = doErr(t, e, map[string]string{"method": "lock", "key": "lock-1"}, "")
+	require.Contains(t, err.Error(), "held by another owner")
+	doErr(t, e, map[string]string{"method": "lock", "key": "lock-1", "token": "other"}, "")
+	doErr(t, e, map[string]string{"method": "unlock", "key": "lock-1", "token": "other"}, "")
+	doErr(t, e, map[string]string{"method": "renew", "key": "lock-1", "token": "other"}, "")
+
+	server.FastForward(5 * time.Second)
+	resp = do(t, e, map[string]string{"method": "lock", "key": "lock-1", "token": token}, "")
+	require.Equal(t, token, resp.Metadata["token"])
+	require.Equal(t, 10*time.Second, server.TTL("lock-1"))
+	server.FastForward(5 * time.Second)
+	resp = do(t, e, map[string]string{"method": "renew", "key": "lock-1", "token": token, "ttl_seconds": "10"}, "")
+	require.Equal(t, token, resp.Metadata["token"])
+	require.Equal(t, 10*time.Second, server.TTL("lock-1"))
+	err = doErr(t, e, map[string]string{"method": "renew", "key": "lock-1", "token": token, "ttl_seconds": "20"}, "")
+	require.Contains(t, err.Error(), "cannot be changed")
+	doErr(t, e, map[string]string{"method": "lock", "key": "lock-1", "token": token, "ttl_seconds": "20"}, "")
+	do(t, e, map[string]string{"method": "unlock", "key": "lock-1", "token": token}, "")
+	doErr(t, e, map[string]string{"method": "unlock", "key": "lock-1", "token": token}, "")
+	doErr(t, e, map[string]string{"method": "renew", "key": "lock-1", "token": token}, "")
+
+	resp = do(t, e, map[string]string{"method": "lock", "key": "lock-2"}, "")
+	token = resp.Metadata["token"]
+	server.FastForward(31 * time.Second)
+	doErr(t, e, map[string]string{"method": "renew", "key": "lock-2", "token": token}, "")
+	doErr(t, e, map[string]string{"method": "lock", "key": "lock-2", "token": "owner-2"}, "")
+	resp = do(t, e, map[string]string{"method": "lock", "key": "lock-2"}, "")
+	require.NotEqual(t, token, resp.Metadata["token"])
+
+	doErr(t, e, map[string]string{"method": "unlock", "key": "lock-2"}, "")
+	doErr(t, e, map[string]string{"method": "lock", "key": "lock-3", "ttl_seconds": "5"}, "")
+	doErr(t, e, map[string]string{"method": "lock", "key": "lock-3", "ttl_seconds": "86401"}, "")
+	server.Set("lock-4", "value")
+	doErr(t, e, map[string]string{"method": "lock", "key": "lock-4"}, "")
+	doErr(t, e, map[string]string{"method": "unlock", "key": "lock-4", "token": "value"}, "")
+}
@@ -0,0 +1,79 @@
+package redisengine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+// lock keys are hashes holding the lock token and ttl, renewing a lock extends it by the ttl it was created with.
+// renewQuery returns the lock ttl in milliseconds, 0 when the lock is not held by the token, or the negated lock ttl
+// when the request ttl differs from it
+const (
+	lockQuery   = "if redis.call(\"EXISTS\", KEYS[1]) == 1 then return 0 end; redis.call(\"HSET\", KEYS[1], \"token\", ARGV[1], \"ttl\", ARGV[2]); redis.call(\"PEXPIRE\", KEYS[1], ARGV[2]); return 1"
+	unlockQuery = "if redis.call(\"TYPE\", KEYS[1]).ok == \"hash\" and redis.call(\"HGET\", KEYS[1], \"token\") == ARGV[1] then return redis.call(\"DEL\", KEYS[1]) end; return 0"
+	renewQuery  = "if redis.call(\"TYPE\", KEYS[1]).ok ~= \"hash\" or redis.call(\"HGET\", KEYS[1], \"token\") ~= ARGV[1] then return 0 end; local ttl = redis.call(\"HGET\", KEYS[1], \"ttl\"); if ARGV[2] ~= \"0\" and ARGV[2] ~= ttl then return -tonumber(ttl) end; redis.call(\"PEXPIRE\", KEYS[1], ttl); return tonumber(ttl)"
+)
+
+func lockResponse(meta metadata, expiresAt time.Time) *types.Response {
+	return types.NewResponse().
+		SetMetadataKeyValue("key", meta.key).
+		SetMetadataKeyValue("token", meta.token).
+		SetMetadataKeyValue("expires_at", expiresAt.UTC().Format(time.RFC3339Nano)).
+		SetMetadataKeyValue("result", "ok")
+}
+
+// lock acquires the lock key for a new token, a request with the token of the lock owner renews the lock
+func (e *Engine) lock(ctx context.Context, meta metadata) (*types.Response, error) {
+	if meta.token != "" {
+		return e.renew(ctx, meta)
+	}
+	meta.token = uuid.New().String()
+	expiresAt := time.Now().Add(meta.ttl)
+	acquired, err := e.cmd(ctx).Eval(lockQuery, []string{meta.key}, meta.token, meta.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock key %s, %w", meta.key, err)
+	}
+	if acquired == 0 {
+		return nil, fmt.Errorf("lock %s is held by another owner", meta.key)
+	}
+	return lockResponse(meta, expiresAt), nil
+}
+
+// unlock releases the lock key when it is held by the request token
+func (e *Engine) unlock(ctx context.Context, meta metadata) (*types.Response, error) {
+	released, err := e.cmd(ctx).Eval(unlockQuery, []string{meta.key}, meta.token).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock key %s, %w", meta.key, err)
+	}
+	if released == 0 {
+		return nil, fmt.Errorf("lock %s is not held by this token", meta.key)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("key", meta.key).
+		SetMetadataKeyValue("token", meta.token).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+// renew extends the lock key by its ttl when it is held by the request token, the ttl of a lock cannot be changed after it is created
+func (e *Engine) renew(ctx context.Context, meta metadata) (*types.Response, error) {
+	var ttl time.Duration
+	if meta.ttlSet {
+		ttl = meta.ttl
+	}
+	now := time.Now()
+	lockTTL, err := e.cmd(ctx).Eval(renewQuery, []string{meta.key}, meta.token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew lock key %s, %w", meta.key, err)
+	}
+	switch {
+	case lockTTL == 0:
+		return nil, fmt.Errorf("lock %s is not held by this token", meta.key)
+	case lockTTL < 0:
+		return nil, fmt.Errorf("lock %s ttl is %s, ttl_seconds cannot be changed after the lock is created", meta.key, time.Duration(-lockTTL)*time.Millisecond)
+	}
+	return lockResponse(meta, now.Add(time.Duration(lockTTL)*time.Millisecond)), nil
+}
@@ -0,0 +1,191 @@
+package redisengine
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+var methodsMap = map[string]string{
+	"get":           "get",
+	"set":           "set",
+	"delete":        "delete",
+	"mget":          "mget",
+	"mset":          "mset",
+	"incr":          "incr",
+	"decr":          "decr",
+	"hset":          "hset",
+	"hget":          "hget",
+	"hgetall":       "hgetall",
+	"hdel":          "hdel",
+	"lpush":         "lpush",
+	"rpush":         "rpush",
+	"lpop":          "lpop",
+	"rpop":          "rpop",
+	"lrange":        "lrange",
+	"sadd":          "sadd",
+	"srem":          "srem",
+	"smembers":      "smembers",
+	"sismember":     "sismember",
+	"zadd":          "zadd",
+	"zrem":          "zrem",
+	"zrange":        "zrange",
+	"zrangebyscore": "zrangebyscore",
+	"xadd":          "xadd",
+	"xrange":        "xrange",
+	"publish":       "publish",
+	"command":       "command",
+	"lock":          "lock",
+	"unlock":        "unlock",
+	"renew":         "renew",
+}
+
+// keylessMethods are the methods which take their keys from the request data or do not use keys
+var keylessMethods = map[string]bool{
+	"mget":    true,
+	"mset":    true,
+	"publish": true,
+	"command": true,
+}
+
+// lock ttl is limited to 10 seconds up to 24 hours, the same range as the other lock backends
+const (
+	defaultLockTTLSeconds = 30
+	minLockTTLSeconds     = 10
+	maxLockTTLSeconds     = 86400
+)
+
+var concurrencyMap = map[string]string{
+	"first-write": "first-write",
+	"last-write":  "last-write",
+	"":            "",
+}
+
+var consistencyMap = map[string]string{
+	"strong":   "strong",
+	"eventual": "eventual",
+	"":         "",
+}
+
+type metadata struct {
+	method      string
+	key         string
+	etag        int
+	concurrency string
+	consistency string
+	ttl         time.Duration
+	field       string
+	member      string
+	value       int64
+	start       string
+	stop        string
+	count       int64
+	id          string
+	maxLen      int64
+	channel     string
+	token       string
+	// ttlSet is set when the request sets ttl_seconds
+	ttlSet bool
+}
+
+func parseMetadata(meta types.Metadata) (metadata, error) {
+	m := metadata{}
+	var err error
+	m.method, err = meta.ParseStringMap("method", methodsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing method, %w", err)
+	}
+	if !keylessMethods[m.method] {
+		m.key, err = meta.MustParseString("key")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing key value, %w", err)
+		}
+	}
+	m.etag, err = meta.ParseIntWithRange("etag", 0, 0, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error on parsing etag value, %w", err)
+	}
+	m.concurrency, err = meta.ParseStringMap("concurrency", concurrencyMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error on parsing concurrency, %w", err)
+	}
+	m.consistency, err = meta.ParseStringMap("consistency", consistencyMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error on parsing consistency, %w", err)
+	}
+	minTTL, defaultTTL, maxTTL := 0, 0, math.MaxInt32
+	if m.method == "lock" || m.method == "renew" {
+		minTTL, defaultTTL, maxTTL = minLockTTLSeconds, defaultLockTTLSeconds, maxLockTTLSeconds
+	}
+	ttl, err := meta.ParseIntWithRange("ttl_seconds", defaultTTL, minTTL, maxTTL)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error on parsing ttl seconds value, %w", err)
+	}
+	m.ttl = time.Duration(ttl) * time.Second
+	_, m.ttlSet = meta["ttl_seconds"]
+	switch m.method {
+	case "hget", "hdel":
+		m.field, err = meta.MustParseString("field")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing field value, %w", err)
+		}
+	case "sismember":
+		m.member, err = meta.MustParseString("member")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing member value, %w", err)
+		}
+	case "incr", "decr":
+		value, err := meta.ParseIntWithRange("value", 1, 0, math.MaxInt32)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing value, %w", err)
+		}
+		m.value = int64(value)
+	case "lrange", "zrange":
+		start, err := meta.ParseIntWithRange("start", 0, math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing start value, %w", err)
+		}
+		stop, err := meta.ParseIntWithRange("stop", -1, math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing stop value, %w", err)
+		}
+		m.start = strconv.Itoa(start)
+		m.stop = strconv.Itoa(stop)
+	case "zrangebyscore":
+		m.start = meta.ParseString("min", "-inf")
+		m.stop = meta.ParseString("max", "+inf")
+	case "xrange":
+		m.start = meta.ParseString("start", "-")
+		m.stop = meta.ParseString("end", "+")
+	case "xadd":
+		m.id = meta.ParseString("id", "*")
+		maxLen, err := meta.ParseIntWithRange("max_len", 0, 0, math.MaxInt32)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing max len value, %w", err)
+		}
+		m.maxLen = int64(maxLen)
+	case "lock":
+		m.token = meta.ParseString("token", "")
+	case "unlock", "renew":
+		m.token, err = meta.MustParseString("token")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing token value, %w", err)
+		}
+	case "publish":
+		m.channel, err = meta.MustParseString("channel")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing channel value, %w", err)
+		}
+	}
+	if m.method == "zrangebyscore" || m.method == "xrange" {
+		count, err := meta.ParseIntWithRange("count", 0, 0, math.MaxInt32)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing count value, %w", err)
+		}
+		m.count = int64(count)
+	}
+	return m, nil
+}
@@ -0,0 +1,100 @@
+package redisengine
+
+import (
+	"crypto/tls"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+var modesMap = map[string]string{
+	"":           "standalone",
+	"standalone": "standalone",
+	"cluster":    "cluster",
+	"sentinel":   "sentinel",
+}
+
+// Options holds the connection settings shared by the redis targets
+type Options struct {
+	// Mode is the connection mode, standalone, cluster or sentinel
+	Mode string
+	// URL is the redis url of a standalone connection
+	URL string
+	// Addresses are the cluster nodes or the sentinel addresses
+	Addresses []string
+	// MasterName is the sentinel master name
+	MasterName string
+	// Username and Password authenticate cluster and sentinel connections, standalone connections set them in the url
+	Username string
+	Password string
+	// DB is the database of a sentinel connection
+	DB int
+	// TLS enables tls for cluster and sentinel connections, standalone connections use a rediss url
+	TLS bool
+	// AllowedCommands lists the commands the command method may run, "*" allows all commands, empty disables the method
+	AllowedCommands []string
+}
+
+// ParseOptions parses the connection mode properties
+func ParseOptions(props types.Metadata) (Options, error) {
+	o := Options{}
+	var err error
+	o.Mode, err = props.ParseStringMap("mode", modesMap)
+	if err != nil {
+		return Options{}, fmt.Errorf("error parsing mode, %w", err)
+	}
+	switch o.Mode {
+	case "standalone":
+		o.URL, err = props.MustParseString("url")
+		if err != nil {
+			return Options{}, fmt.Errorf("error parsing url, %w", err)
+		}
+	default:
+		o.Addresses, err = props.MustParseStringList("addresses")
+		if err != nil {
+			return Options{}, fmt.Errorf("error parsing addresses, %w", err)
+		}
+		for i := range o.Addresses {
+			o.Addresses[i] = strings.TrimSpace(o.Addresses[i])
+		}
+	}
+	if o.Mode == "sentinel" {
+		o.MasterName, err = props.MustParseString("master_name")
+		if err != nil {
+			return Options{}, fmt.Errorf("error parsing master name, %w", err)
+		}
+	}
+	o.Username = props.ParseString("username", "")
+	o.Password = props.ParseString("password", "")
+	o.DB, err = props.ParseIntWithRange("db", 0, 0, math.MaxInt16)
+	if err != nil {
+		return Options{}, fmt.Errorf("error parsing db, %w", err)
+	}
+	o.TLS = props.ParseBool("tls", false)
+	for _, command := range strings.Split(props.ParseString("allowed_commands", ""), ",") {
+		command = strings.ToUpper(strings.TrimSpace(command))
+		if command != "" {
+			o.AllowedCommands = append(o.AllowedCommands, command)
+		}
+	}
+	return o, nil
+}
+
+func (o Options) tlsConfig() *tls.Config {
+	if !o.TLS {
+		return nil
+	}
+	return &tls.Config{}
+}
+
+func (o Options) isCommandAllowed(command string) bool {
+	command = strings.ToUpper(command)
+	for _, allowed := range o.AllowedCommands {
+		if allowed == "*" || allowed == command {
+			return true
+		}
+	}
+	return false
+}
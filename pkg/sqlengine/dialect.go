@@ -0,0 +1,165 @@
+package sqlengine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach-go/crdb"
+)
+
+type placeholderStyle int
+
+type savepointStyle int
+
+const (
+	// standardSavepoints use SAVEPOINT, ROLLBACK TO SAVEPOINT and RELEASE SAVEPOINT
+	standardSavepoints savepointStyle = iota
+	// noSavepoints is set for databases without savepoint support
+	noSavepoints
+	// transactSavepoints use SAVE TRANSACTION and ROLLBACK TRANSACTION, sql server has no release
+	transactSavepoints
+)
+
+const (
+	// question placeholders are ?, used by mysql compatible drivers and the mssql driver name
+	question placeholderStyle = iota
+	// dollar placeholders are $1..$n, used by postgres compatible drivers
+	dollar
+	// atP placeholders are @p1..@pn, used by the sqlserver driver name
+	atP
+)
+
+// Dialect describes the syntax differences between the supported databases
+type Dialect struct {
+	Name        string
+	placeholder placeholderStyle
+	// backslashEscapes is set when a backslash escapes a quote inside string literals
+	backslashEscapes bool
+	// dollarQuotes is set when $tag$ ... $tag$ strings are supported
+	dollarQuotes bool
+	// bracketIdentifiers is set when identifiers may be quoted with [ ]
+	bracketIdentifiers bool
+	// copy is set when bulk inserts can use COPY FROM STDIN through lib/pq
+	copy bool
+	// noTransactions is set for databases without transaction support
+	noTransactions bool
+	savepoints     savepointStyle
+	// offsetFetch is set when result set pages are read with OFFSET FETCH instead of LIMIT OFFSET
+	offsetFetch bool
+	// txRunner runs a transaction function, Transaction is used when it is nil
+	txRunner   func(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error
+	identQuote func(name string) string
+}
+
+var (
+	Postgres = Dialect{
+		Name:         "postgres",
+		placeholder:  dollar,
+		dollarQuotes: true,
+		copy:         true,
+		identQuote:   doubleQuoted,
+	}
+	// Cockroach runs transactions with the cockroach client retry loop
+	Cockroach = Dialect{
+		Name:         "cockroachdb",
+		placeholder:  dollar,
+		dollarQuotes: true,
+		copy:         true,
+		txRunner:     crdb.ExecuteTx,
+		identQuote:   doubleQuoted,
+	}
+	Redshift = Dialect{
+		Name:         "redshift",
+		placeholder:  dollar,
+		dollarQuotes: true,
+		savepoints:   noSavepoints,
+		identQuote:   doubleQuoted,
+	}
+	Crate = Dialect{
+		Name:           "crate",
+		placeholder:    dollar,
+		noTransactions: true,
+		savepoints:     noSavepoints,
+		identQuote:     doubleQuoted,
+	}
+	MySQL = Dialect{
+		Name:             "mysql",
+		placeholder:      question,
+		backslashEscapes: true,
+		identQuote:       backQuoted,
+	}
+	MSSQL = Dialect{
+		Name:               "mssql",
+		placeholder:        question,
+		bracketIdentifiers: true,
+		savepoints:         transactSavepoints,
+		offsetFetch:        true,
+		identQuote:         bracketQuoted,
+	}
+	SQLServer = Dialect{
+		Name:               "sqlserver",
+		placeholder:        atP,
+		bracketIdentifiers: true,
+		savepoints:         transactSavepoints,
+		offsetFetch:        true,
+		identQuote:         bracketQuoted,
+	}
+	// SQLite is used by the engine conformance tests
+	SQLite = Dialect{
+		Name:        "sqlite",
+		placeholder: question,
+		identQuote:  doubleQuoted,
+	}
+)
+
+// Bind returns the placeholder of the n-th parameter, n starts at 1
+func (d Dialect) Bind(n int) string {
+	switch d.placeholder {
+	case dollar:
+		return fmt.Sprintf("$%d", n)
+	case atP:
+		return fmt.Sprintf("@p%d", n)
+	default:
+		return "?"
+	}
+}
+
+// QuoteIdentifier quotes a table or column name, dotted names are quoted part by part
+func (d Dialect) QuoteIdentifier(name string) string {
+	if d.identQuote == nil {
+		return name
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = d.identQuote(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (d Dialect) runTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	if d.txRunner != nil {
+		return d.txRunner(ctx, db, opts, fn)
+	}
+	return Transaction(ctx, db, opts, fn)
+}
+
+func (d Dialect) savepointSQL(name string) (save, rollback, release string) {
+	if d.savepoints == transactSavepoints {
+		return "SAVE TRANSACTION " + name, "ROLLBACK TRANSACTION " + name, ""
+	}
+	return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, "RELEASE SAVEPOINT " + name
+}
+
+func doubleQuoted(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func backQuoted(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func bracketQuoted(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
@@ -0,0 +1,295 @@
+package sqlengine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+const (
+	defaultMaxIdleConnections           = 10
+	defaultMaxOpenConnections           = 100
+	defaultConnectionMaxLifetimeSeconds = 3600
+)
+
+var methodsMap = map[string]string{
+	"query":       "query",
+	"exec":        "exec",
+	"transaction": "transaction",
+	"bulk_insert": "bulk_insert",
+	"health":      "health",
+}
+
+var isolationLevelsMap = map[string]string{
+	"read_uncommitted": "ReadUncommitted",
+	"read_committed":   "ReadCommitted",
+	"repeatable_read":  "RepeatableRead",
+	"serializable":     "Serializable",
+	"":                 "Default",
+	// the connectors list the isolation levels by their sql package names
+	"Default":         "Default",
+	"ReadUncommitted": "ReadUncommitted",
+	"ReadCommitted":   "ReadCommitted",
+	"RepeatableRead":  "RepeatableRead",
+	"Serializable":    "Serializable",
+}
+
+// Options holds the connection pool and statement settings shared by all the sql targets
+type Options struct {
+	// MaxIdleConnections sets the maximum number of connections in the idle connection pool
+	MaxIdleConnections int
+	// MaxOpenConnections sets the maximum number of open connections to the database
+	MaxOpenConnections int
+	// ConnectionMaxLifetimeSeconds sets the maximum amount of time a connection may be reused
+	ConnectionMaxLifetimeSeconds int
+	// ConnectionMaxIdleTimeSeconds sets the maximum amount of time a connection may be idle, 0 keeps idle connections open
+	ConnectionMaxIdleTimeSeconds int
+	// StatementTimeoutSeconds sets the default timeout of a request, 0 for no timeout
+	StatementTimeoutSeconds int
+}
+
+// ParseOptions parses the connection pool and statement timeout properties
+func ParseOptions(props types.Metadata) (Options, error) {
+	o := Options{}
+	var err error
+	o.MaxIdleConnections, err = props.ParseIntWithRange("max_idle_connections", defaultMaxIdleConnections, 1, math.MaxInt32)
+	if err != nil {
+		return Options{}, fmt.Errorf("error parsing max idle connections value, %w", err)
+	}
+	o.MaxOpenConnections, err = props.ParseIntWithRange("max_open_connections", defaultMaxOpenConnections, 1, math.MaxInt32)
+	if err != nil {
+		return Options{}, fmt.Errorf("error parsing max open connections value, %w", err)
+	}
+	o.ConnectionMaxLifetimeSeconds, err = props.ParseIntWithRange("connection_max_lifetime_seconds", defaultConnectionMaxLifetimeSeconds, 1, math.MaxInt32)
+	if err != nil {
+		return Options{}, fmt.Errorf("error parsing connection max lifetime seconds value, %w", err)
+	}
+	o.ConnectionMaxIdleTimeSeconds, err = props.ParseIntWithRange("connection_max_idle_time_seconds", 0, 0, math.MaxInt32)
+	if err != nil {
+		return Options{}, fmt.Errorf("error parsing connection max idle time seconds value, %w", err)
+	}
+	o.StatementTimeoutSeconds, err = props.ParseIntWithRange("statement_timeout_seconds", 0, 0, math.MaxInt32)
+	if err != nil {
+		return Options{}, fmt.Errorf("error parsing statement timeout seconds value, %w", err)
+	}
+	return o, nil
+}
+
+type metadata struct {
+	method         string
+	isolationLevel sql.IsolationLevel
+	readOnly       bool
+	timeout        time.Duration
+	query          QueryOptions
+}
+
+func (e *Engine) parseMetadata(meta types.Metadata) (metadata, error) {
+	m := metadata{}
+	var err error
+	m.method, err = meta.ParseStringMap("method", methodsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing method, %w", err)
+	}
+	if e.dialect.noTransactions && (m.method == "transaction" || m.method == "bulk_insert") {
+		return metadata{}, fmt.Errorf("method %s is not supported by %s", m.method, e.dialect.Name)
+	}
+	isolationLevel, err := meta.ParseStringMap("isolation_level", isolationLevelsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing isolation_level, %w", err)
+	}
+	m.isolationLevel = convertToSqlIsolationLevel(isolationLevel)
+	m.readOnly = meta.ParseBool("read_only", false)
+	timeout, err := meta.ParseIntWithRange("timeout_seconds", e.opts.StatementTimeoutSeconds, 0, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing timeout_seconds, %w", err)
+	}
+	m.timeout = time.Duration(timeout) * time.Second
+	m.query, err = ParseQueryOptions(meta)
+	if err != nil {
+		return metadata{}, err
+	}
+	return m, nil
+}
+
+func convertToSqlIsolationLevel(value string) sql.IsolationLevel {
+	switch value {
+	case "ReadUncommitted":
+		return sql.LevelReadUncommitted
+	case "ReadCommitted":
+		return sql.LevelReadCommitted
+	case "RepeatableRead":
+		return sql.LevelRepeatableRead
+	case "Serializable":
+		return sql.LevelSerializable
+	default:
+		return sql.LevelDefault
+	}
+}
+
+// Engine executes the sql target requests over a database connection pool
+type Engine struct {
+	db      *sql.DB
+	dialect Dialect
+	opts    Options
+}
+
+// New creates an engine over an opened database and applies the connection pool options
+func New(db *sql.DB, d Dialect, opts Options) *Engine {
+	db.SetMaxOpenConns(opts.MaxOpenConnections)
+	db.SetMaxIdleConns(opts.MaxIdleConnections)
+	db.SetConnMaxLifetime(time.Duration(opts.ConnectionMaxLifetimeSeconds) * time.Second)
+	db.SetConnMaxIdleTime(time.Duration(opts.ConnectionMaxIdleTimeSeconds) * time.Second)
+	return &Engine{
+		db:      db,
+		dialect: d,
+		opts:    opts,
+	}
+}
+
+// Do executes a request according to its method, the request timeout_seconds metadata overrides the statement timeout
+func (e *Engine) Do(ctx context.Context, req *types.Request) (*types.Response, error) {
+	meta, err := e.parseMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	if meta.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, meta.timeout)
+		defer cancel()
+	}
+	switch meta.method {
+	case "query":
+		return e.query(ctx, meta, req.Data)
+	case "exec":
+		return e.exec(ctx, req.Data)
+	case "transaction":
+		return e.transaction(ctx, meta, req.Data)
+	case "bulk_insert":
+		return e.bulkInsert(ctx, meta, req.Data)
+	case "health":
+		return e.Health(ctx)
+	}
+	return nil, fmt.Errorf("invalid method %s", meta.method)
+}
+
+func (e *Engine) txOptions(meta metadata) *sql.TxOptions {
+	return &sql.TxOptions{
+		Isolation: meta.isolationLevel,
+		ReadOnly:  meta.readOnly,
+	}
+}
+
+func (e *Engine) query(ctx context.Context, meta metadata, value []byte) (*types.Response, error) {
+	stmt, err := ParseQuery(value, e.dialect)
+	if err != nil {
+		return nil, err
+	}
+	if stmt.SQL == "" {
+		return nil, fmt.Errorf("no query statement found")
+	}
+	page, err := e.dialect.PageQuery(stmt, meta.query)
+	if err != nil {
+		return nil, err
+	}
+	if !meta.readOnly || e.dialect.noTransactions {
+		rows, err := e.db.QueryContext(ctx, page.SQL, page.Args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		return RowsResponse(rows, e.dialect, stmt, meta.query)
+	}
+	// a read only query runs in a read only transaction which is rolled back once the rows are read
+	tx, err := e.db.BeginTx(ctx, e.txOptions(meta))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	rows, err := tx.QueryContext(ctx, page.SQL, page.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return RowsResponse(rows, e.dialect, stmt, meta.query)
+}
+
+func (e *Engine) exec(ctx context.Context, value []byte) (*types.Response, error) {
+	stmts, err := ParseStatements(value, e.dialect)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) == 0 {
+		return nil, fmt.Errorf("no exec statement found")
+	}
+	results, err := e.dialect.execStatements(ctx, e.db, stmts, false)
+	if err != nil {
+		return nil, err
+	}
+	return ResultsResponse(results), nil
+}
+
+func (e *Engine) transaction(ctx context.Context, meta metadata, value []byte) (*types.Response, error) {
+	stmts, err := ParseStatements(value, e.dialect)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) == 0 {
+		return nil, fmt.Errorf("no transaction statements found")
+	}
+	var results []Result
+	err = e.dialect.runTx(ctx, e.db, e.txOptions(meta), func(tx *sql.Tx) error {
+		var execErr error
+		results, execErr = e.dialect.execStatements(ctx, tx, stmts, true)
+		return execErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ResultsResponse(results), nil
+}
+
+func (e *Engine) bulkInsert(ctx context.Context, meta metadata, value []byte) (*types.Response, error) {
+	req, err := ParseBulkInsert(value)
+	if err != nil {
+		return nil, err
+	}
+	var result Result
+	err = e.dialect.runTx(ctx, e.db, e.txOptions(meta), func(tx *sql.Tx) error {
+		var execErr error
+		result, execErr = bulkInsert(ctx, tx, e.dialect, req)
+		return execErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ResultsResponse([]Result{result}), nil
+}
+
+// Health pings the database and returns the connection pool statistics
+func (e *Engine) Health(ctx context.Context) (*types.Response, error) {
+	if err := e.db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("error reaching %s, %w", e.dialect.Name, err)
+	}
+	stats := e.db.Stats()
+	return types.NewResponse().
+		SetMetadataKeyValue("open_connections", strconv.Itoa(stats.OpenConnections)).
+		SetMetadataKeyValue("in_use", strconv.Itoa(stats.InUse)).
+		SetMetadataKeyValue("idle", strconv.Itoa(stats.Idle)).
+		SetMetadataKeyValue("wait_count", strconv.FormatInt(stats.WaitCount, 10)).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+// Close closes the database
+func (e *Engine) Close() error {
+	if e == nil || e.db == nil {
+		return nil
+	}
+	return e.db.Close()
+}
@@ -0,0 +1,393 @@
+package sqlengine
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newTestEngine(t *testing.T, opts Options) *Engine {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	e := New(db, SQLite, opts)
+	t.Cleanup(func() {
+		_ = e.Close()
+	})
+	_, err = e.Do(context.Background(), types.NewRequest().
+		SetMetadataKeyValue("method", "exec").
+		SetData([]byte(`CREATE TABLE post (id INTEGER PRIMARY KEY, title TEXT NOT NULL, score REAL, payload BLOB)`)))
+	require.NoError(t, err)
+	return e
+}
+
+func defaultTestOptions() Options {
+	return Options{
+		MaxIdleConnections:           1,
+		MaxOpenConnections:           1,
+		ConnectionMaxLifetimeSeconds: 3600,
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	got, err := ParseOptions(types.Metadata{})
+	require.NoError(t, err)
+	require.Equal(t, Options{
+		MaxIdleConnections:           10,
+		MaxOpenConnections:           100,
+		ConnectionMaxLifetimeSeconds: 3600,
+	}, got)
+	got, err = ParseOptions(types.Metadata{
+		"max_idle_connections":             "2",
+		"max_open_connections":             "4",
+		"connection_max_lifetime_seconds":  "60",
+		"connection_max_idle_time_seconds": "30",
+		"statement_timeout_seconds":        "5",
+	})
+	require.NoError(t, err)
+	require.Equal(t, Options{
+		MaxIdleConnections:           2,
+		MaxOpenConnections:           4,
+		ConnectionMaxLifetimeSeconds: 60,
+		ConnectionMaxIdleTimeSeconds: 30,
+		StatementTimeoutSeconds:      5,
+	}, got)
+	_, err = ParseOptions(types.Metadata{"statement_timeout_seconds": "-1"})
+	require.Error(t, err)
+}
+
+func TestEngine_Do(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests []*types.Request
+		wantData string
+		wantMeta map[string]string
+		wantErr  bool
+	}{
+		{
+			name: "exec and query",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "exec").
+					SetData([]byte(`INSERT INTO post (id, title) VALUES (1, 'a;b'); INSERT INTO post (id, title) VALUES (2, 'c')`)),
+				types.NewRequest().
+					SetMetadataKeyValue("method", "query").
+					SetData([]byte(`SELECT id, title FROM post ORDER BY id`)),
+			},
+			wantData: `[{"id":1,"title":"a;b"},{"id":2,"title":"c"}]`,
+			wantMeta: map[string]string{"row_count": "2", "result": "ok"},
+		},
+		{
+			name: "parameterised exec with batch",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "exec").
+					SetData([]byte(`{"sql":"INSERT INTO post (id, title, score) VALUES (:id, :title, :score)","batch":[{"id":1,"title":"a","score":1.5},{"id":2,"title":"b","score":null}]}`)),
+			},
+			wantData: `[{"statement":0,"rows_affected":2,"last_insert_id":2,"executions":2}]`,
+			wantMeta: map[string]string{"rows_affected": "2", "result": "ok"},
+		},
+		{
+			name: "parameterised query",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "exec").
+					SetData([]byte(`INSERT INTO post (id, title) VALUES (1, 'a'), (2, 'b')`)),
+				types.NewRequest().
+					SetMetadataKeyValue("method", "query").
+					SetData([]byte(`{"sql":"SELECT title FROM post WHERE id = ?","params":[2]}`)),
+			},
+			wantData: `[{"title":"b"}]`,
+			wantMeta: map[string]string{"row_count": "1"},
+		},
+		{
+			name: "transaction rolls back on failure",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "transaction").
+					SetData([]byte(`INSERT INTO post (id, title) VALUES (1, 'a'); INSERT INTO post (id, title) VALUES (1, 'b')`)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "transaction with isolation level",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "transaction").
+					SetMetadataKeyValue("isolation_level", "serializable").
+					SetData([]byte(`INSERT INTO post (id, title) VALUES (1, 'a'); UPDATE post SET title = 'b' WHERE id = 1`)),
+				types.NewRequest().
+					SetMetadataKeyValue("method", "query").
+					SetData([]byte(`SELECT title FROM post`)),
+			},
+			wantData: `[{"title":"b"}]`,
+		},
+		{
+			name: "transaction savepoint rolls back the failed statement only",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "transaction").
+					SetData([]byte(`{"statements":[
+						{"sql":"INSERT INTO post (id, title) VALUES (1, 'a')"},
+						{"sql":"INSERT INTO post (id, title) VALUES (1, 'duplicate')","savepoint":"sp1"},
+						{"sql":"INSERT INTO post (id, title) VALUES (2, 'b')","savepoint":"sp2"}
+					]}`)),
+				types.NewRequest().
+					SetMetadataKeyValue("method", "query").
+					SetData([]byte(`SELECT id, title FROM post ORDER BY id`)),
+			},
+			wantData: `[{"id":1,"title":"a"},{"id":2,"title":"b"}]`,
+		},
+		{
+			name: "savepoint outside of a transaction",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "exec").
+					SetData([]byte(`{"sql":"INSERT INTO post (id, title) VALUES (1, 'a')","savepoint":"sp1"}`)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid savepoint name",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "transaction").
+					SetData([]byte(`{"sql":"INSERT INTO post (id, title) VALUES (1, 'a')","savepoint":"sp1; DROP TABLE post"}`)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "bulk insert",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "bulk_insert").
+					SetData([]byte(`{"table":"post","columns":["id","title"],"rows":[[1,"a"],{"id":2,"title":"b"},[3,"c"]]}`)),
+				types.NewRequest().
+					SetMetadataKeyValue("method", "query").
+					SetData([]byte(`SELECT count(*) AS total FROM post`)),
+			},
+			wantData: `[{"total":3}]`,
+		},
+		{
+			name: "bulk insert rolls back on failure",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "bulk_insert").
+					SetData([]byte(`{"table":"post","columns":["id","title"],"rows":[[1,"a"],[1,"b"]]}`)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "query formats",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "exec").
+					SetData([]byte(`INSERT INTO post (id, title, score) VALUES (1, 'a,b', 2.5), (2, 'c', NULL)`)),
+				types.NewRequest().
+					SetMetadataKeyValue("method", "query").
+					SetMetadataKeyValue("result_format", "csv").
+					SetData([]byte(`SELECT id, title, score FROM post ORDER BY id`)),
+			},
+			wantData: "id,title,score\n1,\"a,b\",2.5\n2,c,\n",
+		},
+		{
+			name: "health",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "health"),
+			},
+			wantMeta: map[string]string{"result": "ok", "in_use": "0"},
+		},
+		{
+			name: "invalid method",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "bad-method"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid isolation level",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "transaction").
+					SetMetadataKeyValue("isolation_level", "bad-level").
+					SetData([]byte(`SELECT 1`)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty exec",
+			requests: []*types.Request{
+				types.NewRequest().
+					SetMetadataKeyValue("method", "exec").
+					SetData([]byte(` ; `)),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEngine(t, defaultTestOptions())
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			var got *types.Response
+			var err error
+			for _, req := range tt.requests {
+				got, err = e.Do(ctx, req)
+				if err != nil {
+					break
+				}
+			}
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantData != "" {
+				if json.Valid([]byte(tt.wantData)) {
+					require.JSONEq(t, tt.wantData, string(got.Data))
+				} else {
+					require.Equal(t, tt.wantData, string(got.Data))
+				}
+			}
+			for key, value := range tt.wantMeta {
+				require.Equal(t, value, got.Metadata[key], key)
+			}
+		})
+	}
+}
+
+func TestEngine_TransactionSavepointResults(t *testing.T) {
+	e := newTestEngine(t, defaultTestOptions())
+	got, err := e.Do(context.Background(), types.NewRequest().
+		SetMetadataKeyValue("method", "transaction").
+		SetData([]byte(`[{"sql":"INSERT INTO post (id, title) VALUES (1, 'a')"},{"sql":"INSERT INTO post (id) VALUES (2)","savepoint":"sp"}]`)))
+	require.NoError(t, err)
+	var results []Result
+	require.NoError(t, json.Unmarshal(got.Data, &results))
+	require.Len(t, results, 2)
+	require.False(t, results[0].RolledBack)
+	require.True(t, results[1].RolledBack)
+	require.NotEmpty(t, results[1].Error)
+	require.Equal(t, "1", got.Metadata["rows_affected"])
+}
+
+func TestEngine_QueryPagination(t *testing.T) {
+	e := newTestEngine(t, defaultTestOptions())
+	ctx := context.Background()
+	_, err := e.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "bulk_insert").
+		SetData([]byte(`{"table":"post","columns":["id","title"],"rows":[[1,"a"],[2,"b"],[3,"c"],[4,"d"],[5,"e"]]}`)))
+	require.NoError(t, err)
+	var titles []string
+	cursor := ""
+	pages := 0
+	for {
+		got, err := e.Do(ctx, types.NewRequest().
+			SetMetadataKeyValue("method", "query").
+			SetMetadataKeyValue("result_format", "ndjson").
+			SetMetadataKeyValue("max_rows", "2").
+			SetMetadataKeyValue("cursor", cursor).
+			SetData([]byte(`SELECT title FROM post ORDER BY id`)))
+		require.NoError(t, err)
+		pages++
+		dec := json.NewDecoder(bytes.NewReader(got.Data))
+		for dec.More() {
+			row := map[string]string{}
+			require.NoError(t, dec.Decode(&row))
+			titles = append(titles, row["title"])
+		}
+		cursor = got.Metadata["next_cursor"]
+		if cursor == "" {
+			break
+		}
+	}
+	require.Equal(t, 3, pages)
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, titles)
+	got, err := e.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "query").
+		SetMetadataKeyValue("max_rows", "3").
+		SetData([]byte(`SELECT title FROM post ORDER BY id LIMIT 4;`)))
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"title":"a"},{"title":"b"},{"title":"c"}]`, string(got.Data))
+	got, err = e.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "query").
+		SetMetadataKeyValue("max_rows", "3").
+		SetMetadataKeyValue("cursor", got.Metadata["next_cursor"]).
+		SetData([]byte(`SELECT title FROM post ORDER BY id LIMIT 4;`)))
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"title":"d"}]`, string(got.Data))
+	require.Empty(t, got.Metadata["next_cursor"])
+	_, err = e.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "query").
+		SetMetadataKeyValue("max_rows", "2").
+		SetMetadataKeyValue("cursor", "bad-cursor").
+		SetData([]byte(`SELECT title FROM post ORDER BY id`)))
+	require.Error(t, err)
+}
+
+func TestEngine_ReadOnly(t *testing.T) {
+	e := newTestEngine(t, defaultTestOptions())
+	got, err := e.Do(context.Background(), types.NewRequest().
+		SetMetadataKeyValue("method", "query").
+		SetMetadataKeyValue("read_only", "true").
+		SetData([]byte(`SELECT count(*) AS total FROM post`)))
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"total":0}]`, string(got.Data))
+}
+
+func TestEngine_Timeout(t *testing.T) {
+	slowQuery := []byte(`WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT max(x) FROM c`)
+	opts := defaultTestOptions()
+	opts.StatementTimeoutSeconds = 1
+	e := newTestEngine(t, opts)
+	start := time.Now()
+	_, err := e.Do(context.Background(), types.NewRequest().
+		SetMetadataKeyValue("method", "query").
+		SetData(slowQuery))
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 10*time.Second)
+
+	e = newTestEngine(t, defaultTestOptions())
+	start = time.Now()
+	_, err = e.Do(context.Background(), types.NewRequest().
+		SetMetadataKeyValue("method", "query").
+		SetMetadataKeyValue("timeout_seconds", "1").
+		SetData(slowQuery))
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestEngine_NoTransactions(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	dialect := SQLite
+	dialect.noTransactions = true
+	e := New(db, dialect, defaultTestOptions())
+	defer func() {
+		_ = e.Close()
+	}()
+	for _, method := range []string{"transaction", "bulk_insert"} {
+		_, err := e.Do(context.Background(), types.NewRequest().
+			SetMetadataKeyValue("method", method).
+			SetData([]byte(`SELECT 1`)))
+		require.Error(t, err)
+	}
+}
+
+func TestDialect_SavepointSQL(t *testing.T) {
+	save, rollback, release := Postgres.savepointSQL("sp")
+	require.Equal(t, []string{"SAVEPOINT sp", "ROLLBACK TO SAVEPOINT sp", "RELEASE SAVEPOINT sp"}, []string{save, rollback, release})
+	save, rollback, release = MSSQL.savepointSQL("sp")
+	require.Equal(t, []string{"SAVE TRANSACTION sp", "ROLLBACK TRANSACTION sp", ""}, []string{save, rollback, release})
+	_, err := Redshift.execStatements(context.Background(), nil, []Statement{{SQL: "SELECT 1", Savepoint: "sp"}}, true)
+	require.Error(t, err)
+}
@@ -0,0 +1,261 @@
+package sqlengine
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/lib/pq"
+)
+
+// Execer is implemented by both *sql.DB and *sql.Tx
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// Result holds the outcome of a single statement, batch statements report the totals of all their executions
+type Result struct {
+	Statement    int    `json:"statement"`
+	RowsAffected int64  `json:"rows_affected"`
+	LastInsertId *int64 `json:"last_insert_id,omitempty"`
+	Executions   int    `json:"executions,omitempty"`
+	// RolledBack is set when the statement failed and the transaction was rolled back to the statement savepoint
+	RolledBack bool   `json:"rolled_back,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (r *Result) add(res sql.Result) {
+	if n, err := res.RowsAffected(); err == nil {
+		r.RowsAffected += n
+	}
+	// drivers without last insert id support, like postgres and mssql, return an error
+	if id, err := res.LastInsertId(); err == nil {
+		r.LastInsertId = &id
+	}
+}
+
+// TotalRowsAffected sums the rows affected of all the results
+func TotalRowsAffected(results []Result) int64 {
+	var total int64
+	for _, r := range results {
+		total += r.RowsAffected
+	}
+	return total
+}
+
+// ResultsResponse returns the per statement results as the response data with the total rows affected in the metadata
+func ResultsResponse(results []Result) *types.Response {
+	data, _ := json.Marshal(results)
+	return types.NewResponse().
+		SetData(data).
+		SetMetadataKeyValue("rows_affected", fmt.Sprintf("%d", TotalRowsAffected(results))).
+		SetMetadataKeyValue("result", "ok")
+}
+
+// execStatements executes the statements in order and stops on the first failure, unless inTx is set and the failed statement has a savepoint
+func (d Dialect) execStatements(ctx context.Context, e Execer, stmts []Statement, inTx bool) ([]Result, error) {
+	results := make([]Result, 0, len(stmts))
+	for i, stmt := range stmts {
+		var result Result
+		var err error
+		if stmt.Savepoint != "" {
+			result, err = d.execSavepoint(ctx, e, stmt, inTx)
+		} else {
+			result, err = execStatement(ctx, e, stmt)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error on statement %d, %w", i, err)
+		}
+		result.Statement = i
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// execSavepoint executes a statement wrapped by a savepoint, a statement failure is reported in the result after rolling back to the savepoint
+func (d Dialect) execSavepoint(ctx context.Context, e Execer, stmt Statement, inTx bool) (Result, error) {
+	if !inTx {
+		return Result{}, fmt.Errorf("savepoint %s can only be set in a transaction", stmt.Savepoint)
+	}
+	if d.savepoints == noSavepoints {
+		return Result{}, fmt.Errorf("savepoints are not supported by %s", d.Name)
+	}
+	save, rollback, release := d.savepointSQL(stmt.Savepoint)
+	if _, err := e.ExecContext(ctx, save); err != nil {
+		return Result{}, fmt.Errorf("error setting savepoint %s, %w", stmt.Savepoint, err)
+	}
+	result, execErr := execStatement(ctx, e, stmt)
+	if execErr != nil {
+		if _, err := e.ExecContext(ctx, rollback); err != nil {
+			return Result{}, fmt.Errorf("error rolling back to savepoint %s, %w", stmt.Savepoint, err)
+		}
+		return Result{RolledBack: true, Error: execErr.Error()}, nil
+	}
+	if release != "" {
+		if _, err := e.ExecContext(ctx, release); err != nil {
+			return Result{}, fmt.Errorf("error releasing savepoint %s, %w", stmt.Savepoint, err)
+		}
+	}
+	return result, nil
+}
+
+func execStatement(ctx context.Context, e Execer, stmt Statement) (Result, error) {
+	result := Result{}
+	if stmt.Batch == nil {
+		res, err := e.ExecContext(ctx, stmt.SQL, stmt.Args...)
+		if err != nil {
+			return Result{}, err
+		}
+		result.add(res)
+		return result, nil
+	}
+	prepared, err := e.PrepareContext(ctx, stmt.SQL)
+	if err != nil {
+		return Result{}, err
+	}
+	defer func() {
+		_ = prepared.Close()
+	}()
+	for i, args := range stmt.Batch {
+		res, err := prepared.ExecContext(ctx, args...)
+		if err != nil {
+			return Result{}, fmt.Errorf("batch entry %d, %w", i, err)
+		}
+		result.add(res)
+		result.Executions++
+	}
+	return result, nil
+}
+
+// Transaction runs fn in a transaction, the transaction is rolled back when fn fails or panics
+func Transaction(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		if rollBackErr := tx.Rollback(); rollBackErr != nil {
+			return rollBackErr
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// BulkInsertRequest inserts rows to a table, each row is either an array of values in the columns order or an object keyed by column name
+type BulkInsertRequest struct {
+	Table   string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+type bulkInsertRequest struct {
+	Table   string            `json:"table"`
+	Columns []string          `json:"columns"`
+	Rows    []json.RawMessage `json:"rows"`
+}
+
+// ParseBulkInsert parses a json bulk insert request
+func ParseBulkInsert(data []byte) (BulkInsertRequest, error) {
+	req := bulkInsertRequest{}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return BulkInsertRequest{}, fmt.Errorf("invalid bulk insert request, %w", err)
+	}
+	if strings.TrimSpace(req.Table) == "" {
+		return BulkInsertRequest{}, fmt.Errorf("invalid bulk insert request, missing table")
+	}
+	if len(req.Columns) == 0 {
+		return BulkInsertRequest{}, fmt.Errorf("invalid bulk insert request, missing columns")
+	}
+	if len(req.Rows) == 0 {
+		return BulkInsertRequest{}, fmt.Errorf("invalid bulk insert request, missing rows")
+	}
+	bulk := BulkInsertRequest{
+		Table:   req.Table,
+		Columns: req.Columns,
+		Rows:    make([][]interface{}, 0, len(req.Rows)),
+	}
+	for i, raw := range req.Rows {
+		value, err := decodeValue(bytes.TrimSpace(raw))
+		if err != nil {
+			return BulkInsertRequest{}, fmt.Errorf("invalid bulk insert row %d, %w", i, err)
+		}
+		row := make([]interface{}, len(req.Columns))
+		switch v := value.(type) {
+		case []interface{}:
+			if len(v) != len(req.Columns) {
+				return BulkInsertRequest{}, fmt.Errorf("invalid bulk insert row %d, expected %d values, got %d", i, len(req.Columns), len(v))
+			}
+			for j := range v {
+				row[j] = toArg(v[j])
+			}
+		case map[string]interface{}:
+			for j, col := range req.Columns {
+				row[j] = toArg(v[col])
+			}
+		default:
+			return BulkInsertRequest{}, fmt.Errorf("invalid bulk insert row %d, row must be an array or an object", i)
+		}
+		bulk.Rows = append(bulk.Rows, row)
+	}
+	return bulk, nil
+}
+
+// bulkInsert inserts the rows with COPY when the dialect supports it, otherwise with a prepared insert statement executed per row.
+// It must run in a transaction
+func bulkInsert(ctx context.Context, tx Execer, d Dialect, req BulkInsertRequest) (Result, error) {
+	if d.copy {
+		return copyIn(ctx, tx, req)
+	}
+	cols := make([]string, len(req.Columns))
+	binds := make([]string, len(req.Columns))
+	for i, col := range req.Columns {
+		cols[i] = d.QuoteIdentifier(col)
+		binds[i] = d.Bind(i + 1)
+	}
+	stmt := Statement{
+		SQL:   fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", d.QuoteIdentifier(req.Table), strings.Join(cols, ", "), strings.Join(binds, ", ")),
+		Batch: req.Rows,
+	}
+	return execStatement(ctx, tx, stmt)
+}
+
+func copyIn(ctx context.Context, tx Execer, req BulkInsertRequest) (Result, error) {
+	query := pq.CopyIn(req.Table, req.Columns...)
+	if idx := strings.Index(req.Table, "."); idx > 0 {
+		query = pq.CopyInSchema(req.Table[:idx], req.Table[idx+1:], req.Columns...)
+	}
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return Result{}, err
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+	for i, row := range req.Rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return Result{}, fmt.Errorf("bulk insert row %d, %w", i, err)
+		}
+	}
+	// the final exec without args flushes the copy buffer and returns the copied rows count
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Executions: len(req.Rows)}
+	if n, err := res.RowsAffected(); err == nil {
+		result.RowsAffected = n
+	}
+	return result, nil
+}
@@ -0,0 +1,380 @@
+package sqlengine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+var resultFormatsMap = map[string]string{
+	"":       "json",
+	"json":   "json",
+	"ndjson": "ndjson",
+	"csv":    "csv",
+}
+
+// QueryOptions controls the query response format and pagination
+type QueryOptions struct {
+	Format  string
+	MaxRows int
+	Cursor  string
+}
+
+// ParseQueryOptions parses the result_format, max_rows and cursor request metadata keys
+func ParseQueryOptions(meta types.Metadata) (QueryOptions, error) {
+	o := QueryOptions{}
+	var err error
+	o.Format, err = meta.ParseStringMap("result_format", resultFormatsMap)
+	if err != nil {
+		return QueryOptions{}, fmt.Errorf("error parsing result_format, %w", err)
+	}
+	o.MaxRows, err = meta.ParseIntWithRange("max_rows", 0, 0, math.MaxInt32)
+	if err != nil {
+		return QueryOptions{}, fmt.Errorf("error parsing max_rows, %w", err)
+	}
+	o.Cursor = meta.ParseString("cursor", "")
+	return o, nil
+}
+
+// Column describes a result set column
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable *bool  `json:"nullable,omitempty"`
+}
+
+type cursor struct {
+	Offset int    `json:"offset"`
+	Query  string `json:"query"`
+}
+
+// queryHash binds a cursor to the statement it was issued for
+func queryHash(stmt Statement) string {
+	h := sha256.New()
+	h.Write([]byte(stmt.SQL))
+	b, _ := json.Marshal(stmt.Args)
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func decodeCursor(value string, stmt Statement) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor, %w", err)
+	}
+	c := cursor{}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return 0, fmt.Errorf("invalid cursor, %w", err)
+	}
+	if c.Query != queryHash(stmt) || c.Offset < 0 {
+		return 0, fmt.Errorf("invalid cursor, cursor does not match the query")
+	}
+	return c.Offset, nil
+}
+
+func encodeCursor(offset int, stmt Statement) string {
+	data, _ := json.Marshal(cursor{Offset: offset, Query: queryHash(stmt)})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// PageQuery returns the statement reading the result set page of the max_rows and cursor options, the page statement
+// reads one more row than max_rows which tells whether there is a next page. Pages are read with the database offset,
+// so paginated queries should have a deterministic order
+func (d Dialect) PageQuery(stmt Statement, opts QueryOptions) (Statement, error) {
+	offset, err := decodeCursor(opts.Cursor, stmt)
+	if err != nil {
+		return Statement{}, err
+	}
+	if opts.MaxRows == 0 {
+		if offset > 0 {
+			return Statement{}, fmt.Errorf("invalid cursor, max_rows must be set with cursor")
+		}
+		return stmt, nil
+	}
+	return Statement{SQL: d.paginate(stmt.SQL, offset, opts.MaxRows+1), Args: stmt.Args}, nil
+}
+
+// paginate limits the query to limit rows from offset. The limit clause is appended to a query without one,
+// otherwise the query is read as a subquery. The query is kept on its own lines so a trailing comment does not hide the clause
+func (d Dialect) paginate(sqlText string, offset, limit int) string {
+	sqlText = strings.TrimSuffix(strings.TrimSpace(sqlText), ";")
+	words := d.topLevelWords(sqlText)
+	if d.offsetFetch {
+		// sql server accepts OFFSET FETCH only after ORDER BY, and does not accept ORDER BY in a subquery without TOP or OFFSET
+		if words["ORDER"] && !words["OFFSET"] && !words["TOP"] {
+			return fmt.Sprintf("%s\nOFFSET %d ROWS FETCH NEXT %d ROWS ONLY", sqlText, offset, limit)
+		}
+		return fmt.Sprintf("SELECT * FROM (\n%s\n) AS kubemq_page ORDER BY (SELECT NULL) OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", sqlText, offset, limit)
+	}
+	if !words["LIMIT"] && !words["OFFSET"] && !words["FETCH"] && !words["FOR"] {
+		return fmt.Sprintf("%s\nLIMIT %d OFFSET %d", sqlText, limit, offset)
+	}
+	return fmt.Sprintf("SELECT * FROM (\n%s\n) AS kubemq_page LIMIT %d OFFSET %d", sqlText, limit, offset)
+}
+
+// topLevelWords returns the upper cased words found outside of literals, quoted identifiers, comments and parentheses
+func (d Dialect) topLevelWords(sqlText string) map[string]bool {
+	words := map[string]bool{}
+	depth := 0
+	for i := 0; i < len(sqlText); {
+		if next, ok := d.skip(sqlText, i); ok {
+			i = next
+			continue
+		}
+		switch c := sqlText[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(sqlText) && isIdentPart(sqlText[j]) {
+				j++
+			}
+			if depth == 0 {
+				words[strings.ToUpper(sqlText[i:j])] = true
+			}
+			i = j
+			continue
+		}
+		i++
+	}
+	return words
+}
+
+// RowsResponse reads the rows of a PageQuery statement to a response. The data holds the rows in the requested format, the metadata holds the columns,
+// the returned rows count and, when the result set has more rows than max_rows, the cursor of the next page.
+// The response is built in memory in every format, max_rows bounds its size
+func RowsResponse(rows *sql.Rows, d Dialect, stmt Statement, opts QueryOptions) (*types.Response, error) {
+	offset, err := decodeCursor(opts.Cursor, stmt)
+	if err != nil {
+		return nil, err
+	}
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("error reading columns, %w", err)
+	}
+	columns := make([]Column, len(colTypes))
+	for i, ct := range colTypes {
+		columns[i] = Column{
+			Name: ct.Name(),
+			Type: strings.ToUpper(ct.DatabaseTypeName()),
+		}
+		if nullable, ok := ct.Nullable(); ok {
+			columns[i].Nullable = &nullable
+		}
+	}
+	w := newRowsWriter(opts.Format, columns)
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	position, count, nextCursor := offset, 0, ""
+	for rows.Next() {
+		position++
+		if opts.MaxRows > 0 && count == opts.MaxRows {
+			nextCursor = encodeCursor(offset+count, stmt)
+			break
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("error scanning row %d, %w", position, err)
+		}
+		row := make([]interface{}, len(values))
+		for i, value := range values {
+			row[i] = d.convert(value, columns[i].Type)
+		}
+		if err := w.write(row); err != nil {
+			return nil, fmt.Errorf("error writing row %d, %w", position, err)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	data, err := w.close()
+	if err != nil {
+		return nil, err
+	}
+	columnsData, _ := json.Marshal(columns)
+	resp := types.NewResponse().
+		SetData(data).
+		SetMetadataKeyValue("columns", string(columnsData)).
+		SetMetadataKeyValue("row_count", strconv.Itoa(count)).
+		SetMetadataKeyValue("result", "ok")
+	if nextCursor != "" {
+		resp.SetMetadataKeyValue("next_cursor", nextCursor)
+	}
+	return resp, nil
+}
+
+// convert renders a scanned value to a json friendly value according to its database type
+func (d Dialect) convert(value interface{}, dbType string) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		if dbType == "DATE" {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		return v
+	case float32:
+		return d.convert(float64(v), dbType)
+	case []byte:
+		return d.convertBytes(v, dbType)
+	default:
+		return v
+	}
+}
+
+func (d Dialect) convertBytes(v []byte, dbType string) interface{} {
+	baseType := strings.TrimPrefix(dbType, "UNSIGNED ")
+	switch {
+	case baseType == "JSON" || baseType == "JSONB":
+		if json.Valid(v) {
+			return json.RawMessage(append([]byte(nil), v...))
+		}
+	case baseType == "UNIQUEIDENTIFIER" && len(v) == 16:
+		return formatMSSQLUUID(v)
+	case isNumericType(baseType):
+		s := string(v)
+		if _, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "xXnN") {
+			return json.Number(s)
+		}
+		return s
+	case isBinaryType(baseType):
+		return append([]byte(nil), v...)
+	}
+	return string(v)
+}
+
+func isNumericType(dbType string) bool {
+	switch dbType {
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT", "INT2", "INT4", "INT8",
+		"DECIMAL", "NUMERIC", "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "REAL", "YEAR":
+		return true
+	}
+	return false
+}
+
+func isBinaryType(dbType string) bool {
+	switch dbType {
+	case "BYTEA", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "IMAGE", "GEOMETRY":
+		return true
+	}
+	return false
+}
+
+// formatMSSQLUUID formats a uniqueidentifier, sql server stores the first three groups in little endian order
+func formatMSSQLUUID(b []byte) string {
+	u := []byte{b[3], b[2], b[1], b[0], b[5], b[4], b[7], b[6], b[8], b[9], b[10], b[11], b[12], b[13], b[14], b[15]}
+	s := hex.EncodeToString(u)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", s[0:8], s[8:12], s[12:16], s[16:20], s[20:32])
+}
+
+// rowsWriter renders the rows of a response, the csv and ndjson formats are written to a buffer and are not streamed
+type rowsWriter struct {
+	format  string
+	columns []Column
+	rows    []map[string]interface{}
+	buf     *bytes.Buffer
+	csv     *csv.Writer
+}
+
+func newRowsWriter(format string, columns []Column) *rowsWriter {
+	w := &rowsWriter{
+		format:  format,
+		columns: columns,
+		buf:     &bytes.Buffer{},
+	}
+	if format == "csv" {
+		w.csv = csv.NewWriter(w.buf)
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = col.Name
+		}
+		_ = w.csv.Write(header)
+	}
+	return w
+}
+
+func (w *rowsWriter) write(row []interface{}) error {
+	switch w.format {
+	case "csv":
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = csvValue(value)
+		}
+		return w.csv.Write(record)
+	case "ndjson":
+		data, err := json.Marshal(w.object(row))
+		if err != nil {
+			return err
+		}
+		w.buf.Write(data)
+		w.buf.WriteByte('\n')
+		return nil
+	default:
+		w.rows = append(w.rows, w.object(row))
+		return nil
+	}
+}
+
+func (w *rowsWriter) object(row []interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(row))
+	for i, value := range row {
+		m[w.columns[i].Name] = value
+	}
+	return m
+}
+
+func (w *rowsWriter) close() ([]byte, error) {
+	switch w.format {
+	case "csv":
+		w.csv.Flush()
+		return w.buf.Bytes(), w.csv.Error()
+	case "ndjson":
+		return w.buf.Bytes(), nil
+	default:
+		if w.rows == nil {
+			return nil, nil
+		}
+		return json.Marshal(w.rows)
+	}
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.RawMessage:
+		return string(v)
+	case json.Number:
+		return v.String()
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,116 @@
+package sqlengine
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialect_convert(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 10, 20, 30, 500, time.UTC)
+	tests := []struct {
+		name    string
+		dialect Dialect
+		value   interface{}
+		dbType  string
+		want    interface{}
+	}{
+		{name: "null", dialect: Postgres, value: nil, dbType: "TEXT", want: nil},
+		{name: "timestamp", dialect: Postgres, value: ts, dbType: "TIMESTAMPTZ", want: "2024-03-01T10:20:30.0000005Z"},
+		{name: "date", dialect: Postgres, value: ts, dbType: "DATE", want: "2024-03-01"},
+		{name: "decimal", dialect: Postgres, value: []byte("12345678901234567890.123"), dbType: "NUMERIC", want: json.Number("12345678901234567890.123")},
+		{name: "decimal nan", dialect: Postgres, value: []byte("NaN"), dbType: "NUMERIC", want: "NaN"},
+		{name: "uuid", dialect: Postgres, value: []byte("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"), dbType: "UUID", want: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+		{name: "json", dialect: Postgres, value: []byte(`{"a":1}`), dbType: "JSONB", want: json.RawMessage(`{"a":1}`)},
+		{name: "bytea", dialect: Postgres, value: []byte{0x01, 0x02}, dbType: "BYTEA", want: []byte{0x01, 0x02}},
+		{name: "text bytes", dialect: MySQL, value: []byte("hello"), dbType: "VARCHAR", want: "hello"},
+		{name: "mysql int text", dialect: MySQL, value: []byte("42"), dbType: "INT", want: json.Number("42")},
+		{name: "mysql unsigned bigint", dialect: MySQL, value: []byte("18446744073709551615"), dbType: "UNSIGNED BIGINT", want: json.Number("18446744073709551615")},
+		{name: "mysql tinyint text", dialect: MySQL, value: []byte("1"), dbType: "TINYINT", want: json.Number("1")},
+		{name: "mysql tinyint number", dialect: MySQL, value: int64(7), dbType: "TINYINT", want: int64(7)},
+		{name: "postgres smallint", dialect: Postgres, value: int64(1), dbType: "INT2", want: int64(1)},
+		{name: "mssql uniqueidentifier", dialect: MSSQL, value: []byte{0x99, 0xbc, 0xee, 0xa0, 0x0b, 0x9c, 0xf8, 0x4e, 0xbb, 0x6d, 0x6b, 0xb9, 0xbd, 0x38, 0x0a, 0x11}, dbType: "UNIQUEIDENTIFIER", want: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.dialect.convert(tt.value, tt.dbType))
+		})
+	}
+}
+
+func TestRowsWriter(t *testing.T) {
+	columns := []Column{{Name: "id"}, {Name: "title"}, {Name: "doc"}}
+	rows := [][]interface{}{
+		{int64(1), nil, json.RawMessage(`{"a":1}`)},
+		{int64(2), "x,y", nil},
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "json", want: `[{"doc":{"a":1},"id":1,"title":null},{"doc":null,"id":2,"title":"x,y"}]`},
+		{format: "ndjson", want: "{\"doc\":{\"a\":1},\"id\":1,\"title\":null}\n{\"doc\":null,\"id\":2,\"title\":\"x,y\"}\n"},
+		{format: "csv", want: "id,title,doc\n1,,\"{\"\"a\"\":1}\"\n2,\"x,y\",\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			w := newRowsWriter(tt.format, columns)
+			for _, row := range rows {
+				require.NoError(t, w.write(row))
+			}
+			data, err := w.close()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(data))
+		})
+	}
+	data, err := newRowsWriter("json", columns).close()
+	require.NoError(t, err)
+	require.Nil(t, data)
+}
+
+func TestCursor(t *testing.T) {
+	stmt := Statement{SQL: "SELECT * FROM post ORDER BY id WHERE id > $1", Args: []interface{}{1}}
+	offset, err := decodeCursor(encodeCursor(20, stmt), stmt)
+	require.NoError(t, err)
+	require.Equal(t, 20, offset)
+	_, err = decodeCursor(encodeCursor(20, stmt), Statement{SQL: stmt.SQL, Args: []interface{}{2}})
+	require.Error(t, err)
+	_, err = decodeCursor("not-a-cursor", stmt)
+	require.Error(t, err)
+}
+
+func TestDialect_paginate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		sql     string
+		want    string
+	}{
+		{name: "limit offset", dialect: Postgres, sql: "SELECT * FROM post ORDER BY id;", want: "SELECT * FROM post ORDER BY id\nLIMIT 3 OFFSET 4"},
+		{name: "trailing comment", dialect: SQLite, sql: "SELECT * FROM post -- all posts", want: "SELECT * FROM post -- all posts\nLIMIT 3 OFFSET 4"},
+		{name: "nested limit", dialect: MySQL, sql: "SELECT * FROM post WHERE id IN (SELECT id FROM tag LIMIT 1) AND title <> 'limit'", want: "SELECT * FROM post WHERE id IN (SELECT id FROM tag LIMIT 1) AND title <> 'limit'\nLIMIT 3 OFFSET 4"},
+		{name: "query limit", dialect: Postgres, sql: "SELECT * FROM post ORDER BY id LIMIT 10", want: "SELECT * FROM (\nSELECT * FROM post ORDER BY id LIMIT 10\n) AS kubemq_page LIMIT 3 OFFSET 4"},
+		{name: "offset fetch", dialect: MSSQL, sql: "SELECT * FROM [order] ORDER BY id", want: "SELECT * FROM [order] ORDER BY id\nOFFSET 4 ROWS FETCH NEXT 3 ROWS ONLY"},
+		{name: "offset fetch without order", dialect: SQLServer, sql: "SELECT * FROM post", want: "SELECT * FROM (\nSELECT * FROM post\n) AS kubemq_page ORDER BY (SELECT NULL) OFFSET 4 ROWS FETCH NEXT 3 ROWS ONLY"},
+		{name: "offset fetch with top", dialect: MSSQL, sql: "SELECT TOP 10 * FROM post ORDER BY id", want: "SELECT * FROM (\nSELECT TOP 10 * FROM post ORDER BY id\n) AS kubemq_page ORDER BY (SELECT NULL) OFFSET 4 ROWS FETCH NEXT 3 ROWS ONLY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.dialect.paginate(tt.sql, 4, 3))
+		})
+	}
+}
+
+func TestDialect_PageQuery(t *testing.T) {
+	stmt := Statement{SQL: "SELECT * FROM post ORDER BY id", Args: []interface{}{1}}
+	page, err := Postgres.PageQuery(stmt, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, stmt, page)
+	page, err = Postgres.PageQuery(stmt, QueryOptions{MaxRows: 2, Cursor: encodeCursor(4, stmt)})
+	require.NoError(t, err)
+	require.Equal(t, Statement{SQL: "SELECT * FROM post ORDER BY id\nLIMIT 3 OFFSET 4", Args: stmt.Args}, page)
+	_, err = Postgres.PageQuery(stmt, QueryOptions{Cursor: encodeCursor(4, stmt)})
+	require.Error(t, err)
+}
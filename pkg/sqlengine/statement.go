@@ -0,0 +1,317 @@
+package sqlengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Statement is a single sql statement, executed once with Args or, when Batch is set, once per Batch entry through a prepared statement.
+// In a transaction, a statement with a Savepoint is wrapped by a savepoint and its failure rolls back to it instead of failing the transaction
+type Statement struct {
+	SQL       string
+	Args      []interface{}
+	Batch     [][]interface{}
+	Savepoint string
+}
+
+type statementRequest struct {
+	SQL       string            `json:"sql"`
+	Params    json.RawMessage   `json:"params"`
+	Batch     []json.RawMessage `json:"batch"`
+	Savepoint string            `json:"savepoint"`
+}
+
+var savepointName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type statementsRequest struct {
+	Statements []statementRequest `json:"statements"`
+}
+
+// IsStructured returns true when the request data is a json statements request rather than raw sql text
+func IsStructured(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && (data[0] == '{' || data[0] == '[')
+}
+
+// ParseStatements parses the request data to statements, raw sql text is split on the semicolons found outside of literals and comments,
+// json data is either a statement object, an array of statement objects or an object with a statements array
+func ParseStatements(data []byte, d Dialect) ([]Statement, error) {
+	if !IsStructured(data) {
+		var stmts []Statement
+		for _, stmt := range d.Split(string(data)) {
+			stmts = append(stmts, Statement{SQL: stmt})
+		}
+		return stmts, nil
+	}
+	requests, err := decodeStatements(data)
+	if err != nil {
+		return nil, err
+	}
+	var stmts []Statement
+	for i, req := range requests {
+		stmt, err := req.bind(d)
+		if err != nil {
+			return nil, fmt.Errorf("error on statement %d, %w", i, err)
+		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts, nil
+}
+
+// ParseQuery parses the request data to a single statement, raw sql text is used as is
+func ParseQuery(data []byte, d Dialect) (Statement, error) {
+	if !IsStructured(data) {
+		return Statement{SQL: strings.TrimSpace(string(data))}, nil
+	}
+	stmts, err := ParseStatements(data, d)
+	if err != nil {
+		return Statement{}, err
+	}
+	if len(stmts) != 1 {
+		return Statement{}, fmt.Errorf("query request must have exactly one statement")
+	}
+	if stmts[0].Batch != nil {
+		return Statement{}, fmt.Errorf("query request does not support batch parameters")
+	}
+	return stmts[0], nil
+}
+
+func decodeStatements(data []byte) ([]statementRequest, error) {
+	data = bytes.TrimSpace(data)
+	if data[0] == '[' {
+		var requests []statementRequest
+		if err := json.Unmarshal(data, &requests); err != nil {
+			return nil, fmt.Errorf("invalid statements request, %w", err)
+		}
+		return requests, nil
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("invalid statements request, %w", err)
+	}
+	if _, ok := fields["statements"]; ok {
+		req := statementsRequest{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("invalid statements request, %w", err)
+		}
+		return req.Statements, nil
+	}
+	req := statementRequest{}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("invalid statements request, %w", err)
+	}
+	return []statementRequest{req}, nil
+}
+
+func (r statementRequest) bind(d Dialect) (Statement, error) {
+	if strings.TrimSpace(r.SQL) == "" {
+		return Statement{}, fmt.Errorf("missing sql")
+	}
+	if len(r.Params) > 0 && r.Batch != nil {
+		return Statement{}, fmt.Errorf("params and batch cannot be set together")
+	}
+	if r.Savepoint != "" && !savepointName.MatchString(r.Savepoint) {
+		return Statement{}, fmt.Errorf("invalid savepoint name %q", r.Savepoint)
+	}
+	if r.Batch == nil {
+		stmt, args, err := bindParams(r.SQL, r.Params, d)
+		if err != nil {
+			return Statement{}, err
+		}
+		return Statement{SQL: stmt, Args: args, Savepoint: r.Savepoint}, nil
+	}
+	if len(r.Batch) == 0 {
+		return Statement{}, fmt.Errorf("empty batch")
+	}
+	stmt := Statement{SQL: r.SQL, Batch: make([][]interface{}, 0, len(r.Batch)), Savepoint: r.Savepoint}
+	for i, params := range r.Batch {
+		sqlText, args, err := bindParams(r.SQL, params, d)
+		if err != nil {
+			return Statement{}, fmt.Errorf("batch entry %d, %w", i, err)
+		}
+		stmt.SQL = sqlText
+		stmt.Batch = append(stmt.Batch, args)
+	}
+	return stmt, nil
+}
+
+// bindParams returns the positional args of a params array, or rewrites the :name references of a params object to the dialect placeholders
+func bindParams(sqlText string, params json.RawMessage, d Dialect) (string, []interface{}, error) {
+	params = bytes.TrimSpace(params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return sqlText, nil, nil
+	}
+	value, err := decodeValue(params)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid params, %w", err)
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		args := make([]interface{}, len(v))
+		for i := range v {
+			args[i] = toArg(v[i])
+		}
+		return sqlText, args, nil
+	case map[string]interface{}:
+		return BindNamed(sqlText, v, d)
+	default:
+		return "", nil, fmt.Errorf("params must be an array or an object")
+	}
+}
+
+// BindNamed replaces the :name references found outside of literals and comments with the dialect placeholders and returns the matching args
+func BindNamed(sqlText string, params map[string]interface{}, d Dialect) (string, []interface{}, error) {
+	var args []interface{}
+	var sb strings.Builder
+	last := 0
+	for i := 0; i < len(sqlText); {
+		if next, ok := d.skip(sqlText, i); ok {
+			i = next
+			continue
+		}
+		if sqlText[i] != ':' || i+1 >= len(sqlText) || !isIdentStart(sqlText[i+1]) || (i > 0 && sqlText[i-1] == ':') {
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(sqlText) && isIdentPart(sqlText[end]) {
+			end++
+		}
+		name := sqlText[i+1 : end]
+		value, ok := params[name]
+		if !ok {
+			return "", nil, fmt.Errorf("missing value for parameter %s", name)
+		}
+		args = append(args, toArg(value))
+		sb.WriteString(sqlText[last:i])
+		sb.WriteString(d.Bind(len(args)))
+		last = end
+		i = end
+	}
+	sb.WriteString(sqlText[last:])
+	return sb.String(), args, nil
+}
+
+// Split splits sql text on the semicolons found outside of literals, quoted identifiers and comments, empty statements are dropped
+func (d Dialect) Split(sqlText string) []string {
+	var stmts []string
+	start := 0
+	add := func(stmt string) {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	for i := 0; i < len(sqlText); {
+		if next, ok := d.skip(sqlText, i); ok {
+			i = next
+			continue
+		}
+		if sqlText[i] == ';' {
+			add(sqlText[start:i])
+			start = i + 1
+		}
+		i++
+	}
+	add(sqlText[start:])
+	return stmts
+}
+
+// skip returns the position after the literal, quoted identifier or comment starting at i
+func (d Dialect) skip(s string, i int) (int, bool) {
+	switch c := s[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		return d.skipQuoted(s, i, c), true
+	case c == '[' && d.bracketIdentifiers:
+		return skipUntil(s, i+1, "]"), true
+	case c == '-' && strings.HasPrefix(s[i:], "--"):
+		return skipUntil(s, i+2, "\n"), true
+	case c == '/' && strings.HasPrefix(s[i:], "/*"):
+		return skipUntil(s, i+2, "*/"), true
+	case c == '$' && d.dollarQuotes && (i == 0 || !isIdentPart(s[i-1])):
+		if tag, ok := dollarTag(s[i:]); ok {
+			return skipUntil(s, i+len(tag), tag), true
+		}
+	}
+	return i, false
+}
+
+func (d Dialect) skipQuoted(s string, i int, quote byte) int {
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '\\' && d.backslashEscapes && quote != '`':
+			j++
+		case s[j] == quote:
+			// a doubled quote is an escaped quote
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+func skipUntil(s string, from int, end string) int {
+	if from > len(s) {
+		return len(s)
+	}
+	idx := strings.Index(s[from:], end)
+	if idx < 0 {
+		return len(s)
+	}
+	return from + idx + len(end)
+}
+
+// dollarTag returns the $tag$ opening a dollar quoted string, $1 style placeholders are not tags
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		if s[j] == '$' {
+			return s[:j+1], true
+		}
+		if !(isIdentStart(s[j]) || (j > 1 && isIdentPart(s[j]))) {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+func decodeValue(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// toArg converts a decoded json value to a driver argument, numbers keep their integer precision and objects and arrays are passed as json text
+func toArg(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return v
+	}
+}
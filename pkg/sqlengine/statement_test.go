@@ -0,0 +1,198 @@
+package sqlengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialect_Split(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		sql     string
+		want    []string
+	}{
+		{
+			name:    "plain statements",
+			dialect: Postgres,
+			sql:     "DELETE FROM a; DELETE FROM b;\n",
+			want:    []string{"DELETE FROM a", "DELETE FROM b"},
+		},
+		{
+			name:    "semicolon in literal",
+			dialect: Postgres,
+			sql:     "INSERT INTO a VALUES ('x;y', 'it''s;');SELECT 1",
+			want:    []string{"INSERT INTO a VALUES ('x;y', 'it''s;')", "SELECT 1"},
+		},
+		{
+			name:    "semicolon in comments and quoted identifier",
+			dialect: Postgres,
+			sql:     "SELECT \"a;b\" FROM t -- c;d\n;/* e;f */SELECT 2",
+			want:    []string{"SELECT \"a;b\" FROM t -- c;d", "/* e;f */SELECT 2"},
+		},
+		{
+			name:    "dollar quoted body",
+			dialect: Postgres,
+			sql:     "CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql; SELECT $1",
+			want:    []string{"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql", "SELECT $1"},
+		},
+		{
+			name:    "mysql backslash escape",
+			dialect: MySQL,
+			sql:     "INSERT INTO a VALUES ('x\\';y');SELECT `c;d` FROM a",
+			want:    []string{"INSERT INTO a VALUES ('x\\';y')", "SELECT `c;d` FROM a"},
+		},
+		{
+			name:    "mssql bracket identifier",
+			dialect: MSSQL,
+			sql:     "SELECT [a;b] FROM t;SELECT 2",
+			want:    []string{"SELECT [a;b] FROM t", "SELECT 2"},
+		},
+		{
+			name:    "empty",
+			dialect: Postgres,
+			sql:     " ; ;",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.dialect.Split(tt.sql))
+		})
+	}
+}
+
+func TestBindNamed(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		sql      string
+		params   map[string]interface{}
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "postgres",
+			dialect:  Postgres,
+			sql:      "UPDATE t SET a=:a, b=':b', c=now()::text WHERE id=:id OR parent=:id",
+			params:   map[string]interface{}{"a": "x", "id": 1},
+			wantSQL:  "UPDATE t SET a=$1, b=':b', c=now()::text WHERE id=$2 OR parent=$3",
+			wantArgs: []interface{}{"x", 1, 1},
+		},
+		{
+			name:     "mysql",
+			dialect:  MySQL,
+			sql:      "SELECT * FROM t WHERE id=:id",
+			params:   map[string]interface{}{"id": 1},
+			wantSQL:  "SELECT * FROM t WHERE id=?",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "sqlserver",
+			dialect:  SQLServer,
+			sql:      "SELECT * FROM t WHERE id=:id AND name=:name",
+			params:   map[string]interface{}{"id": 1, "name": "n"},
+			wantSQL:  "SELECT * FROM t WHERE id=@p1 AND name=@p2",
+			wantArgs: []interface{}{1, "n"},
+		},
+		{
+			name:    "missing param",
+			dialect: Postgres,
+			sql:     "SELECT * FROM t WHERE id=:id",
+			params:  map[string]interface{}{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := BindNamed(tt.sql, tt.params, tt.dialect)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSQL, gotSQL)
+			require.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestParseStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Statement
+		wantErr bool
+	}{
+		{
+			name: "raw sql",
+			data: "DELETE FROM a;DELETE FROM b",
+			want: []Statement{{SQL: "DELETE FROM a"}, {SQL: "DELETE FROM b"}},
+		},
+		{
+			name: "statements object",
+			data: `{"statements":[{"sql":"INSERT INTO a VALUES ($1,$2)","params":[1,"x"]},{"sql":"UPDATE a SET b=:b","params":{"b":{"k":1.5}}}]}`,
+			want: []Statement{
+				{SQL: "INSERT INTO a VALUES ($1,$2)", Args: []interface{}{int64(1), "x"}},
+				{SQL: "UPDATE a SET b=$1", Args: []interface{}{`{"k":1.5}`}},
+			},
+		},
+		{
+			name: "single statement with batch",
+			data: `{"sql":"INSERT INTO a VALUES (:id)","batch":[{"id":1},{"id":2.5}]}`,
+			want: []Statement{
+				{SQL: "INSERT INTO a VALUES ($1)", Batch: [][]interface{}{{int64(1)}, {2.5}}},
+			},
+		},
+		{
+			name: "statements array",
+			data: `[{"sql":"SELECT 1"},{"sql":"SELECT $1","params":[null]}]`,
+			want: []Statement{
+				{SQL: "SELECT 1"},
+				{SQL: "SELECT $1", Args: []interface{}{nil}},
+			},
+		},
+		{
+			name:    "missing sql",
+			data:    `{"statements":[{"params":[1]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "params and batch",
+			data:    `{"sql":"SELECT $1","params":[1],"batch":[[1]]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			data:    `{"sql":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStatements([]byte(tt.data), Postgres)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseBulkInsert(t *testing.T) {
+	got, err := ParseBulkInsert([]byte(`{"table":"post","columns":["id","title"],"rows":[[1,"a"],{"title":"b","id":2}]}`))
+	require.NoError(t, err)
+	require.Equal(t, BulkInsertRequest{
+		Table:   "post",
+		Columns: []string{"id", "title"},
+		Rows:    [][]interface{}{{int64(1), "a"}, {int64(2), "b"}},
+	}, got)
+	_, err = ParseBulkInsert([]byte(`{"table":"post","columns":["id","title"],"rows":[[1]]}`))
+	require.Error(t, err)
+	_, err = ParseBulkInsert([]byte(`{"columns":["id"],"rows":[[1]]}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/kubemq-io/kubemq-targets"
+
+var (
+	propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	noopTracer = trace.NewNoopTracerProvider()
+)
+
+// Provider holds the tracer provider installed by Start, a disabled configuration returns a Provider with no exporter
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// Start installs a global OTLP tracer provider and W3C trace context propagator according to the tracing configuration
+func Start(ctx context.Context, cfg config.TracingConfig) (*Provider, error) {
+	if !cfg.Enabled {
+		otel.SetTracerProvider(noopTracer)
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+		return &Provider{}, nil
+	}
+	var opts []otlptracegrpc.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating otlp trace exporter, %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating tracing resource, %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+	return &Provider{tp: tp}, nil
+}
+
+// Stop flushes pending spans and shuts down the exporter
+func (p *Provider) Stop(ctx context.Context) error {
+	if p == nil || p.tp == nil {
+		return nil
+	}
+	return p.tp.Shutdown(ctx)
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Extract returns a context with the remote span context found in the carrier, if any
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+// Inject writes the span context of ctx into the carrier, nothing is written when tracing is disabled
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+}
+
+func StartSpan(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// StartReceive extracts the trace context from the KubeMQ message tags and starts a consumer span for the received message
+func StartReceive(ctx context.Context, kind, channel string, tags map[string]string) (context.Context, trace.Span) {
+	if tags != nil {
+		ctx = Extract(ctx, propagation.MapCarrier(tags))
+	}
+	return StartSpan(ctx, fmt.Sprintf("receive %s", channel), trace.SpanKindConsumer,
+		attribute.String("messaging.system", "kubemq"),
+		attribute.String("messaging.source.kind", kind),
+		attribute.String("messaging.source.name", channel),
+	)
+}
+
+// End records the error, if any, on the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
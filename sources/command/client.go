@@ -10,6 +10,7 @@ import (
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/middleware"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
 	"github.com/kubemq-io/kubemq-targets/types"
 )
@@ -96,7 +97,9 @@ func (c *Client) runClient(ctx context.Context, client *kubemq.Client) error {
 					cmdResponse := client.R().
 						SetRequestId(command.Id).
 						SetResponseTo(command.ResponseTo)
-					_, err := c.processCommand(ctx, command)
+					cmdCtx, span := tracing.StartReceive(ctx, "kubemq.command", c.opts.channel, command.Tags)
+					_, err := c.processCommand(cmdCtx, command)
+					tracing.End(span, err)
 					if err != nil {
 						cmdResponse.SetError(err)
 					}
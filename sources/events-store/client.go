@@ -9,7 +9,9 @@ import (
 	"github.com/kubemq-io/kubemq-go"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/middleware"
+	"github.com/kubemq-io/kubemq-targets/pkg/delivery"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
 	"github.com/kubemq-io/kubemq-targets/types"
 )
@@ -17,10 +19,11 @@ import (
 var errInvalidTarget = errors.New("invalid target received, cannot be nil")
 
 type Client struct {
-	opts    options
-	clients []*kubemq.Client
-	log     *logger.Logger
-	target  middleware.Middleware
+	opts        options
+	clients     []*kubemq.Client
+	log         *logger.Logger
+	processor   *delivery.Processor
+	bindingName string
 }
 
 func New() *Client {
@@ -41,6 +44,7 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, bindingName string,
 	if err != nil {
 		return err
 	}
+	c.bindingName = bindingName
 	for i := 0; i < c.opts.sources; i++ {
 		clientId := fmt.Sprintf("kubemq-targets_%s_%s", bindingName, c.opts.clientId)
 		if c.opts.sources > 1 {
@@ -66,8 +70,16 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, bindingName string,
 func (c *Client) Start(ctx context.Context, target middleware.Middleware) error {
 	if target == nil {
 		return errInvalidTarget
-	} else {
-		c.target = target
+	}
+	c.processor = &delivery.Processor{
+		Target:            target,
+		Log:               c.log,
+		Binding:           c.bindingName,
+		Channel:           c.opts.channel,
+		DeadLetterChannel: c.opts.deadLetterChannel,
+		MaxAttempts:       c.opts.maxReceiveCount,
+		RetryInterval:     c.opts.retryInterval,
+		DoNotParsePayload: c.opts.doNotParsePayload,
 	}
 	if c.opts.sources > 1 && c.opts.group == "" {
 		c.opts.group = uuid.New().String()
@@ -93,12 +105,17 @@ func (c *Client) runClient(ctx context.Context, client *kubemq.Client) error {
 			select {
 			case event := <-eventsCh:
 				go func(event *kubemq.EventStoreReceive) {
-					resp, err := c.processEventStore(ctx, event)
+					eventCtx, span := tracing.StartReceive(ctx, "kubemq.events-store", c.opts.channel, event.Tags)
+					resp, err := c.processor.Process(eventCtx, delivery.Message{Metadata: event.Metadata, Body: event.Body, Tags: event.Tags}, delivery.QueueSender(client))
+					tracing.End(span, err)
+					if ctx.Err() != nil {
+						return
+					}
 					if err != nil {
 						resp = types.NewResponse().SetError(err)
 					}
 					if c.opts.responseChannel != "" {
-						sendRes, errSend := client.SetEventStore(resp.ToEventStore()).SetChannel(c.opts.responseChannel).Send(ctx)
+						sendRes, errSend := client.SetEventStore(resp.ToEventStore().SetTags(delivery.TraceTags(eventCtx))).SetChannel(c.opts.responseChannel).Send(ctx)
 						if errSend != nil {
 							c.log.Errorf("error sending event response %s", errSend.Error())
 						} else {
@@ -121,24 +138,6 @@ func (c *Client) runClient(ctx context.Context, client *kubemq.Client) error {
 	return nil
 }
 
-func (c *Client) processEventStore(ctx context.Context, event *kubemq.EventStoreReceive) (*types.Response, error) {
-	var req *types.Request
-	var err error
-	if c.opts.doNotParsePayload {
-		req = types.NewRequest().SetData(event.Body)
-	} else {
-		req, err = types.ParseRequest(event.Body)
-		if err != nil {
-			return nil, fmt.Errorf("invalid request format, %w", err)
-		}
-	}
-	resp, err := c.target.Do(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
-}
-
 func (c *Client) Stop() error {
 	for _, client := range c.clients {
 		_ = client.Close()
@@ -34,6 +34,48 @@ func TestOptions_parseOptions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid options - dead letter channel",
+			cfg: config.Spec{
+				Name: "kubemq-rpc",
+				Kind: "",
+				Properties: map[string]string{
+					"address":             "localhost:50000",
+					"channel":             "some-channel",
+					"dead_letter_channel": "some-dead-letter-channel",
+					"max_receive_count":   "3",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid options - bad retry interval seconds",
+			cfg: config.Spec{
+				Name: "kubemq-rpc",
+				Kind: "",
+				Properties: map[string]string{
+					"address":                "localhost:50000",
+					"channel":                "some-channel",
+					"dead_letter_channel":    "some-dead-letter-channel",
+					"max_receive_count":      "3",
+					"retry_interval_seconds": "-1",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - dead letter channel without max receive count",
+			cfg: config.Spec{
+				Name: "kubemq-rpc",
+				Kind: "",
+				Properties: map[string]string{
+					"address":             "localhost:50000",
+					"channel":             "some-channel",
+					"dead_letter_channel": "some-dead-letter-channel",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid options - no channel",
 			cfg: config.Spec{
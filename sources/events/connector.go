@@ -108,5 +108,34 @@ func Connector() *common.Connector {
 				SetDescription("Set auto reconnection max reconnects").
 				SetMust(false).
 				SetDefault("0"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("dead_letter_channel").
+				SetTitle("Dead Letter Channel").
+				SetDescription("Set Queue channel for requests which failed max receive count times").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("max_receive_count").
+				SetTitle("Max Receive Count").
+				SetDescription("Set how many attempts to process a request before moving it to dead letter channel").
+				SetMust(false).
+				SetDefault("0"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("retry_interval_seconds").
+				SetTitle("Retry Interval Seconds").
+				SetDescription("Set wait before the first retry of a failed request, doubled after each attempt").
+				SetMust(false).
+				SetDefault("1").
+				SetMin(0).
+				SetMax(3600),
 		)
 }
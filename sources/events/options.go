@@ -11,6 +11,7 @@ import (
 const (
 	defaultAutoReconnect = true
 	defaultSources       = 1
+	defaultRetryInterval = 1
 )
 
 type options struct {
@@ -26,6 +27,9 @@ type options struct {
 	maxReconnects            int
 	sources                  int
 	doNotParsePayload        bool
+	maxReceiveCount          int
+	deadLetterChannel        string
+	retryInterval            time.Duration
 }
 
 func parseOptions(cfg config.Spec) (options, error) {
@@ -57,5 +61,18 @@ func parseOptions(cfg config.Spec) (options, error) {
 	o.reconnectIntervalSeconds = time.Duration(interval) * time.Second
 	o.maxReconnects = cfg.Properties.ParseInt("max_reconnects", 0)
 	o.doNotParsePayload = cfg.Properties.ParseBool("do_not_parse_payload", false)
+	o.deadLetterChannel = cfg.Properties.ParseString("dead_letter_channel", "")
+	o.maxReceiveCount, err = cfg.Properties.ParseIntWithRange("max_receive_count", 0, 0, 1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max receive count value, %w", err)
+	}
+	if o.deadLetterChannel != "" && o.maxReceiveCount == 0 {
+		return options{}, fmt.Errorf("error parsing max receive count value, must be greater than 0 when dead letter channel is set")
+	}
+	retryInterval, err := cfg.Properties.ParseIntWithRange("retry_interval_seconds", defaultRetryInterval, 0, 3600)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing retry interval seconds value, %w", err)
+	}
+	o.retryInterval = time.Duration(retryInterval) * time.Second
 	return o, nil
 }
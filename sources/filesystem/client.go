@@ -0,0 +1,408 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kubemq-hub/builder/connector/common"
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/middleware"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+const checkInterval = 250 * time.Millisecond
+
+var errInvalidTarget = errors.New("invalid controller received, cannot be null")
+
+// fileState is the size and modification time a file had when it was last seen
+type fileState struct {
+	size    int64
+	modTime int64
+}
+
+// pendingFile is a file that changed and waits for the settle time to pass without further changes
+type pendingFile struct {
+	state      fileState
+	lastChange time.Time
+}
+
+type Client struct {
+	opts        options
+	log         *logger.Logger
+	target      middleware.Middleware
+	bindingName string
+	cancel      context.CancelFunc
+	done        chan struct{}
+	// pending and processed are owned by the run loop goroutine
+	pending   map[string]*pendingFile
+	processed map[string]fileState
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func (c *Client) Connector() *common.Connector {
+	return Connector()
+}
+
+func (c *Client) Init(ctx context.Context, cfg config.Spec, bindingName string, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger(cfg.Kind)
+	}
+	var err error
+	c.opts, err = parseOptions(cfg)
+	if err != nil {
+		return err
+	}
+	c.bindingName = bindingName
+	c.pending = map[string]*pendingFile{}
+	c.processed = map[string]fileState{}
+	return nil
+}
+
+func (c *Client) Start(ctx context.Context, target middleware.Middleware) error {
+	if target == nil {
+		return errInvalidTarget
+	} else {
+		c.target = target
+	}
+	var watcher *fsnotify.Watcher
+	if !c.opts.usePolling {
+		var err error
+		watcher, err = fsnotify.NewWatcher()
+		if err != nil {
+			c.log.Warnf("error creating file system watcher, falling back to polling, %s", err.Error())
+			watcher = nil
+		}
+	}
+	if watcher != nil {
+		if err := c.addWatches(watcher, c.opts.basePath); err != nil {
+			c.log.Warnf("error watching %s, falling back to polling, %s", c.opts.basePath, err.Error())
+			_ = watcher.Close()
+			watcher = nil
+		}
+	}
+	if !c.opts.includeExisting {
+		c.scan(time.Now(), true)
+	}
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	go c.run(ctx, watcher)
+	return nil
+}
+
+// run owns the watch state, it applies the watcher events, rescans the base path every poll interval and sends the settled files
+func (c *Client) run(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer close(c.done)
+	var events chan fsnotify.Event
+	var watchErrors chan error
+	if watcher != nil {
+		defer func() {
+			_ = watcher.Close()
+		}()
+		events = watcher.Events
+		watchErrors = watcher.Errors
+	}
+	c.scan(time.Now(), false)
+	pollTicker := time.NewTicker(c.opts.pollInterval)
+	defer pollTicker.Stop()
+	checkTicker := time.NewTicker(checkInterval)
+	defer checkTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			c.onEvent(watcher, event)
+		case err, ok := <-watchErrors:
+			if !ok {
+				watchErrors = nil
+				continue
+			}
+			c.log.Errorf("file system watcher error, %s", err.Error())
+		case <-pollTicker.C:
+			c.scan(time.Now(), false)
+		case <-checkTicker.C:
+			c.processSettled(ctx)
+		}
+	}
+}
+
+func (c *Client) addWatches(watcher *fsnotify.Watcher, root string) error {
+	if !c.opts.recursive {
+		return watcher.Add(root)
+	}
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && c.skipDir(path) {
+			return fs.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+func (c *Client) onEvent(watcher *fsnotify.Watcher, event fsnotify.Event) {
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		delete(c.pending, event.Name)
+		delete(c.processed, event.Name)
+		return
+	}
+	info, err := os.Stat(event.Name)
+	if err != nil {
+		return
+	}
+	if info.IsDir() {
+		if c.opts.recursive && event.Has(fsnotify.Create) && !c.skipDir(event.Name) {
+			if err := c.addWatches(watcher, event.Name); err != nil {
+				c.log.Errorf("error watching %s, %s", event.Name, err.Error())
+			}
+			// files may have been created before the watch was added
+			c.scan(time.Now(), false)
+		}
+		return
+	}
+	if c.match(event.Name, info) {
+		c.touch(event.Name, info, time.Now())
+	}
+}
+
+// scan walks the base path and records the changed files as pending, with markProcessed the current files are recorded as processed instead
+func (c *Client) scan(now time.Time, markProcessed bool) {
+	seen := map[string]bool{}
+	err := filepath.WalkDir(c.opts.basePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == c.opts.basePath {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != c.opts.basePath && (!c.opts.recursive || c.skipDir(path)) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !c.match(path, info) {
+			return nil
+		}
+		seen[path] = true
+		if markProcessed {
+			c.processed[path] = fileState{size: info.Size(), modTime: info.ModTime().UnixNano()}
+			return nil
+		}
+		c.touch(path, info, now)
+		return nil
+	})
+	if err != nil {
+		c.log.Errorf("error scanning %s, %s", c.opts.basePath, err.Error())
+		return
+	}
+	// forget the deleted files so they are sent again when they are created again
+	for path := range c.processed {
+		if !seen[path] {
+			delete(c.processed, path)
+		}
+	}
+}
+
+// touch records a file as pending when it differs from the processed state, the settle time restarts on every change
+func (c *Client) touch(path string, info os.FileInfo, now time.Time) {
+	state := fileState{size: info.Size(), modTime: info.ModTime().UnixNano()}
+	if processed, ok := c.processed[path]; ok && processed == state {
+		return
+	}
+	if p, ok := c.pending[path]; ok {
+		if p.state != state {
+			p.state = state
+			p.lastChange = now
+		}
+		return
+	}
+	c.pending[path] = &pendingFile{state: state, lastChange: now}
+}
+
+func (c *Client) processSettled(ctx context.Context) {
+	now := time.Now()
+	for path, p := range c.pending {
+		if ctx.Err() != nil {
+			return
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			delete(c.pending, path)
+			continue
+		}
+		state := fileState{size: info.Size(), modTime: info.ModTime().UnixNano()}
+		if state != p.state {
+			p.state = state
+			p.lastChange = now
+			continue
+		}
+		if now.Sub(p.lastChange) < c.opts.settleTime {
+			continue
+		}
+		delete(c.pending, path)
+		if err := c.processFile(ctx, path, info); err != nil {
+			c.log.Errorf("error processing file %s, %s, file will be processed again", path, err.Error())
+		}
+	}
+}
+
+// processFile sends the file request to the target and applies the processed action once the target succeeds
+func (c *Client) processFile(ctx context.Context, path string, info os.FileInfo) error {
+	_, created := c.processed[path]
+	created = !created
+	req, err := c.newRequest(path, info, created)
+	if err != nil {
+		return err
+	}
+	reqCtx, span := tracing.StartReceive(ctx, "storage.filesystem", c.opts.basePath, nil)
+	resp, err := c.target.Do(reqCtx, req)
+	if err == nil && resp != nil && resp.IsError {
+		err = errors.New(resp.Error)
+	}
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+	switch c.opts.processedAction {
+	case "delete":
+		delete(c.processed, path)
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("error deleting processed file, %w", err)
+		}
+	case "move":
+		delete(c.processed, path)
+		relPath, _ := filepath.Rel(c.opts.basePath, path)
+		destination := filepath.Join(c.opts.moveToPath, relPath)
+		if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+			return fmt.Errorf("error moving processed file, %w", err)
+		}
+		if err := os.Rename(path, destination); err != nil {
+			return fmt.Errorf("error moving processed file, %w", err)
+		}
+	default:
+		c.processed[path] = fileState{size: info.Size(), modTime: info.ModTime().UnixNano()}
+	}
+	c.log.Infof("processed file %s successfully", path)
+	return nil
+}
+
+func (c *Client) newRequest(path string, info os.FileInfo, created bool) (*types.Request, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	h := sha256.New()
+	var w io.Writer = h
+	var buf *bytes.Buffer
+	sendContent := c.opts.sendContent && (c.opts.maxFileSize == 0 || info.Size() <= c.opts.maxFileSize)
+	if sendContent {
+		buf = bytes.NewBuffer(make([]byte, 0, info.Size()))
+		w = io.MultiWriter(h, buf)
+	}
+	size, err := io.Copy(w, f)
+	if err != nil {
+		return nil, err
+	}
+	relPath, _ := filepath.Rel(c.opts.basePath, path)
+	dir := filepath.ToSlash(filepath.Dir(relPath))
+	if dir == "." {
+		dir = ""
+	}
+	event := "modified"
+	if created {
+		event = "created"
+	}
+	metadata := types.NewMetadata()
+	for key, value := range c.opts.metadata {
+		metadata.Set(key, value)
+	}
+	req := types.NewRequest().
+		SetMetadata(metadata).
+		SetMetadataKeyValue("event", event).
+		SetMetadataKeyValue("path", dir).
+		SetMetadataKeyValue("filename", filepath.Base(path)).
+		SetMetadataKeyValue("full_path", path).
+		SetMetadataKeyValue("size", strconv.FormatInt(size, 10)).
+		SetMetadataKeyValue("mod_time", info.ModTime().UTC().Format(time.RFC3339Nano)).
+		SetMetadataKeyValue("hash", hex.EncodeToString(h.Sum(nil))).
+		SetMetadataKeyValue("hash_algorithm", "sha256")
+	if sendContent {
+		req.SetData(buf.Bytes())
+	} else if c.opts.sendContent {
+		req.SetMetadataKeyValue("content_omitted", "true")
+	}
+	return req, nil
+}
+
+// match returns true for regular files matching the pattern, hidden files are skipped unless include_hidden is set
+func (c *Client) match(path string, info os.FileInfo) bool {
+	if !info.Mode().IsRegular() {
+		return false
+	}
+	name := filepath.Base(path)
+	if !c.opts.includeHidden && strings.HasPrefix(name, ".") {
+		return false
+	}
+	if c.opts.moveToPath != "" && isWithin(c.opts.moveToPath, path) {
+		return false
+	}
+	if c.opts.pattern == defaultPattern {
+		return true
+	}
+	matched, _ := filepath.Match(c.opts.pattern, name)
+	return matched
+}
+
+func (c *Client) skipDir(path string) bool {
+	if !c.opts.includeHidden && strings.HasPrefix(filepath.Base(path), ".") {
+		return true
+	}
+	return c.opts.moveToPath != "" && isWithin(c.opts.moveToPath, path)
+}
+
+func isWithin(base, target string) bool {
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+func (c *Client) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+	return nil
+}
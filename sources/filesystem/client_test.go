@@ -0,0 +1,220 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTarget struct {
+	mu       sync.Mutex
+	fail     bool
+	requests []*types.Request
+}
+
+func (t *mockTarget) Do(ctx context.Context, request *types.Request) (*types.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.fail {
+		return types.NewResponse().SetError(os.ErrInvalid), nil
+	}
+	t.requests = append(t.requests, request)
+	return types.NewResponse().SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (t *mockTarget) setFail(fail bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.fail = fail
+}
+
+func (t *mockTarget) received() []*types.Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*types.Request(nil), t.requests...)
+}
+
+func (t *mockTarget) waitFor(tb testing.TB, count int) []*types.Request {
+	require.Eventually(tb, func() bool {
+		return len(t.received()) >= count
+	}, 10*time.Second, 50*time.Millisecond)
+	return t.received()
+}
+
+func startClient(t *testing.T, properties map[string]string) (*Client, *mockTarget) {
+	c := New()
+	err := c.Init(context.Background(), config.Spec{
+		Name:       "filesystem",
+		Kind:       "storage.filesystem",
+		Properties: properties,
+	}, "filesystem-binding", nil)
+	require.NoError(t, err)
+	target := &mockTarget{}
+	require.NoError(t, c.Start(context.Background(), target))
+	t.Cleanup(func() {
+		_ = c.Stop()
+	})
+	return c, target
+}
+
+func writeFile(t *testing.T, path, data string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
+func TestClient_Start_BadTarget(t *testing.T) {
+	c := New()
+	err := c.Init(context.Background(), config.Spec{
+		Name:       "filesystem",
+		Kind:       "storage.filesystem",
+		Properties: map[string]string{"base_path": t.TempDir()},
+	}, "filesystem-binding", nil)
+	require.NoError(t, err)
+	require.Error(t, c.Start(context.Background(), nil))
+}
+
+func TestClient_Watch(t *testing.T) {
+	for _, usePolling := range []string{"false", "true"} {
+		t.Run("use_polling="+usePolling, func(t *testing.T) {
+			basePath := t.TempDir()
+			writeFile(t, filepath.Join(basePath, "existing.csv"), "existing")
+			_, target := startClient(t, map[string]string{
+				"base_path":             basePath,
+				"pattern":               "*.csv",
+				"recursive":             "true",
+				"settle_time_seconds":   "0",
+				"poll_interval_seconds": "1",
+				"use_polling":           usePolling,
+				"metadata":              `{"method":"save"}`,
+			})
+			reqs := target.waitFor(t, 1)
+			require.Equal(t, "existing.csv", reqs[0].Metadata["filename"])
+			require.Equal(t, "created", reqs[0].Metadata["event"])
+
+			writeFile(t, filepath.Join(basePath, "sub", "data.csv"), "a,b,c")
+			writeFile(t, filepath.Join(basePath, "sub", "skipped.txt"), "skipped")
+			writeFile(t, filepath.Join(basePath, ".hidden.csv"), "hidden")
+			reqs = target.waitFor(t, 2)
+			req := reqs[1]
+			sum := sha256.Sum256([]byte("a,b,c"))
+			require.Equal(t, "save", req.Metadata["method"])
+			require.Equal(t, "sub", req.Metadata["path"])
+			require.Equal(t, "data.csv", req.Metadata["filename"])
+			require.Equal(t, "5", req.Metadata["size"])
+			require.Equal(t, hex.EncodeToString(sum[:]), req.Metadata["hash"])
+			require.Equal(t, []byte("a,b,c"), req.Data)
+
+			time.Sleep(1500 * time.Millisecond)
+			writeFile(t, filepath.Join(basePath, "sub", "data.csv"), "a,b,c,d")
+			reqs = target.waitFor(t, 3)
+			require.Equal(t, "modified", reqs[2].Metadata["event"])
+			require.Equal(t, []byte("a,b,c,d"), reqs[2].Data)
+
+			time.Sleep(1500 * time.Millisecond)
+			require.Len(t, target.received(), 3)
+		})
+	}
+}
+
+func TestClient_IncludeExisting(t *testing.T) {
+	basePath := t.TempDir()
+	writeFile(t, filepath.Join(basePath, "existing.txt"), "existing")
+	_, target := startClient(t, map[string]string{
+		"base_path":             basePath,
+		"settle_time_seconds":   "0",
+		"poll_interval_seconds": "1",
+		"include_existing":      "false",
+		"send_content":          "false",
+	})
+	writeFile(t, filepath.Join(basePath, "new.txt"), "new")
+	reqs := target.waitFor(t, 1)
+	require.Equal(t, "new.txt", reqs[0].Metadata["filename"])
+	require.Empty(t, reqs[0].Data)
+	time.Sleep(1500 * time.Millisecond)
+	require.Len(t, target.received(), 1)
+}
+
+func TestClient_SettleTime(t *testing.T) {
+	basePath := t.TempDir()
+	_, target := startClient(t, map[string]string{
+		"base_path":             basePath,
+		"settle_time_seconds":   "1",
+		"poll_interval_seconds": "1",
+	})
+	path := filepath.Join(basePath, "growing.log")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	for i := 0; i < 6; i++ {
+		_, err := f.WriteString("line\n")
+		require.NoError(t, err)
+		time.Sleep(300 * time.Millisecond)
+	}
+	require.NoError(t, f.Close())
+	require.Empty(t, target.received())
+	reqs := target.waitFor(t, 1)
+	require.Equal(t, "30", reqs[0].Metadata["size"])
+}
+
+func TestClient_ProcessedAction(t *testing.T) {
+	t.Run("move", func(t *testing.T) {
+		basePath := t.TempDir()
+		_, target := startClient(t, map[string]string{
+			"base_path":             basePath,
+			"recursive":             "true",
+			"settle_time_seconds":   "0",
+			"poll_interval_seconds": "1",
+			"processed_action":      "move",
+			"move_to_path":          "done",
+		})
+		writeFile(t, filepath.Join(basePath, "in", "file.json"), "{}")
+		target.waitFor(t, 1)
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(filepath.Join(basePath, "done", "in", "file.json"))
+			return err == nil
+		}, 5*time.Second, 50*time.Millisecond)
+		_, err := os.Stat(filepath.Join(basePath, "in", "file.json"))
+		require.True(t, os.IsNotExist(err))
+		time.Sleep(1500 * time.Millisecond)
+		require.Len(t, target.received(), 1)
+	})
+	t.Run("delete after target failure", func(t *testing.T) {
+		basePath := t.TempDir()
+		c := New()
+		err := c.Init(context.Background(), config.Spec{
+			Name: "filesystem",
+			Kind: "storage.filesystem",
+			Properties: map[string]string{
+				"base_path":             basePath,
+				"settle_time_seconds":   "0",
+				"poll_interval_seconds": "1",
+				"processed_action":      "delete",
+			},
+		}, "filesystem-binding", nil)
+		require.NoError(t, err)
+		target := &mockTarget{fail: true}
+		require.NoError(t, c.Start(context.Background(), target))
+		defer func() {
+			_ = c.Stop()
+		}()
+		path := filepath.Join(basePath, "file.txt")
+		writeFile(t, path, "data")
+		time.Sleep(1 * time.Second)
+		_, err = os.Stat(path)
+		require.NoError(t, err)
+		target.setFail(false)
+		target.waitFor(t, 1)
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(path)
+			return os.IsNotExist(err)
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
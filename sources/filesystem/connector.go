@@ -0,0 +1,131 @@
+package filesystem
+
+import (
+	"math"
+
+	"github.com/kubemq-hub/builder/connector/common"
+)
+
+func Connector() *common.Connector {
+	return common.NewConnector().
+		SetKind("storage.filesystem").
+		SetDescription("Local Filesystem Watch Source").
+		SetName("File System").
+		SetProvider("").
+		SetCategory("Storage").
+		SetTags("filesystem", "watch").
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("base_path").
+				SetTitle("Watch Path").
+				SetDescription("Set local file system directory to watch").
+				SetMust(true).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("pattern").
+				SetDescription("Set glob pattern of the file names to process").
+				SetMust(false).
+				SetDefault("*"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("recursive").
+				SetDescription("Set watching the sub directories").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("include_hidden").
+				SetDescription("Set processing hidden files and directories").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("include_existing").
+				SetDescription("Set processing the files found on start").
+				SetMust(false).
+				SetDefault("true"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("use_polling").
+				SetDescription("Set polling instead of file system notifications, for network file systems").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("settle_time_seconds").
+				SetTitle("Settle Time (Seconds)").
+				SetDescription("Set how long a file must stay unchanged before it is processed").
+				SetMust(false).
+				SetDefault("5").
+				SetMin(0).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("poll_interval_seconds").
+				SetTitle("Poll Interval (Seconds)").
+				SetDescription("Set directory rescan interval").
+				SetMust(false).
+				SetDefault("30").
+				SetMin(1).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("send_content").
+				SetDescription("Set sending the file content as the request data").
+				SetMust(false).
+				SetDefault("true"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("max_file_size").
+				SetDescription("Set max file size in bytes sent as request data, 0 for no limit").
+				SetMust(false).
+				SetDefault("104857600").
+				SetMin(0).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("processed_action").
+				SetDescription("Set what to do with files after the target succeeds").
+				SetMust(false).
+				SetOptions([]string{"none", "move", "delete"}).
+				SetDefault("none"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("move_to_path").
+				SetDescription("Set directory processed files are moved to, relative to the base path or absolute").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("metadata").
+				SetDescription("Set request metadata as json object, added to the file metadata").
+				SetMust(false).
+				SetDefault(""),
+		)
+}
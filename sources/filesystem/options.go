@@ -0,0 +1,107 @@
+package filesystem
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+)
+
+const (
+	defaultPattern      = "*"
+	defaultSettleTime   = 5
+	defaultPollInterval = 30
+	defaultMaxFileSize  = 100 * 1024 * 1024
+)
+
+var processedActionsMap = map[string]string{
+	"":       "none",
+	"none":   "none",
+	"move":   "move",
+	"delete": "delete",
+}
+
+type options struct {
+	basePath        string
+	pattern         string
+	recursive       bool
+	includeHidden   bool
+	includeExisting bool
+	usePolling      bool
+	settleTime      time.Duration
+	pollInterval    time.Duration
+	sendContent     bool
+	maxFileSize     int64
+	processedAction string
+	moveToPath      string
+	metadata        map[string]string
+}
+
+func parseOptions(cfg config.Spec) (options, error) {
+	o := options{}
+	var err error
+	basePath, err := cfg.Properties.MustParseString("base_path")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing base_path, %w", err)
+	}
+	o.basePath, err = filepath.Abs(basePath)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing base_path, %w", err)
+	}
+	info, err := os.Stat(o.basePath)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing base_path, %w", err)
+	}
+	if !info.IsDir() {
+		return options{}, fmt.Errorf("error parsing base_path, %s is not a directory", o.basePath)
+	}
+	o.pattern = cfg.Properties.ParseString("pattern", defaultPattern)
+	if _, err := filepath.Match(o.pattern, ""); err != nil {
+		return options{}, fmt.Errorf("error parsing pattern, %w", err)
+	}
+	o.recursive = cfg.Properties.ParseBool("recursive", false)
+	o.includeHidden = cfg.Properties.ParseBool("include_hidden", false)
+	o.includeExisting = cfg.Properties.ParseBool("include_existing", true)
+	o.usePolling = cfg.Properties.ParseBool("use_polling", false)
+	settleTime, err := cfg.Properties.ParseIntWithRange("settle_time_seconds", defaultSettleTime, 0, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing settle time seconds value, %w", err)
+	}
+	o.settleTime = time.Duration(settleTime) * time.Second
+	pollInterval, err := cfg.Properties.ParseIntWithRange("poll_interval_seconds", defaultPollInterval, 1, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing poll interval seconds value, %w", err)
+	}
+	o.pollInterval = time.Duration(pollInterval) * time.Second
+	o.sendContent = cfg.Properties.ParseBool("send_content", true)
+	maxFileSize, err := cfg.Properties.ParseIntWithRange("max_file_size", defaultMaxFileSize, 0, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max file size value, %w", err)
+	}
+	o.maxFileSize = int64(maxFileSize)
+	o.processedAction, err = cfg.Properties.ParseStringMap("processed_action", processedActionsMap)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing processed action value, %w", err)
+	}
+	if o.processedAction == "move" {
+		moveToPath, err := cfg.Properties.MustParseString("move_to_path")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing move to path value, %w", err)
+		}
+		if !filepath.IsAbs(moveToPath) {
+			moveToPath = filepath.Join(o.basePath, moveToPath)
+		}
+		o.moveToPath = filepath.Clean(moveToPath)
+		if o.moveToPath == o.basePath {
+			return options{}, fmt.Errorf("error parsing move to path value, move to path cannot be the base path")
+		}
+	}
+	o.metadata, err = cfg.Properties.MustParseJsonMap("metadata")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing metadata value, %w", err)
+	}
+	return o, nil
+}
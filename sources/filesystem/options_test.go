@@ -0,0 +1,112 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_parseOptions(t *testing.T) {
+	basePath := t.TempDir()
+	tests := []struct {
+		name       string
+		properties map[string]string
+		wantErr    bool
+	}{
+		{
+			name: "valid options",
+			properties: map[string]string{
+				"base_path": basePath,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid options - move",
+			properties: map[string]string{
+				"base_path":           basePath,
+				"pattern":             "*.csv",
+				"recursive":           "true",
+				"settle_time_seconds": "0",
+				"processed_action":    "move",
+				"move_to_path":        "processed",
+			},
+			wantErr: false,
+		},
+		{
+			name:       "invalid options - no base path",
+			properties: map[string]string{},
+			wantErr:    true,
+		},
+		{
+			name: "invalid options - base path does not exist",
+			properties: map[string]string{
+				"base_path": basePath + "/not-exists",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad pattern",
+			properties: map[string]string{
+				"base_path": basePath,
+				"pattern":   "[",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad processed action",
+			properties: map[string]string{
+				"base_path":        basePath,
+				"processed_action": "archive",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - move without path",
+			properties: map[string]string{
+				"base_path":        basePath,
+				"processed_action": "move",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - move to base path",
+			properties: map[string]string{
+				"base_path":        basePath,
+				"processed_action": "move",
+				"move_to_path":     ".",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad poll interval",
+			properties: map[string]string{
+				"base_path":             basePath,
+				"poll_interval_seconds": "0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad metadata",
+			properties: map[string]string{
+				"base_path": basePath,
+				"metadata":  "bad-json",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(config.Spec{
+				Name:       "filesystem",
+				Kind:       "storage.filesystem",
+				Properties: tt.properties,
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
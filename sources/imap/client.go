@@ -0,0 +1,330 @@
+package imap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	"github.com/kubemq-hub/builder/connector/common"
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/middleware"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
+)
+
+var errInvalidTarget = errors.New("invalid controller received, cannot be null")
+
+type Client struct {
+	opts        options
+	log         *logger.Logger
+	target      middleware.Middleware
+	bindingName string
+	cancel      context.CancelFunc
+	// failures is owned by the run loop goroutine
+	failures *failures
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func (c *Client) Connector() *common.Connector {
+	return Connector()
+}
+
+func (c *Client) Init(ctx context.Context, cfg config.Spec, bindingName string, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger(cfg.Kind)
+	}
+	var err error
+	c.opts, err = parseOptions(cfg)
+	if err != nil {
+		return err
+	}
+	c.bindingName = bindingName
+	c.failures = newFailures(c.opts.maxRetries)
+	imapClient, err := c.connect()
+	if err != nil {
+		return err
+	}
+	_ = imapClient.Logout()
+	return nil
+}
+
+func (c *Client) connect() (*client.Client, error) {
+	address := fmt.Sprintf("%s:%d", c.opts.host, c.opts.port)
+	tlsConfig := &tls.Config{
+		ServerName: c.opts.host,
+		/* #nosec */
+		InsecureSkipVerify: c.opts.insecureSkipVerify,
+	}
+	var imapClient *client.Client
+	var err error
+	if c.opts.useTLS {
+		imapClient, err = client.DialTLS(address, tlsConfig)
+	} else {
+		imapClient, err = client.Dial(address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to imap server at %s, %w", address, err)
+	}
+	if c.opts.startTLS {
+		if err := imapClient.StartTLS(tlsConfig); err != nil {
+			_ = imapClient.Logout()
+			return nil, fmt.Errorf("error starting tls, %w", err)
+		}
+	}
+	if err := imapClient.Login(c.opts.username, c.opts.password); err != nil {
+		_ = imapClient.Logout()
+		return nil, fmt.Errorf("error login to imap server, %w", err)
+	}
+	return imapClient, nil
+}
+
+func (c *Client) Start(ctx context.Context, target middleware.Middleware) error {
+	if target == nil {
+		return errInvalidTarget
+	} else {
+		c.target = target
+	}
+	ctx, c.cancel = context.WithCancel(ctx)
+	go c.run(ctx)
+	return nil
+}
+
+func (c *Client) run(ctx context.Context) {
+	for {
+		err := c.watch(ctx)
+		if err != nil {
+			c.log.Errorf("error watching mailbox folder %s, %s", c.opts.folder, err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.opts.pollInterval):
+		}
+	}
+}
+
+// watch processes the folder messages and waits for new ones with IDLE, falling back to polling when the server does not support IDLE
+func (c *Client) watch(ctx context.Context) error {
+	imapClient, err := c.connect()
+	if err != nil {
+		return err
+	}
+	updates := make(chan client.Update, 100)
+	notify := make(chan struct{}, 1)
+	closed := make(chan struct{})
+	defer func() {
+		_ = imapClient.Logout()
+		close(closed)
+	}()
+	go func() {
+		for {
+			select {
+			case update := <-updates:
+				if _, ok := update.(*client.MailboxUpdate); ok {
+					select {
+					case notify <- struct{}{}:
+					default:
+					}
+				}
+			case <-closed:
+				return
+			}
+		}
+	}()
+	imapClient.Updates = updates
+	status, err := imapClient.Select(c.opts.folder, false)
+	if err != nil {
+		return fmt.Errorf("error selecting folder, %w", err)
+	}
+	c.failures.setUidValidity(status.UidValidity)
+	for {
+		processed, err := c.processFolder(ctx, imapClient)
+		if err != nil {
+			return err
+		}
+		if processed == c.opts.batchSize && ctx.Err() == nil {
+			continue
+		}
+		if err := c.idle(ctx, imapClient, notify); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// idle returns when new messages arrive to the folder, the poll interval passed or ctx is done
+func (c *Client) idle(ctx context.Context, imapClient *client.Client, notify chan struct{}) error {
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- imapClient.Idle(stop, &client.IdleOptions{
+			PollInterval: c.opts.pollInterval,
+		})
+	}()
+	timer := time.NewTimer(c.opts.pollInterval)
+	defer timer.Stop()
+	select {
+	case <-notify:
+	case <-timer.C:
+	case <-ctx.Done():
+	case err := <-done:
+		return err
+	}
+	close(stop)
+	return <-done
+}
+
+func (c *Client) search(imapClient *client.Client) ([]uint32, error) {
+	criteria := imap.NewSearchCriteria()
+	if c.opts.processedAction == "flag" {
+		criteria.WithoutFlags = []string{c.opts.processedFlag, c.opts.failedFlag}
+	} else {
+		criteria.WithoutFlags = []string{imap.DeletedFlag, c.opts.failedFlag}
+	}
+	uids, err := imapClient.UidSearch(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("error searching messages, %w", err)
+	}
+	// failed messages are left out before the batch is cut so they cannot block newer messages
+	uids = c.failures.filter(uids)
+	if len(uids) > c.opts.batchSize {
+		uids = uids[:c.opts.batchSize]
+	}
+	return uids, nil
+}
+
+// fetch returns the parsed messages and the uids of the messages which could not be parsed
+func (c *Client) fetch(imapClient *client.Client, uids []uint32) ([]*message, map[uint32]error, error) {
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uids...)
+	section := &imap.BodySectionName{Peek: true}
+	items := []imap.FetchItem{imap.FetchUid, section.FetchItem()}
+	ch := make(chan *imap.Message, len(uids))
+	if err := imapClient.UidFetch(seqSet, items, ch); err != nil {
+		return nil, nil, fmt.Errorf("error fetching messages, %w", err)
+	}
+	var messages []*message
+	invalid := map[uint32]error{}
+	for msg := range ch {
+		body := msg.GetBody(section)
+		if body == nil {
+			invalid[msg.Uid] = errors.New("message body is missing")
+			continue
+		}
+		m, err := parseMessage(msg.Uid, body)
+		if err != nil {
+			invalid[msg.Uid] = fmt.Errorf("error parsing message, %w", err)
+			continue
+		}
+		messages = append(messages, m)
+	}
+	return messages, invalid, nil
+}
+
+// processFolder processes a batch of messages and returns how many of them were processed successfully
+func (c *Client) processFolder(ctx context.Context, imapClient *client.Client) (int, error) {
+	uids, err := c.search(imapClient)
+	if err != nil || len(uids) == 0 {
+		return 0, err
+	}
+	messages, invalid, err := c.fetch(imapClient, uids)
+	if err != nil {
+		return 0, err
+	}
+	for uid, err := range invalid {
+		c.fail(imapClient, uid, err)
+	}
+	processed := 0
+	for _, m := range messages {
+		if ctx.Err() != nil {
+			return processed, nil
+		}
+		if err := c.processMessage(ctx, m); err != nil {
+			c.fail(imapClient, m.uid, err)
+			continue
+		}
+		if err := c.markProcessed(imapClient, m.uid); err != nil {
+			return processed, err
+		}
+		c.failures.remove(m.uid)
+		processed++
+	}
+	return processed, nil
+}
+
+// processMessage sends the message requests to the target, all of them must succeed for the message to be marked as processed
+func (c *Client) processMessage(ctx context.Context, m *message) error {
+	for _, req := range m.requests(c.opts.folder, c.opts.splitAttachments) {
+		reqCtx, span := tracing.StartReceive(ctx, "email.imap", c.opts.folder, nil)
+		resp, err := c.target.Do(reqCtx, req)
+		if err == nil && resp != nil && resp.IsError {
+			err = errors.New(resp.Error)
+		}
+		tracing.End(span, err)
+		if err != nil {
+			return err
+		}
+	}
+	c.log.Infof("processed message uid %d from %s successfully", m.uid, m.from)
+	return nil
+}
+
+// fail records a failed message, a message without retries left is flagged with the failed flag and is not processed again
+func (c *Client) fail(imapClient *client.Client, uid uint32, err error) {
+	if !c.failures.add(uid) {
+		c.log.Errorf("error processing message uid %d, %s, message will be processed again", uid, err.Error())
+		return
+	}
+	c.log.Errorf("error processing message uid %d, %s, no retries left, message is flagged with %s", uid, err.Error(), c.opts.failedFlag)
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uid)
+	item := imap.FormatFlagsOp(imap.AddFlags, true)
+	if err := imapClient.UidStore(seqSet, item, []interface{}{c.opts.failedFlag}, nil); err != nil {
+		// the message stays excluded from the searches of this binding
+		c.log.Errorf("error flagging failed message uid %d, %s", uid, err.Error())
+		return
+	}
+	c.failures.remove(uid)
+}
+
+func (c *Client) markProcessed(imapClient *client.Client, uid uint32) error {
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uid)
+	switch c.opts.processedAction {
+	case "move":
+		if err := imapClient.UidMove(seqSet, c.opts.moveToFolder); err != nil {
+			return fmt.Errorf("error moving message uid %d to %s, %w", uid, c.opts.moveToFolder, err)
+		}
+	case "delete":
+		item := imap.FormatFlagsOp(imap.AddFlags, true)
+		if err := imapClient.UidStore(seqSet, item, []interface{}{imap.DeletedFlag}, nil); err != nil {
+			return fmt.Errorf("error deleting message uid %d, %w", uid, err)
+		}
+		if err := imapClient.Expunge(nil); err != nil {
+			return fmt.Errorf("error expunging message uid %d, %w", uid, err)
+		}
+	default:
+		item := imap.FormatFlagsOp(imap.AddFlags, true)
+		if err := imapClient.UidStore(seqSet, item, []interface{}{c.opts.processedFlag}, nil); err != nil {
+			return fmt.Errorf("error flagging message uid %d, %w", uid, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	return nil
+}
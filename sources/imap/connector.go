@@ -0,0 +1,156 @@
+package imap
+
+import (
+	"math"
+
+	"github.com/kubemq-hub/builder/connector/common"
+)
+
+func Connector() *common.Connector {
+	return common.NewConnector().
+		SetKind("email.imap").
+		SetDescription("IMAP Mailbox Source").
+		SetName("IMAP").
+		SetProvider("").
+		SetCategory("Email").
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("host").
+				SetDescription("Set IMAP server host").
+				SetMust(true).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("port").
+				SetDescription("Set IMAP server port").
+				SetMust(false).
+				SetDefault("993").
+				SetMin(1).
+				SetMax(math.MaxUint16),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("username").
+				SetDescription("Set IMAP username").
+				SetMust(true).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("password").
+				SetDescription("Set IMAP password").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("use_tls").
+				SetTitle("Use TLS").
+				SetDescription("Set connecting with implicit TLS").
+				SetMust(false).
+				SetDefault("true"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("start_tls").
+				SetTitle("Use STARTTLS").
+				SetDescription("Set upgrading a plain connection with STARTTLS").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("insecure_skip_verify").
+				SetDescription("Set skipping server certificate verification").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("folder").
+				SetDescription("Set mailbox folder to watch").
+				SetMust(false).
+				SetDefault("INBOX"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("poll_interval_seconds").
+				SetTitle("Poll Interval (Seconds)").
+				SetDescription("Set polling interval when IDLE is not supported or no update was received").
+				SetMust(false).
+				SetDefault("60").
+				SetMin(1).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("batch_size").
+				SetDescription("Set how many messages to process in each batch").
+				SetMust(false).
+				SetDefault("100").
+				SetMin(1).
+				SetMax(1024),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("processed_action").
+				SetDescription("Set what to do with messages after the target succeeds").
+				SetMust(false).
+				SetOptions([]string{"flag", "move", "delete"}).
+				SetDefault("flag"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("processed_flag").
+				SetDescription("Set flag added to processed messages").
+				SetMust(false).
+				SetDefault("\\Seen"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("move_to_folder").
+				SetDescription("Set folder processed messages are moved to").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("split_attachments").
+				SetDescription("Set sending a request per each message attachment").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("max_retries").
+				SetDescription("Set retries of a failed message before it is flagged as failed").
+				SetMust(false).
+				SetDefault("3").
+				SetMin(0).
+				SetMax(1024),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("failed_flag").
+				SetDescription("Set flag added to messages without retries left").
+				SetMust(false).
+				SetDefault("$KubemqFailed"),
+		)
+}
@@ -0,0 +1,44 @@
+package imap
+
+// failures counts the failed processing attempts of the folder messages, messages without retries left are excluded from the searches
+type failures struct {
+	maxRetries  int
+	uidValidity uint32
+	counts      map[uint32]int
+}
+
+func newFailures(maxRetries int) *failures {
+	return &failures{
+		maxRetries: maxRetries,
+		counts:     map[uint32]int{},
+	}
+}
+
+// setUidValidity clears the counts when the folder uids are no longer valid
+func (f *failures) setUidValidity(uidValidity uint32) {
+	if f.uidValidity != uidValidity {
+		f.uidValidity = uidValidity
+		f.counts = map[uint32]int{}
+	}
+}
+
+// add records a failed attempt and returns true when the message has no retries left
+func (f *failures) add(uid uint32) bool {
+	f.counts[uid]++
+	return f.counts[uid] > f.maxRetries
+}
+
+func (f *failures) remove(uid uint32) {
+	delete(f.counts, uid)
+}
+
+// filter returns the uids of the messages which have retries left
+func (f *failures) filter(uids []uint32) []uint32 {
+	filtered := uids[:0]
+	for _, uid := range uids {
+		if f.counts[uid] <= f.maxRetries {
+			filtered = append(filtered, uid)
+		}
+	}
+	return filtered
+}
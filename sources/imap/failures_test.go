@@ -0,0 +1,73 @@
+package imap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		failed     []uint32
+		uids       []uint32
+		want       []uint32
+	}{
+		{
+			name:       "no failures",
+			maxRetries: 2,
+			uids:       []uint32{1, 2, 3},
+			want:       []uint32{1, 2, 3},
+		},
+		{
+			name:       "failures with retries left",
+			maxRetries: 2,
+			failed:     []uint32{1, 1, 2},
+			uids:       []uint32{1, 2, 3},
+			want:       []uint32{1, 2, 3},
+		},
+		{
+			name:       "failures without retries left",
+			maxRetries: 2,
+			failed:     []uint32{1, 1, 1, 2, 2, 2, 3},
+			uids:       []uint32{1, 2, 3, 4},
+			want:       []uint32{3, 4},
+		},
+		{
+			name:       "no retries",
+			maxRetries: 0,
+			failed:     []uint32{2},
+			uids:       []uint32{1, 2, 3},
+			want:       []uint32{1, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFailures(tt.maxRetries)
+			for _, uid := range tt.failed {
+				f.add(uid)
+			}
+			require.EqualValues(t, tt.want, f.filter(tt.uids))
+		})
+	}
+}
+
+func TestFailures_Exhausted(t *testing.T) {
+	f := newFailures(1)
+	require.False(t, f.add(10))
+	require.True(t, f.add(10))
+	require.Empty(t, f.filter([]uint32{10}))
+	f.remove(10)
+	require.EqualValues(t, []uint32{10}, f.filter([]uint32{10}))
+}
+
+func TestFailures_UidValidity(t *testing.T) {
+	f := newFailures(0)
+	f.setUidValidity(1)
+	f.add(10)
+	f.setUidValidity(1)
+	require.Empty(t, f.filter([]uint32{10}))
+	f.setUidValidity(2)
+	require.EqualValues(t, []uint32{10}, f.filter([]uint32{10}))
+}
@@ -0,0 +1,147 @@
+package imap
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-message/mail"
+	"github.com/kubemq-io/kubemq-targets/types"
+)
+
+type attachment struct {
+	filename    string
+	contentType string
+	data        []byte
+}
+
+type message struct {
+	uid         uint32
+	messageId   string
+	from        string
+	to          string
+	cc          string
+	subject     string
+	date        time.Time
+	body        []byte
+	contentType string
+	attachments []*attachment
+}
+
+func formatAddresses(header *mail.Header, key string) string {
+	list, err := header.AddressList(key)
+	if err != nil || len(list) == 0 {
+		return header.Get(key)
+	}
+	var addresses []string
+	for _, address := range list {
+		addresses = append(addresses, address.Address)
+	}
+	return strings.Join(addresses, ",")
+}
+
+// parseMessage reads a raw RFC 5322 message, the text/plain part is preferred over the text/html part as the message body
+func parseMessage(uid uint32, r io.Reader) (*message, error) {
+	mr, err := mail.CreateReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading message, %w", err)
+	}
+	m := &message{
+		uid:       uid,
+		messageId: mr.Header.Get("Message-Id"),
+		from:      formatAddresses(&mr.Header, "From"),
+		to:        formatAddresses(&mr.Header, "To"),
+		cc:        formatAddresses(&mr.Header, "Cc"),
+	}
+	m.subject, err = mr.Header.Subject()
+	if err != nil {
+		m.subject = mr.Header.Get("Subject")
+	}
+	m.date, _ = mr.Header.Date()
+	var htmlBody []byte
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading message part, %w", err)
+		}
+		data, err := ioutil.ReadAll(part.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading message part body, %w", err)
+		}
+		switch h := part.Header.(type) {
+		case *mail.InlineHeader:
+			contentType, _, _ := h.ContentType()
+			switch {
+			case contentType == "text/html" && htmlBody == nil:
+				htmlBody = data
+			case contentType == "text/plain" && m.body == nil, contentType == "" && m.body == nil:
+				m.body = data
+				m.contentType = "text/plain"
+			}
+		case *mail.AttachmentHeader:
+			filename, _ := h.Filename()
+			contentType, _, _ := h.ContentType()
+			m.attachments = append(m.attachments, &attachment{
+				filename:    filename,
+				contentType: contentType,
+				data:        data,
+			})
+		}
+	}
+	if m.body == nil && htmlBody != nil {
+		m.body = htmlBody
+		m.contentType = "text/html"
+	}
+	return m, nil
+}
+
+func (m *message) metadata(folder string) types.Metadata {
+	meta := types.NewMetadata().
+		Set("uid", fmt.Sprintf("%d", m.uid)).
+		Set("folder", folder).
+		Set("message_id", m.messageId).
+		Set("from", m.from).
+		Set("to", m.to).
+		Set("subject", m.subject).
+		Set("content_type", m.contentType).
+		Set("attachments", fmt.Sprintf("%d", len(m.attachments)))
+	if m.cc != "" {
+		meta.Set("cc", m.cc)
+	}
+	if !m.date.IsZero() {
+		meta.Set("date", m.date.UTC().Format(time.RFC3339))
+	}
+	return meta
+}
+
+// requests returns the message request followed by one request per attachment when split attachments is set
+func (m *message) requests(folder string, splitAttachments bool) []*types.Request {
+	requests := []*types.Request{
+		types.NewRequest().
+			SetMetadata(m.metadata(folder)).
+			SetData(m.body),
+	}
+	if !splitAttachments {
+		return requests
+	}
+	for i, a := range m.attachments {
+		meta := m.metadata(folder).
+			Set("attachment_index", fmt.Sprintf("%d", i)).
+			Set("attachment_filename", a.filename).
+			Set("attachment_content_type", a.contentType)
+		if a.contentType == "" {
+			meta.Set("attachment_content_type", mime.TypeByExtension(filepath.Ext(a.filename)))
+		}
+		requests = append(requests, types.NewRequest().
+			SetMetadata(meta).
+			SetData(a.data))
+	}
+	return requests
+}
@@ -0,0 +1,53 @@
+package imap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMessage = "From: Supplier <billing@supplier.com>\r\n" +
+	"To: invoices@example.com\r\n" +
+	"Subject: Invoice 1001\r\n" +
+	"Date: Mon, 02 Jan 2023 15:04:05 +0000\r\n" +
+	"Message-Id: <1001@supplier.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=boundary\r\n" +
+	"\r\n" +
+	"--boundary\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Please find the invoice attached.\r\n" +
+	"--boundary\r\n" +
+	"Content-Type: application/pdf\r\n" +
+	"Content-Disposition: attachment; filename=invoice-1001.pdf\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0xLjQ=\r\n" +
+	"--boundary--\r\n"
+
+func TestMessage_requests(t *testing.T) {
+	m, err := parseMessage(7, strings.NewReader(testMessage))
+	require.NoError(t, err)
+
+	requests := m.requests("INBOX", false)
+	require.Len(t, requests, 1)
+	require.EqualValues(t, "Please find the invoice attached.", string(requests[0].Data))
+	meta := requests[0].Metadata
+	require.EqualValues(t, "7", meta["uid"])
+	require.EqualValues(t, "INBOX", meta["folder"])
+	require.EqualValues(t, "<1001@supplier.com>", meta["message_id"])
+	require.EqualValues(t, "billing@supplier.com", meta["from"])
+	require.EqualValues(t, "invoices@example.com", meta["to"])
+	require.EqualValues(t, "Invoice 1001", meta["subject"])
+	require.EqualValues(t, "2023-01-02T15:04:05Z", meta["date"])
+	require.EqualValues(t, "1", meta["attachments"])
+
+	requests = m.requests("INBOX", true)
+	require.Len(t, requests, 2)
+	require.EqualValues(t, "%PDF-1.4", string(requests[1].Data))
+	require.EqualValues(t, "invoice-1001.pdf", requests[1].Metadata["attachment_filename"])
+	require.EqualValues(t, "application/pdf", requests[1].Metadata["attachment_content_type"])
+	require.EqualValues(t, "0", requests[1].Metadata["attachment_index"])
+}
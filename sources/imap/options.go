@@ -0,0 +1,100 @@
+package imap
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/kubemq-io/kubemq-targets/config"
+)
+
+const (
+	defaultPort         = 993
+	defaultFolder       = "INBOX"
+	defaultPollInterval = 60
+	defaultBatchSize    = 100
+	defaultMaxRetries   = 3
+	defaultFailedFlag   = "$KubemqFailed"
+)
+
+var processedActionsMap = map[string]string{
+	"":       "flag",
+	"flag":   "flag",
+	"move":   "move",
+	"delete": "delete",
+}
+
+type options struct {
+	host               string
+	port               int
+	username           string
+	password           string
+	useTLS             bool
+	startTLS           bool
+	insecureSkipVerify bool
+	folder             string
+	pollInterval       time.Duration
+	batchSize          int
+	processedAction    string
+	processedFlag      string
+	moveToFolder       string
+	splitAttachments   bool
+	maxRetries         int
+	failedFlag         string
+}
+
+func parseOptions(cfg config.Spec) (options, error) {
+	o := options{}
+	var err error
+	o.host, err = cfg.Properties.MustParseString("host")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing host value, %w", err)
+	}
+	o.port, err = cfg.Properties.ParseIntWithRange("port", defaultPort, 1, math.MaxUint16)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing port value, %w", err)
+	}
+	o.username, err = cfg.Properties.MustParseString("username")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing username value, %w", err)
+	}
+	o.password = cfg.Properties.ParseString("password", "")
+	o.useTLS = cfg.Properties.ParseBool("use_tls", true)
+	o.startTLS = cfg.Properties.ParseBool("start_tls", false)
+	if o.useTLS && o.startTLS {
+		return options{}, fmt.Errorf("use_tls and start_tls cannot be both set")
+	}
+	o.insecureSkipVerify = cfg.Properties.ParseBool("insecure_skip_verify", false)
+	o.folder = cfg.Properties.ParseString("folder", defaultFolder)
+	pollInterval, err := cfg.Properties.ParseIntWithRange("poll_interval_seconds", defaultPollInterval, 1, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing poll interval seconds value, %w", err)
+	}
+	o.pollInterval = time.Duration(pollInterval) * time.Second
+	o.batchSize, err = cfg.Properties.ParseIntWithRange("batch_size", defaultBatchSize, 1, 1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing batch size value, %w", err)
+	}
+	o.processedAction, err = cfg.Properties.ParseStringMap("processed_action", processedActionsMap)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing processed action value, %w", err)
+	}
+	o.processedFlag = cfg.Properties.ParseString("processed_flag", imap.SeenFlag)
+	if o.processedAction == "move" {
+		o.moveToFolder, err = cfg.Properties.MustParseString("move_to_folder")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing move to folder value, %w", err)
+		}
+	}
+	o.splitAttachments = cfg.Properties.ParseBool("split_attachments", false)
+	o.maxRetries, err = cfg.Properties.ParseIntWithRange("max_retries", defaultMaxRetries, 0, 1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max retries value, %w", err)
+	}
+	o.failedFlag = cfg.Properties.ParseString("failed_flag", defaultFailedFlag)
+	if o.processedAction == "flag" && o.failedFlag == o.processedFlag {
+		return options{}, fmt.Errorf("failed_flag and processed_flag cannot be the same")
+	}
+	return o, nil
+}
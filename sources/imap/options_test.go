@@ -0,0 +1,171 @@
+package imap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_parseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Spec
+		want    options
+		wantErr bool
+	}{
+		{
+			name: "valid options",
+			cfg: config.Spec{
+				Name: "email-imap",
+				Kind: "email.imap",
+				Properties: map[string]string{
+					"host":     "imap.example.com",
+					"username": "user",
+					"password": "pass",
+				},
+			},
+			want: options{
+				host:            "imap.example.com",
+				port:            993,
+				username:        "user",
+				password:        "pass",
+				useTLS:          true,
+				folder:          "INBOX",
+				pollInterval:    60 * time.Second,
+				batchSize:       100,
+				processedAction: "flag",
+				processedFlag:   "\\Seen",
+				maxRetries:      3,
+				failedFlag:      "$KubemqFailed",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid options - move with attachments",
+			cfg: config.Spec{
+				Name: "email-imap",
+				Kind: "email.imap",
+				Properties: map[string]string{
+					"host":                  "imap.example.com",
+					"port":                  "143",
+					"username":              "user",
+					"use_tls":               "false",
+					"start_tls":             "true",
+					"folder":                "Invoices",
+					"poll_interval_seconds": "10",
+					"batch_size":            "5",
+					"processed_action":      "move",
+					"move_to_folder":        "Invoices/Done",
+					"split_attachments":     "true",
+					"max_retries":           "0",
+					"failed_flag":           "Failed",
+				},
+			},
+			want: options{
+				host:             "imap.example.com",
+				port:             143,
+				username:         "user",
+				startTLS:         true,
+				folder:           "Invoices",
+				pollInterval:     10 * time.Second,
+				batchSize:        5,
+				processedAction:  "move",
+				processedFlag:    "\\Seen",
+				moveToFolder:     "Invoices/Done",
+				splitAttachments: true,
+				maxRetries:       0,
+				failedFlag:       "Failed",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid options - no host",
+			cfg: config.Spec{
+				Name: "email-imap",
+				Kind: "email.imap",
+				Properties: map[string]string{
+					"username": "user",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - tls and start tls",
+			cfg: config.Spec{
+				Name: "email-imap",
+				Kind: "email.imap",
+				Properties: map[string]string{
+					"host":      "imap.example.com",
+					"username":  "user",
+					"start_tls": "true",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - move without folder",
+			cfg: config.Spec{
+				Name: "email-imap",
+				Kind: "email.imap",
+				Properties: map[string]string{
+					"host":             "imap.example.com",
+					"username":         "user",
+					"processed_action": "move",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad processed action",
+			cfg: config.Spec{
+				Name: "email-imap",
+				Kind: "email.imap",
+				Properties: map[string]string{
+					"host":             "imap.example.com",
+					"username":         "user",
+					"processed_action": "bad-action",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad max retries",
+			cfg: config.Spec{
+				Name: "email-imap",
+				Kind: "email.imap",
+				Properties: map[string]string{
+					"host":        "imap.example.com",
+					"username":    "user",
+					"max_retries": "-1",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - failed flag same as processed flag",
+			cfg: config.Spec{
+				Name: "email-imap",
+				Kind: "email.imap",
+				Properties: map[string]string{
+					"host":        "imap.example.com",
+					"username":    "user",
+					"failed_flag": "\\Seen",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tt.want, got)
+		})
+	}
+}
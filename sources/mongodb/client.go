@@ -0,0 +1,228 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kubemq-hub/builder/connector/common"
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/middleware"
+	"github.com/kubemq-io/kubemq-targets/pkg/delivery"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
+	"github.com/kubemq-io/kubemq-targets/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	monogOptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var errInvalidTarget = errors.New("invalid controller received, cannot be null")
+
+// changeEvent holds the change stream event fields set in the request metadata
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	Namespace     struct {
+		Database   string `bson:"db"`
+		Collection string `bson:"coll"`
+	} `bson:"ns"`
+	DocumentKey bson.Raw            `bson:"documentKey"`
+	ClusterTime primitive.Timestamp `bson:"clusterTime"`
+}
+
+type Client struct {
+	opts        options
+	log         *logger.Logger
+	client      *mongo.Client
+	target      middleware.Middleware
+	bindingName string
+	cancel      context.CancelFunc
+	done        chan struct{}
+	// resumeToken is the token of the last processed event, it is owned by the run loop goroutine
+	resumeToken bson.Raw
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func (c *Client) Connector() *common.Connector {
+	return Connector()
+}
+
+func (c *Client) Init(ctx context.Context, cfg config.Spec, bindingName string, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger(cfg.Kind)
+	}
+	var err error
+	c.opts, err = parseOptions(cfg)
+	if err != nil {
+		return err
+	}
+	c.bindingName = bindingName
+	c.resumeToken = c.opts.resumeToken
+	c.client, err = mongo.Connect(ctx, monogOptions.Client().ApplyURI(c.opts.url))
+	if err != nil {
+		return fmt.Errorf("error in creating mongodb client: %s", err)
+	}
+	err = c.client.Ping(ctx, nil)
+	if err != nil {
+		_ = c.client.Disconnect(context.Background())
+		return fmt.Errorf("error in creating mongodb client: %s", err)
+	}
+	return nil
+}
+
+func (c *Client) Start(ctx context.Context, target middleware.Middleware) error {
+	if target == nil {
+		return errInvalidTarget
+	} else {
+		c.target = target
+	}
+	// the first change stream is opened here so a deployment without change streams support fails the binding
+	stream, err := c.watch(ctx)
+	if err != nil {
+		return err
+	}
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	go c.run(ctx, stream)
+	return nil
+}
+
+// run processes the change stream events, the change stream is opened again after the last processed event when it fails
+func (c *Client) run(ctx context.Context, stream *mongo.ChangeStream) {
+	defer close(c.done)
+	for {
+		err := c.process(ctx, stream)
+		_ = stream.Close(context.Background())
+		if ctx.Err() != nil {
+			return
+		}
+		c.log.Errorf("error watching %s change stream, %s", c.namespace(), err.Error())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.opts.reconnectInterval):
+			}
+			stream, err = c.watch(ctx)
+			if err == nil {
+				break
+			}
+			c.log.Errorf("error opening %s change stream, %s", c.namespace(), err.Error())
+		}
+	}
+}
+
+// watch opens a change stream of the collection, the database or the whole deployment, resuming after the last processed event
+func (c *Client) watch(ctx context.Context) (*mongo.ChangeStream, error) {
+	streamOptions := monogOptions.ChangeStream().SetFullDocument(c.opts.fullDocument)
+	if c.resumeToken != nil {
+		streamOptions.SetResumeAfter(c.resumeToken)
+	}
+	pipeline := c.opts.changeStreamPipeline()
+	var stream *mongo.ChangeStream
+	var err error
+	switch {
+	case c.opts.collection != "":
+		stream, err = c.client.Database(c.opts.database).Collection(c.opts.collection).Watch(ctx, pipeline, streamOptions)
+	case c.opts.database != "":
+		stream, err = c.client.Database(c.opts.database).Watch(ctx, pipeline, streamOptions)
+	default:
+		stream, err = c.client.Watch(ctx, pipeline, streamOptions)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening change stream, %w", err)
+	}
+	return stream, nil
+}
+
+// process sends the change stream events to the target, a failed event is retried up to max_attempts and then skipped,
+// so an event which cannot be parsed or is always rejected by the target does not stall the change stream
+func (c *Client) process(ctx context.Context, stream *mongo.ChangeStream) error {
+	for stream.Next(ctx) {
+		req, err := newRequest(stream.Current)
+		if err != nil {
+			c.log.Errorf("error processing %s event, %s, event skipped", c.namespace(), err.Error())
+			c.resumeToken = stream.ResumeToken()
+			continue
+		}
+		operationType := req.Metadata["operation_type"]
+		reqCtx, span := tracing.StartReceive(ctx, "stores.mongodb", c.namespace(), nil)
+		_, attempts, err := delivery.Send(reqCtx, c.target, req, delivery.Retry{
+			MaxAttempts: c.opts.maxAttempts,
+			Interval:    c.opts.retryInterval,
+			OnRetry: func(attempts int, delay time.Duration, err error) {
+				c.log.Errorf("error processing %s event, attempt %d, %s, retrying in %s", operationType, attempts, err.Error(), delay)
+			},
+		})
+		tracing.End(span, err)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			c.log.Errorf("error processing %s event after %d attempts, %s, event skipped", operationType, attempts, err.Error())
+		}
+		c.resumeToken = stream.ResumeToken()
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	return errors.New("change stream closed")
+}
+
+func (c *Client) namespace() string {
+	switch {
+	case c.opts.collection != "":
+		return c.opts.database + "." + c.opts.collection
+	case c.opts.database != "":
+		return c.opts.database
+	}
+	return "*"
+}
+
+// newRequest returns the request of a change stream event, the request data is the event in relaxed extended json
+func newRequest(raw bson.Raw) (*types.Request, error) {
+	event := changeEvent{}
+	if err := bson.Unmarshal(raw, &event); err != nil {
+		return nil, fmt.Errorf("error parsing change event, %w", err)
+	}
+	data, err := bson.MarshalExtJSON(raw, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing change event, %w", err)
+	}
+	req := types.NewRequest().
+		SetData(data).
+		SetMetadataKeyValue("operation_type", event.OperationType).
+		SetMetadataKeyValue("database", event.Namespace.Database).
+		SetMetadataKeyValue("collection", event.Namespace.Collection).
+		SetMetadataKeyValue("cluster_time", time.Unix(int64(event.ClusterTime.T), 0).UTC().Format(time.RFC3339))
+	if event.DocumentKey != nil {
+		documentKey, err := bson.MarshalExtJSON(event.DocumentKey, false, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing change event document key, %w", err)
+		}
+		req.SetMetadataKeyValue("document_key", string(documentKey))
+	}
+	if id, err := raw.LookupErr("_id"); err == nil {
+		if resumeToken, err := bson.MarshalExtJSON(id.Document(), false, false); err == nil {
+			req.SetMetadataKeyValue("resume_token", string(resumeToken))
+		}
+	}
+	return req, nil
+}
+
+func (c *Client) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+	if c.client != nil {
+		return c.client.Disconnect(context.Background())
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClient_newRequest(t *testing.T) {
+	event, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: bson.D{{Key: "_data", Value: "8263"}}},
+		{Key: "operationType", Value: "insert"},
+		{Key: "ns", Value: bson.D{{Key: "db", Value: "admin"}, {Key: "coll", Value: "test"}}},
+		{Key: "documentKey", Value: bson.D{{Key: "_id", Value: "some-id"}}},
+		{Key: "fullDocument", Value: bson.D{{Key: "_id", Value: "some-id"}, {Key: "status", Value: "new"}}},
+	})
+	require.NoError(t, err)
+	req, err := newRequest(event)
+	require.NoError(t, err)
+	require.Equal(t, "insert", req.Metadata["operation_type"])
+	require.Equal(t, "admin", req.Metadata["database"])
+	require.Equal(t, "test", req.Metadata["collection"])
+	require.JSONEq(t, `{"_id":"some-id"}`, req.Metadata["document_key"])
+	require.JSONEq(t, `{"_data":"8263"}`, req.Metadata["resume_token"])
+	require.Contains(t, string(req.Data), `"status":"new"`)
+}
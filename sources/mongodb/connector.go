@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"math"
+
+	"github.com/kubemq-hub/builder/connector/common"
+)
+
+func Connector() *common.Connector {
+	return common.NewConnector().
+		SetKind("stores.mongodb").
+		SetDescription("MongoDB Change Stream Source").
+		SetName("MongoDB").
+		SetProvider("").
+		SetCategory("Store").
+		SetTags("db", "no-sql", "cdc").
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("url").
+				SetTitle("Connection String").
+				SetDescription("Set MongoDB connection string, change streams require a replica set or a sharded cluster").
+				SetMust(true).
+				SetDefault("mongodb://localhost:27017/?replicaSet=rs0"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("database").
+				SetDescription("Set MongoDB database to watch, empty for all databases").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("collection").
+				SetDescription("Set MongoDB collection to watch, empty for all database collections").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("operation_types").
+				SetDescription("Set comma separated change operation types to send").
+				SetMust(false).
+				SetDefault(defaultOperationTypes),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("full_document").
+				SetDescription("Set full document of update events").
+				SetMust(false).
+				SetOptions([]string{"update_lookup", "default", "when_available", "required"}).
+				SetDefault("update_lookup"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("pipeline").
+				SetDescription("Set change stream aggregation pipeline stages as json array").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("resume_token").
+				SetDescription("Set change stream resume token to start after").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("reconnect_interval_seconds").
+				SetDescription("Set interval between change stream reconnect attempts").
+				SetMust(false).
+				SetDefault("5").
+				SetMin(1).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("max_attempts").
+				SetDescription("Set how many attempts to process an event before skipping it, 0 retries until the target succeeds").
+				SetMust(false).
+				SetDefault("5").
+				SetMin(0).
+				SetMax(1024),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("retry_interval_seconds").
+				SetDescription("Set wait before retrying a failed event, doubled after each attempt up to 30 seconds").
+				SetMust(false).
+				SetDefault("1").
+				SetMin(0).
+				SetMax(3600),
+		)
+}
@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"go.mongodb.org/mongo-driver/bson"
+	monogOptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultOperationTypes    = "insert,update,replace,delete"
+	defaultReconnectInterval = 5
+	defaultMaxAttempts       = 5
+	defaultRetryInterval     = 1
+)
+
+var operationTypesMap = map[string]string{
+	"insert":  "insert",
+	"update":  "update",
+	"replace": "replace",
+	"delete":  "delete",
+}
+
+var fullDocumentMap = map[string]monogOptions.FullDocument{
+	"":               monogOptions.UpdateLookup,
+	"update_lookup":  monogOptions.UpdateLookup,
+	"default":        monogOptions.Default,
+	"when_available": monogOptions.WhenAvailable,
+	"required":       monogOptions.Required,
+}
+
+type options struct {
+	url               string
+	database          string
+	collection        string
+	operationTypes    []string
+	fullDocument      monogOptions.FullDocument
+	pipeline          bson.A
+	resumeToken       bson.Raw
+	reconnectInterval time.Duration
+	maxAttempts       int
+	retryInterval     time.Duration
+}
+
+func parseOptions(cfg config.Spec) (options, error) {
+	o := options{}
+	var err error
+	o.url, err = cfg.Properties.MustParseString("url")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing url, %w", err)
+	}
+	o.database = cfg.Properties.ParseString("database", "")
+	o.collection = cfg.Properties.ParseString("collection", "")
+	if o.collection != "" && o.database == "" {
+		return options{}, fmt.Errorf("error parsing collection, a collection requires a database")
+	}
+	for _, operationType := range strings.Split(cfg.Properties.ParseString("operation_types", defaultOperationTypes), ",") {
+		operationType = strings.TrimSpace(operationType)
+		if operationType == "" {
+			continue
+		}
+		if _, ok := operationTypesMap[operationType]; !ok {
+			return options{}, fmt.Errorf("error parsing operation_types, invalid operation type %s", operationType)
+		}
+		o.operationTypes = append(o.operationTypes, operationType)
+	}
+	if len(o.operationTypes) == 0 {
+		return options{}, fmt.Errorf("error parsing operation_types, no operation types found")
+	}
+	fullDocument := cfg.Properties.ParseString("full_document", "")
+	var ok bool
+	o.fullDocument, ok = fullDocumentMap[fullDocument]
+	if !ok {
+		return options{}, fmt.Errorf("error parsing full_document, invalid value %s", fullDocument)
+	}
+	if pipeline := cfg.Properties.ParseString("pipeline", ""); pipeline != "" {
+		err = bson.UnmarshalExtJSON([]byte(pipeline), false, &o.pipeline)
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing pipeline, a json array of stages is expected, %w", err)
+		}
+	}
+	if resumeToken := cfg.Properties.ParseString("resume_token", ""); resumeToken != "" {
+		err = bson.UnmarshalExtJSON([]byte(resumeToken), false, &o.resumeToken)
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing resume_token, %w", err)
+		}
+	}
+	reconnectInterval, err := cfg.Properties.ParseIntWithRange("reconnect_interval_seconds", defaultReconnectInterval, 1, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing reconnect interval seconds value, %w", err)
+	}
+	o.reconnectInterval = time.Duration(reconnectInterval) * time.Second
+	o.maxAttempts, err = cfg.Properties.ParseIntWithRange("max_attempts", defaultMaxAttempts, 0, 1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max attempts value, %w", err)
+	}
+	retryInterval, err := cfg.Properties.ParseIntWithRange("retry_interval_seconds", defaultRetryInterval, 0, 3600)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing retry interval seconds value, %w", err)
+	}
+	o.retryInterval = time.Duration(retryInterval) * time.Second
+	return o, nil
+}
+
+// changeStreamPipeline returns the pipeline stages filtering the operation types followed by the pipeline option stages
+func (o options) changeStreamPipeline() bson.A {
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "operationType", Value: bson.D{{Key: "$in", Value: o.operationTypes}}}}}},
+	}
+	return append(pipeline, o.pipeline...)
+}
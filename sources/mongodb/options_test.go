@@ -0,0 +1,142 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestOptions_parseOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		wantErr    bool
+	}{
+		{
+			name: "valid options",
+			properties: map[string]string{
+				"url": "mongodb://localhost:27017/?replicaSet=rs0",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid options - collection",
+			properties: map[string]string{
+				"url":                        "mongodb://localhost:27017/?replicaSet=rs0",
+				"database":                   "admin",
+				"collection":                 "test",
+				"operation_types":            "insert, delete",
+				"full_document":              "when_available",
+				"pipeline":                   `[{"$match":{"fullDocument.status":"new"}}]`,
+				"resume_token":               `{"_data":"8263"}`,
+				"reconnect_interval_seconds": "10",
+				"max_attempts":               "0",
+				"retry_interval_seconds":     "2",
+			},
+			wantErr: false,
+		},
+		{
+			name:       "invalid options - no url",
+			properties: map[string]string{},
+			wantErr:    true,
+		},
+		{
+			name: "invalid options - collection without database",
+			properties: map[string]string{
+				"url":        "mongodb://localhost:27017/?replicaSet=rs0",
+				"collection": "test",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad operation type",
+			properties: map[string]string{
+				"url":             "mongodb://localhost:27017/?replicaSet=rs0",
+				"operation_types": "insert,drop",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad full document",
+			properties: map[string]string{
+				"url":           "mongodb://localhost:27017/?replicaSet=rs0",
+				"full_document": "bad",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad pipeline",
+			properties: map[string]string{
+				"url":      "mongodb://localhost:27017/?replicaSet=rs0",
+				"pipeline": `{"$match":{}}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad resume token",
+			properties: map[string]string{
+				"url":          "mongodb://localhost:27017/?replicaSet=rs0",
+				"resume_token": "bad-json",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad reconnect interval",
+			properties: map[string]string{
+				"url":                        "mongodb://localhost:27017/?replicaSet=rs0",
+				"reconnect_interval_seconds": "0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad max attempts",
+			properties: map[string]string{
+				"url":          "mongodb://localhost:27017/?replicaSet=rs0",
+				"max_attempts": "-1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad retry interval",
+			properties: map[string]string{
+				"url":                    "mongodb://localhost:27017/?replicaSet=rs0",
+				"retry_interval_seconds": "3601",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(config.Spec{
+				Name:       "mongodb-source",
+				Kind:       "stores.mongodb",
+				Properties: tt.properties,
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOptions_changeStreamPipeline(t *testing.T) {
+	opts, err := parseOptions(config.Spec{
+		Name: "mongodb-source",
+		Kind: "stores.mongodb",
+		Properties: map[string]string{
+			"url":             "mongodb://localhost:27017/?replicaSet=rs0",
+			"operation_types": "insert,update",
+			"pipeline":        `[{"$match":{"fullDocument.status":"new"}}]`,
+		},
+	})
+	require.NoError(t, err)
+	pipeline := opts.changeStreamPipeline()
+	require.Len(t, pipeline, 2)
+	data, err := bson.MarshalExtJSON(bson.D{{Key: "pipeline", Value: pipeline}}, false, false)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"pipeline":[{"$match":{"operationType":{"$in":["insert","update"]}}},{"$match":{"fullDocument.status":"new"}}]}`, string(data))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/middleware"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
 	"github.com/kubemq-io/kubemq-targets/types"
 
@@ -100,7 +101,9 @@ func (c *Client) runClient(ctx context.Context, client *kubemq.Client) error {
 					queryResponse := client.R().
 						SetRequestId(query.Id).
 						SetResponseTo(query.ResponseTo)
-					resp, err := c.processQuery(ctx, query)
+					queryCtx, span := tracing.StartReceive(ctx, "kubemq.query", c.opts.channel, query.Tags)
+					resp, err := c.processQuery(queryCtx, query)
+					tracing.End(span, err)
 					if err != nil {
 						resp = types.NewResponse().SetError(err)
 					}
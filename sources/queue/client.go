@@ -11,7 +11,9 @@ import (
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/middleware"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/types"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 var errInvalidTarget = errors.New("invalid controller received, cannot be null")
@@ -125,30 +127,47 @@ func (c *Client) processQueueMessage(ctx context.Context, client *queues_stream.
 		} else {
 			req, err = types.ParseRequest(message.Body)
 			if err != nil {
+				if c.opts.deadLetterChannel != "" {
+					c.sendToDeadLetter(ctx, client, message, fmt.Errorf("invalid request format, %w", err))
+					continue
+				}
 				return fmt.Errorf("invalid request format, %w", err)
 			}
 		}
 		c.log.Infof("received request from queue %s, sending to target", c.opts.channel)
-		resp, err := c.target.Do(ctx, req)
+		reqCtx, span := tracing.StartReceive(ctx, "kubemq.queue", c.opts.channel, message.Tags)
+		resp, err := c.target.Do(reqCtx, req)
+		// a response with an error is a failed attempt, so the message is redelivered and reaches the dead letter queue
+		if err == nil && resp != nil && resp.IsError {
+			err = errors.New(resp.Error)
+		}
+		tracing.End(span, err)
 		if err != nil {
 			if c.opts.responseChannel != "" {
-				errResp := types.NewResponse().SetError(err)
-				_, errSend := client.Send(ctx, errResp.ToQueueStreamMessage().SetChannel(c.opts.responseChannel))
+				errResp := resp
+				if errResp == nil {
+					errResp = types.NewResponse().SetError(err)
+				}
+				_, errSend := client.Send(ctx, responseMessage(reqCtx, errResp).SetChannel(c.opts.responseChannel))
 				if errSend != nil {
 					c.log.Errorf("error sending response to a queue, %s", errSend.Error())
 				}
 			}
-			c.log.Errorf("error processing request from queue, %s, sending back to the queue", err.Error())
-			_ = message.NAck()
-			time.Sleep(time.Second)
+			if c.opts.deadLetterChannel != "" && receiveCount(message) >= c.opts.maxReceiveCount {
+				c.sendToDeadLetter(ctx, client, message, err)
+			} else {
+				c.log.Errorf("error processing request from queue, %s, sending back to the queue", err.Error())
+				_ = message.NAck()
+				time.Sleep(time.Second)
+			}
 		} else {
 			c.log.Infof("processed request from queue successfully")
 			_ = message.Ack()
 		}
 
-		if resp != nil {
+		if err == nil && resp != nil {
 			if c.opts.responseChannel != "" {
-				_, errSend := client.Send(ctx, resp.ToQueueStreamMessage().SetChannel(c.opts.responseChannel))
+				_, errSend := client.Send(ctx, responseMessage(reqCtx, resp).SetChannel(c.opts.responseChannel))
 				if errSend != nil {
 					c.log.Errorf("error sending response to a queue, %s", errSend.Error())
 				}
@@ -158,6 +177,48 @@ func (c *Client) processQueueMessage(ctx context.Context, client *queues_stream.
 	return nil
 }
 
+// responseMessage converts the response to a queue message carrying the request trace context in its tags
+func responseMessage(ctx context.Context, resp *types.Response) *queues_stream.QueueMessage {
+	msg := resp.ToQueueStreamMessage()
+	if msg.Tags == nil {
+		msg.Tags = map[string]string{}
+	}
+	tracing.Inject(ctx, propagation.MapCarrier(msg.Tags))
+	return msg
+}
+
+func receiveCount(message *queues_stream.QueueMessage) int {
+	if message.Attributes == nil {
+		return 1
+	}
+	return int(message.Attributes.ReceiveCount)
+}
+
+func (c *Client) sendToDeadLetter(ctx context.Context, client *queues_stream.QueuesStreamClient, message *queues_stream.QueueMessage, processErr error) {
+	attempts := receiveCount(message)
+	dlMsg := queues_stream.NewQueueMessage().
+		SetChannel(c.opts.deadLetterChannel).
+		SetMetadata(message.Metadata).
+		SetBody(message.Body).
+		SetTags(message.Tags).
+		AddTag("dead_letter_error", processErr.Error()).
+		AddTag("dead_letter_attempts", fmt.Sprintf("%d", attempts)).
+		AddTag("dead_letter_binding", c.bindingName).
+		AddTag("dead_letter_source_channel", c.opts.channel)
+	result, err := client.Send(ctx, dlMsg)
+	if err == nil && result != nil && len(result.Results) > 0 && result.Results[0].IsError {
+		err = errors.New(result.Results[0].Error)
+	}
+	if err != nil {
+		c.log.Errorf("error sending request to dead letter queue %s, %s, sending back to the queue", c.opts.deadLetterChannel, err.Error())
+		_ = message.NAck()
+		time.Sleep(time.Second)
+		return
+	}
+	c.log.Errorf("error processing request from queue after %d attempts, %s, moved to dead letter queue %s", attempts, processErr.Error(), c.opts.deadLetterChannel)
+	_ = message.Ack()
+}
+
 func (c *Client) Stop() error {
 	c.isStopped = true
 	return nil
@@ -91,5 +91,23 @@ func Connector() *common.Connector {
 				SetDescription("Set how long to wait in seconds for messages during pull of requests").
 				SetMust(false).
 				SetDefault("5"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("dead_letter_channel").
+				SetTitle("Dead Letter Channel").
+				SetDescription("Set Queue channel for requests which failed max receive count times").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("max_receive_count").
+				SetTitle("Max Receive Count").
+				SetDescription("Set how many failed attempts before moving request to dead letter channel").
+				SetMust(false).
+				SetDefault("0"),
 		)
 }
@@ -24,6 +24,8 @@ type options struct {
 	batchSize         int
 	waitTimeout       int
 	doNotParsePayload bool
+	maxReceiveCount   int
+	deadLetterChannel string
 }
 
 func parseOptions(cfg config.Spec) (options, error) {
@@ -57,5 +59,13 @@ func parseOptions(cfg config.Spec) (options, error) {
 		return options{}, fmt.Errorf("error parsing wait timeout value, %w", err)
 	}
 	o.doNotParsePayload = cfg.Properties.ParseBool("do_not_parse_payload", false)
+	o.deadLetterChannel = cfg.Properties.ParseString("dead_letter_channel", "")
+	o.maxReceiveCount, err = cfg.Properties.ParseIntWithRange("max_receive_count", 0, 0, 1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max receive count value, %w", err)
+	}
+	if o.deadLetterChannel != "" && o.maxReceiveCount == 0 {
+		return options{}, fmt.Errorf("error parsing max receive count value, must be greater than 0 when dead letter channel is set")
+	}
 	return o, nil
 }
@@ -99,6 +99,60 @@ func TestOptions_parseOptions(t *testing.T) {
 			want:    options{},
 			wantErr: true,
 		},
+		{
+			name: "valid options - dead letter channel",
+			cfg: config.Spec{
+				Name: "kubemq-rpc",
+				Kind: "",
+				Properties: map[string]string{
+					"address":             "localhost:50000",
+					"client_id":           "some-clients-id",
+					"channel":             "some-channel",
+					"dead_letter_channel": "some-dead-letter-channel",
+					"max_receive_count":   "3",
+				},
+			},
+			want: options{
+				host:              "localhost",
+				port:              50000,
+				clientId:          "some-clients-id",
+				channel:           "some-channel",
+				sources:           1,
+				waitTimeout:       5,
+				batchSize:         1,
+				maxReceiveCount:   3,
+				deadLetterChannel: "some-dead-letter-channel",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid options - dead letter channel without max receive count",
+			cfg: config.Spec{
+				Name: "kubemq-rpc",
+				Kind: "",
+				Properties: map[string]string{
+					"address":             "localhost:50000",
+					"channel":             "channel",
+					"dead_letter_channel": "some-dead-letter-channel",
+				},
+			},
+			want:    options{},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad max receive count",
+			cfg: config.Spec{
+				Name: "kubemq-rpc",
+				Kind: "",
+				Properties: map[string]string{
+					"address":           "localhost:50000",
+					"channel":           "channel",
+					"max_receive_count": "-1",
+				},
+			},
+			want:    options{},
+			wantErr: true,
+		},
 		{
 			name: "invalid options - bad sources",
 			cfg: config.Spec{
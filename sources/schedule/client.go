@@ -0,0 +1,159 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/kubemq-hub/builder/connector/common"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/middleware"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
+	"github.com/kubemq-io/kubemq-targets/types"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var errInvalidTarget = errors.New("invalid target received, cannot be nil")
+
+type Client struct {
+	opts        options
+	log         *logger.Logger
+	target      middleware.Middleware
+	client      *kubemq.Client
+	bindingName string
+	running     int32
+	cancel      context.CancelFunc
+}
+
+func New() *Client {
+	return &Client{}
+}
+
+func (c *Client) Connector() *common.Connector {
+	return Connector()
+}
+
+func (c *Client) Init(ctx context.Context, cfg config.Spec, bindingName string, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger(cfg.Kind)
+	}
+	var err error
+	c.opts, err = parseOptions(cfg)
+	if err != nil {
+		return err
+	}
+	c.bindingName = bindingName
+	if c.opts.responseChannel != "" {
+		c.client, err = kubemq.NewClient(ctx,
+			kubemq.WithAddress(c.opts.host, c.opts.port),
+			kubemq.WithClientId(fmt.Sprintf("kubemq-targets_%s_%s", bindingName, c.opts.clientId)),
+			kubemq.WithTransportType(kubemq.TransportTypeGRPC),
+			kubemq.WithCheckConnection(true),
+			kubemq.WithAuthToken(c.opts.authToken),
+			kubemq.WithAutoReconnect(true))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) Start(ctx context.Context, target middleware.Middleware) error {
+	if target == nil {
+		return errInvalidTarget
+	} else {
+		c.target = target
+	}
+	ctx, c.cancel = context.WithCancel(ctx)
+	go c.run(ctx)
+	return nil
+}
+
+func (c *Client) run(ctx context.Context) {
+	for {
+		now := time.Now()
+		next := c.opts.schedule.Next(now.In(c.opts.location))
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-timer.C:
+			go c.fire(ctx, next)
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// fire sends the configured request to the target, a run is skipped when the previous one is still in progress
+// unless overlapping runs are allowed
+func (c *Client) fire(ctx context.Context, scheduled time.Time) {
+	if !c.opts.allowOverlap {
+		if !atomic.CompareAndSwapInt32(&c.running, 0, 1) {
+			c.log.Infof("previous run is still in progress, skipping run scheduled at %s", scheduled.Format(time.RFC3339))
+			return
+		}
+		defer atomic.StoreInt32(&c.running, 0)
+	}
+	if c.opts.jitter > 0 {
+		delay := time.NewTimer(time.Duration(rand.Int63n(int64(c.opts.jitter))))
+		select {
+		case <-delay.C:
+		case <-ctx.Done():
+			delay.Stop()
+			return
+		}
+	}
+	c.log.Infof("running schedule %s, sending request to target", c.opts.expression)
+	reqCtx, span := tracing.StartReceive(ctx, "schedule", c.bindingName, nil)
+	resp, err := c.target.Do(reqCtx, c.request())
+	tracing.End(span, err)
+	if err != nil {
+		c.log.Errorf("error processing scheduled request, %s", err.Error())
+		resp = types.NewResponse().SetError(err)
+	} else {
+		c.log.Infof("processed scheduled request successfully")
+	}
+	if c.opts.responseChannel != "" && resp != nil {
+		c.sendResponse(reqCtx, resp)
+	}
+}
+
+// request returns a new copy of the configured request, middlewares may change the request metadata
+func (c *Client) request() *types.Request {
+	metadata := types.NewMetadata()
+	for key, value := range c.opts.metadata {
+		metadata.Set(key, value)
+	}
+	return types.NewRequest().SetMetadata(metadata).SetData(c.opts.data)
+}
+
+func (c *Client) sendResponse(ctx context.Context, resp *types.Response) {
+	tags := map[string]string{}
+	tracing.Inject(ctx, propagation.MapCarrier(tags))
+	result, err := c.client.SetQueueMessage(resp.ToQueueMessage()).
+		SetChannel(c.opts.responseChannel).
+		SetTags(tags).
+		Send(ctx)
+	if err == nil && result.IsError {
+		err = errors.New(result.Error)
+	}
+	if err != nil {
+		c.log.Errorf("error sending response to a queue, %s", err.Error())
+	}
+}
+
+func (c *Client) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.client != nil {
+		_ = c.client.Close()
+	}
+	return nil
+}
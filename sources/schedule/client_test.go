@@ -0,0 +1,115 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/middleware"
+	"github.com/kubemq-io/kubemq-targets/types"
+	"github.com/stretchr/testify/require"
+)
+
+type countingTarget struct {
+	delay    time.Duration
+	calls    int32
+	mu       sync.Mutex
+	requests []*types.Request
+}
+
+func (t *countingTarget) Do(ctx context.Context, request *types.Request) (*types.Response, error) {
+	atomic.AddInt32(&t.calls, 1)
+	t.mu.Lock()
+	t.requests = append(t.requests, request)
+	t.mu.Unlock()
+	time.Sleep(t.delay)
+	return types.NewResponse().SetData(request.Data), nil
+}
+
+func setupClient(ctx context.Context, properties map[string]string) (*Client, error) {
+	c := New()
+	err := c.Init(ctx, config.Spec{
+		Name:       "schedule",
+		Kind:       "schedule",
+		Properties: properties,
+	}, "schedule-binding", nil)
+	return c, err
+}
+
+func TestClient_Start(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, err := setupClient(ctx, map[string]string{
+		"cron":     "* * * * * *",
+		"metadata": `{"method":"list"}`,
+		"data":     "some-data",
+	})
+	require.NoError(t, err)
+	target := &countingTarget{}
+	require.NoError(t, c.Start(ctx, target))
+	time.Sleep(2500 * time.Millisecond)
+	require.NoError(t, c.Stop())
+	require.GreaterOrEqual(t, atomic.LoadInt32(&target.calls), int32(2))
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	for _, req := range target.requests {
+		require.EqualValues(t, "list", req.Metadata["method"])
+		require.EqualValues(t, []byte("some-data"), req.Data)
+	}
+}
+
+func TestClient_Start_BadTarget(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, err := setupClient(ctx, map[string]string{
+		"interval_seconds": "1",
+	})
+	require.NoError(t, err)
+	require.Error(t, c.Start(ctx, nil))
+}
+
+func TestClient_fire(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowOverlap string
+		wantCalls    int32
+	}{
+		{
+			name:         "overlap prevented",
+			allowOverlap: "false",
+			wantCalls:    1,
+		},
+		{
+			name:         "overlap allowed",
+			allowOverlap: "true",
+			wantCalls:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c, err := setupClient(ctx, map[string]string{
+				"interval_seconds": "1",
+				"allow_overlap":    tt.allowOverlap,
+			})
+			require.NoError(t, err)
+			target := &countingTarget{delay: 200 * time.Millisecond}
+			c.target = middleware.Middleware(target)
+			wg := sync.WaitGroup{}
+			for i := 0; i < 2; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					c.fire(ctx, time.Now())
+				}()
+				time.Sleep(50 * time.Millisecond)
+			}
+			wg.Wait()
+			require.EqualValues(t, tt.wantCalls, atomic.LoadInt32(&target.calls))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"math"
+
+	"github.com/kubemq-hub/builder/connector/common"
+)
+
+func Connector() *common.Connector {
+	return common.NewConnector().
+		SetKind("schedule").
+		SetDescription("Scheduled Requests Source").
+		SetName("Schedule").
+		SetProvider("").
+		SetCategory("Scheduler").
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("cron").
+				SetDescription("Set cron expression of the schedule").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("interval_seconds").
+				SetTitle("Interval (Seconds)").
+				SetDescription("Set fixed interval of the schedule, used when cron is not set").
+				SetMust(false).
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("time_zone").
+				SetDescription("Set time zone of the cron expression").
+				SetMust(false).
+				SetDefault("Local"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("jitter_seconds").
+				SetTitle("Jitter (Seconds)").
+				SetDescription("Set max random delay added to each run").
+				SetMust(false).
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("allow_overlap").
+				SetDescription("Set running even when the previous run is still in progress").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("metadata").
+				SetDescription("Set request metadata as json object").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("multilines").
+				SetName("data").
+				SetDescription("Set request data").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("response_channel").
+				SetDescription("Set Queue channel to send the target responses to").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("address").
+				SetTitle("KubeMQ gRPC Service Address").
+				SetDescription("Set Kubemq grpc endpoint address, required when response channel is set").
+				SetMust(false).
+				SetDefault("kubemq-cluster-grpc.kubemq:50000").
+				SetLoadedOptions("kubemq-address"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("client_id").
+				SetDescription("Set client id").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("auth_token").
+				SetDescription("Set authentication token").
+				SetMust(false).
+				SetDefault(""),
+		)
+}
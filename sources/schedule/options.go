@@ -0,0 +1,76 @@
+package schedule
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+type options struct {
+	schedule        cron.Schedule
+	expression      string
+	location        *time.Location
+	jitter          time.Duration
+	allowOverlap    bool
+	metadata        map[string]string
+	data            []byte
+	host            string
+	port            int
+	clientId        string
+	authToken       string
+	responseChannel string
+}
+
+func parseOptions(cfg config.Spec) (options, error) {
+	o := options{}
+	o.expression = cfg.Properties.ParseString("cron", "")
+	interval, err := cfg.Properties.ParseIntWithRange("interval_seconds", 0, 0, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing interval seconds value, %w", err)
+	}
+	switch {
+	case o.expression != "" && interval > 0:
+		return options{}, fmt.Errorf("cron and interval seconds cannot be both set")
+	case o.expression != "":
+		o.schedule, err = cronParser.Parse(o.expression)
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing cron value, %w", err)
+		}
+	case interval > 0:
+		o.expression = fmt.Sprintf("@every %ds", interval)
+		o.schedule = cron.Every(time.Duration(interval) * time.Second)
+	default:
+		return options{}, fmt.Errorf("one of cron or interval seconds must be set")
+	}
+	o.location, err = time.LoadLocation(cfg.Properties.ParseString("time_zone", "Local"))
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing time zone value, %w", err)
+	}
+	jitter, err := cfg.Properties.ParseIntWithRange("jitter_seconds", 0, 0, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing jitter seconds value, %w", err)
+	}
+	o.jitter = time.Duration(jitter) * time.Second
+	o.allowOverlap = cfg.Properties.ParseBool("allow_overlap", false)
+	o.metadata, err = cfg.Properties.MustParseJsonMap("metadata")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing metadata value, %w", err)
+	}
+	o.data = []byte(cfg.Properties.ParseString("data", ""))
+	o.responseChannel = cfg.Properties.ParseString("response_channel", "")
+	if o.responseChannel != "" {
+		o.host, o.port, err = cfg.Properties.MustParseAddress("address", "")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing address value, %w", err)
+		}
+		o.clientId = cfg.Properties.ParseString("client_id", uuid.New().String())
+		o.authToken = cfg.Properties.ParseString("auth_token", "")
+	}
+	return o, nil
+}
@@ -0,0 +1,159 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_parseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Spec
+		wantErr bool
+	}{
+		{
+			name: "valid options - cron",
+			cfg: config.Spec{
+				Name: "schedule",
+				Kind: "schedule",
+				Properties: map[string]string{
+					"cron":     "*/5 * * * *",
+					"metadata": `{"method":"list"}`,
+					"data":     "some-data",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid options - cron with seconds and time zone",
+			cfg: config.Spec{
+				Name: "schedule",
+				Kind: "schedule",
+				Properties: map[string]string{
+					"cron":      "30 0 3 * * *",
+					"time_zone": "UTC",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid options - interval with response channel",
+			cfg: config.Spec{
+				Name: "schedule",
+				Kind: "schedule",
+				Properties: map[string]string{
+					"interval_seconds": "60",
+					"jitter_seconds":   "10",
+					"response_channel": "schedule.response",
+					"address":          "localhost:50000",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid options - no schedule",
+			cfg: config.Spec{
+				Name:       "schedule",
+				Kind:       "schedule",
+				Properties: map[string]string{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - cron and interval",
+			cfg: config.Spec{
+				Name: "schedule",
+				Kind: "schedule",
+				Properties: map[string]string{
+					"cron":             "@hourly",
+					"interval_seconds": "60",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad cron",
+			cfg: config.Spec{
+				Name: "schedule",
+				Kind: "schedule",
+				Properties: map[string]string{
+					"cron": "* * *",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad time zone",
+			cfg: config.Spec{
+				Name: "schedule",
+				Kind: "schedule",
+				Properties: map[string]string{
+					"cron":      "@daily",
+					"time_zone": "Bad/Zone",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - bad metadata",
+			cfg: config.Spec{
+				Name: "schedule",
+				Kind: "schedule",
+				Properties: map[string]string{
+					"cron":     "@daily",
+					"metadata": "bad-json",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid options - response channel without address",
+			cfg: config.Spec{
+				Name: "schedule",
+				Kind: "schedule",
+				Properties: map[string]string{
+					"cron":             "@daily",
+					"response_channel": "schedule.response",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOptions_schedule(t *testing.T) {
+	o, err := parseOptions(config.Spec{
+		Name: "schedule",
+		Kind: "schedule",
+		Properties: map[string]string{
+			"cron":      "0 3 * * *",
+			"time_zone": "America/New_York",
+		},
+	})
+	require.NoError(t, err)
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	require.EqualValues(t, time.Date(2023, 1, 2, 8, 0, 0, 0, time.UTC), o.schedule.Next(now.In(o.location)).UTC())
+
+	o, err = parseOptions(config.Spec{
+		Name: "schedule",
+		Kind: "schedule",
+		Properties: map[string]string{
+			"interval_seconds": "90",
+		},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, now.Add(90*time.Second), o.schedule.Next(now))
+}
@@ -11,8 +11,12 @@ import (
 	"github.com/kubemq-io/kubemq-targets/sources/command"
 	"github.com/kubemq-io/kubemq-targets/sources/events"
 	events_store "github.com/kubemq-io/kubemq-targets/sources/events-store"
+	"github.com/kubemq-io/kubemq-targets/sources/filesystem"
+	"github.com/kubemq-io/kubemq-targets/sources/imap"
+	"github.com/kubemq-io/kubemq-targets/sources/mongodb"
 	"github.com/kubemq-io/kubemq-targets/sources/query"
 	"github.com/kubemq-io/kubemq-targets/sources/queue"
+	"github.com/kubemq-io/kubemq-targets/sources/schedule"
 )
 
 type Source interface {
@@ -54,6 +58,30 @@ func Init(ctx context.Context, cfg config.Spec, bindingName string, log *logger.
 			return nil, err
 		}
 		return source, nil
+	case "email.imap":
+		source := imap.New()
+		if err := source.Init(ctx, cfg, bindingName, log); err != nil {
+			return nil, err
+		}
+		return source, nil
+	case "schedule":
+		source := schedule.New()
+		if err := source.Init(ctx, cfg, bindingName, log); err != nil {
+			return nil, err
+		}
+		return source, nil
+	case "storage.filesystem":
+		source := filesystem.New()
+		if err := source.Init(ctx, cfg, bindingName, log); err != nil {
+			return nil, err
+		}
+		return source, nil
+	case "stores.mongodb":
+		source := mongodb.New()
+		if err := source.Init(ctx, cfg, bindingName, log); err != nil {
+			return nil, err
+		}
+		return source, nil
 
 	default:
 		return nil, fmt.Errorf("invalid kind %s for source", cfg.Kind)
@@ -67,5 +95,9 @@ func Connectors() common.Connectors {
 		events.Connector(),
 		events_store.Connector(),
 		command.Connector(),
+		imap.Connector(),
+		schedule.Connector(),
+		filesystem.Connector(),
+		mongodb.Connector(),
 	}
 }
@@ -5,9 +5,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kubemq-hub/builder/connector/common"
+	"github.com/kubemq-io/kubemq-targets/pkg/elasticengine"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
 	"github.com/kubemq-io/kubemq-targets/types"
 
@@ -16,10 +18,16 @@ import (
 	"github.com/kubemq-io/kubemq-targets/config"
 )
 
+// maxEngines caps the cached engines, the engines of requests to further domains are closed after the request
+const maxEngines = 16
+
 type Client struct {
 	log    *logger.Logger
 	opts   options
 	signer *signer.Signer
+	mu     sync.Mutex
+	// engines are the elastic engines of the requests domains, keyed by domain, region and service
+	engines map[string]*elasticengine.Engine
 }
 
 func New() *Client {
@@ -44,6 +52,7 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, log *logger.Logger)
 
 	signer := signer.NewSigner(credentials.NewStaticCredentials(c.opts.awsKey, c.opts.awsSecretKey, c.opts.token))
 	c.signer = signer
+	c.engines = map[string]*elasticengine.Engine{}
 
 	return nil
 }
@@ -53,6 +62,14 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 	if err != nil {
 		return nil, err
 	}
+	if meta.isEngine() {
+		engine, release, err := c.getEngine(ctx, meta)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return engine.Do(ctx, req)
+	}
 	httpClient := &http.Client{}
 	reader := strings.NewReader(meta.json)
 	request, err := http.NewRequestWithContext(ctx, meta.method, meta.endpoint, reader)
@@ -80,6 +97,50 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 		nil
 }
 
+// getEngine returns the elastic engine of the request domain, the engine requests are signed with the request region and service.
+// The engine connects outside of the lock, when the cache is full the engine is not cached and release closes it
+func (c *Client) getEngine(ctx context.Context, meta metadata) (*elasticengine.Engine, func(), error) {
+	key := meta.domain + "|" + meta.region + "|" + meta.service
+	c.mu.Lock()
+	engine, ok := c.engines[key]
+	c.mu.Unlock()
+	if ok {
+		return engine, func() {}, nil
+	}
+	engine, err := elasticengine.New(ctx, elasticengine.Options{
+		URLs:  []string{meta.domain},
+		Sniff: false,
+		HTTPClient: &http.Client{
+			Transport: &signingTransport{
+				signer:  c.signer,
+				region:  meta.region,
+				service: meta.service,
+				base:    http.DefaultTransport,
+			},
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.engines[key]; ok {
+		_ = engine.Close()
+		return cached, func() {}, nil
+	}
+	if len(c.engines) >= maxEngines {
+		return engine, func() { _ = engine.Close() }, nil
+	}
+	c.engines[key] = engine
+	return engine, func() {}, nil
+}
+
 func (c *Client) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, engine := range c.engines {
+		_ = engine.Close()
+		delete(c.engines, key)
+	}
 	return nil
 }
@@ -2,7 +2,13 @@ package elasticsearch
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -281,3 +287,109 @@ func TestClient_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Do_Engine(t *testing.T) {
+	var mu sync.Mutex
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"version":{"number":"7.10.2"}}`))
+		case "/log/_count":
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"query":{"term":{"level":"error"}}}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"count":3}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := New()
+	err := c.Init(ctx, config.Spec{
+		Name: "aws-elasticsearch",
+		Kind: "aws.elasticsearch",
+		Properties: map[string]string{
+			"aws_key":        "some-key",
+			"aws_secret_key": "some-secret-key",
+		},
+	}, nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = c.Stop()
+	}()
+	resp, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "count").
+		SetMetadataKeyValue("region", "us-west-2").
+		SetMetadataKeyValue("domain", server.URL).
+		SetMetadataKeyValue("index", "log").
+		SetData([]byte(`{"query":{"term":{"level":"error"}}}`)))
+	require.NoError(t, err)
+	require.Equal(t, "3", resp.Metadata["count"])
+	mu.Lock()
+	for _, authorization := range authorizations {
+		require.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=some-key/"), authorization)
+		require.Contains(t, authorization, "/us-west-2/es/aws4_request")
+	}
+	mu.Unlock()
+
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "search").
+		SetMetadataKeyValue("domain", server.URL))
+	require.Error(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "update_by_query").
+		SetMetadataKeyValue("region", "us-west-2").
+		SetMetadataKeyValue("domain", server.URL))
+	require.Error(t, err)
+}
+
+func TestClient_getEngine(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version":{"number":"7.10.2"}}`))
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := New()
+	err := c.Init(ctx, config.Spec{
+		Name: "aws-elasticsearch",
+		Kind: "aws.elasticsearch",
+		Properties: map[string]string{
+			"aws_key":        "some-key",
+			"aws_secret_key": "some-secret-key",
+		},
+	}, nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = c.Stop()
+	}()
+	meta := metadata{domain: server.URL, region: "us-west-2", service: "es"}
+	first, release, err := c.getEngine(ctx, meta)
+	require.NoError(t, err)
+	release()
+	cached, release, err := c.getEngine(ctx, meta)
+	require.NoError(t, err)
+	release()
+	require.Same(t, first, cached)
+
+	for i := 1; i < maxEngines; i++ {
+		_, release, err := c.getEngine(ctx, metadata{domain: server.URL, region: fmt.Sprintf("region-%d", i), service: "es"})
+		require.NoError(t, err)
+		release()
+	}
+	require.Len(t, c.engines, maxEngines)
+	_, release, err = c.getEngine(ctx, metadata{domain: server.URL, region: "region-extra", service: "es"})
+	require.NoError(t, err)
+	release()
+	require.Len(t, c.engines, maxEngines)
+}
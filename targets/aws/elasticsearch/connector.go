@@ -1,6 +1,8 @@
 package elasticsearch
 
 import (
+	"math"
+
 	"github.com/kubemq-hub/builder/connector/common"
 )
 
@@ -40,8 +42,8 @@ func Connector() *common.Connector {
 			common.NewMetadata().
 				SetName("method").
 				SetKind("string").
-				SetDescription("Set Elastic Search execution method").
-				SetOptions([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}).
+				SetDescription("Set Elastic Search http method or execution method").
+				SetOptions([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "get", "set", "delete", "index.exists", "index.create", "index.delete", "search", "count", "bulk", "update_by_query", "delete_by_query", "template.create", "template.get", "template.delete", "alias.create", "alias.delete", "alias.get"}).
 				SetDefault("GET").
 				SetMust(true),
 		).
@@ -66,7 +68,7 @@ func Connector() *common.Connector {
 				SetKind("string").
 				SetName("index").
 				SetDescription("Set Elastic Search index").
-				SetMust(true).
+				SetMust(false).
 				SetDefault(""),
 		).
 		AddMetadata(
@@ -74,7 +76,7 @@ func Connector() *common.Connector {
 				SetKind("string").
 				SetName("endpoint").
 				SetDescription("Set Elastic Search endpoint").
-				SetMust(true).
+				SetMust(false).
 				SetDefault(""),
 		).
 		AddMetadata(
@@ -82,7 +84,7 @@ func Connector() *common.Connector {
 				SetKind("string").
 				SetName("id").
 				SetDescription("Set Elastic Search id").
-				SetMust(true).
+				SetMust(false).
 				SetDefault(""),
 		).
 		AddMetadata(
@@ -100,5 +102,83 @@ func Connector() *common.Connector {
 				SetDescription("Set Elastic Search service").
 				SetMust(false).
 				SetDefault("es"),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("name").
+				SetKind("string").
+				SetDescription("Set Elastic Search index template name").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("alias").
+				SetKind("string").
+				SetDescription("Set Elastic Search index alias").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("size").
+				SetKind("int").
+				SetDescription("Set search page size").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("from").
+				SetKind("int").
+				SetDescription("Set search hits offset").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("search_after").
+				SetKind("string").
+				SetDescription("Set search after sort values as json array").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("scroll").
+				SetKind("string").
+				SetDescription("Set search scroll keep alive").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("scroll_id").
+				SetKind("string").
+				SetDescription("Set search scroll id of the next page").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("refresh").
+				SetKind("string").
+				SetDescription("Set refresh after write").
+				SetOptions([]string{"true", "false", "wait_for"}).
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("conflicts").
+				SetKind("string").
+				SetDescription("Set by query methods version conflicts handling").
+				SetOptions([]string{"abort", "proceed"}).
+				SetDefault("").
+				SetMust(false),
 		)
 }
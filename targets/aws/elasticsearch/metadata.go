@@ -3,6 +3,7 @@ package elasticsearch
 import (
 	"fmt"
 
+	"github.com/kubemq-io/kubemq-targets/pkg/elasticengine"
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
@@ -32,11 +33,17 @@ var httpMethodsMap = map[string]string{
 	"OPTIONS": "OPTIONS",
 }
 
+// isEngine reports whether the request method is executed by the elastic engine instead of a signed http request
+func (m metadata) isEngine() bool {
+	_, ok := httpMethodsMap[m.method]
+	return !ok
+}
+
 func parseMetadata(meta types.Metadata) (metadata, error) {
 	m := metadata{}
 	var err error
-	m.method, err = meta.ParseStringMap("method", httpMethodsMap)
-	if err != nil {
+	m.method = meta.ParseString("method", "")
+	if _, ok := httpMethodsMap[m.method]; !ok && !elasticengine.IsMethod(m.method) {
 		return metadata{}, meta.GetValidMethodTypes(httpMethodsMap)
 	}
 	m.region, err = meta.MustParseString("region")
@@ -47,6 +54,11 @@ func parseMetadata(meta types.Metadata) (metadata, error) {
 	if err != nil {
 		return metadata{}, fmt.Errorf("error failed to parse domain , %w", err)
 	}
+	m.service = meta.ParseString("service", DefaultService)
+	// the engine methods parse the rest of the metadata
+	if m.isEngine() {
+		return m, nil
+	}
 	m.index, err = meta.MustParseString("index")
 	if err != nil {
 		return metadata{}, fmt.Errorf("error failed to parse index , %w", err)
@@ -67,6 +79,5 @@ func parseMetadata(meta types.Metadata) (metadata, error) {
 			return metadata{}, fmt.Errorf("error failed to parse json , %w", err)
 		}
 	}
-	m.service = meta.ParseString("service", DefaultService)
 	return m, nil
 }
@@ -0,0 +1,39 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	signer "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+// signingTransport signs the elasticsearch client requests with the aws signature
+type signingTransport struct {
+	signer  *signer.Signer
+	region  string
+	service string
+	base    http.RoundTripper
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed := req.Clone(req.Context())
+	var body io.ReadSeeker
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > 0 {
+			body = bytes.NewReader(data)
+		}
+		signed.ContentLength = int64(len(data))
+	}
+	_, err := t.signer.Sign(signed, body, t.service, t.region, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(signed)
+}
@@ -6,11 +6,14 @@ import (
 	"testing"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/stretchr/testify/require"
 )
 
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
 type post struct {
 	Id        int64  `json:"id"`
 	Title     string `json:"title,omitempty"`
@@ -80,6 +83,11 @@ const (
 	selectPostTable = `SELECT id,title,content,bignumber,boolvalue FROM post;`
 )
 
+var createPostTableResponse = types.NewResponse().
+	SetData([]byte(`[{"statement":0,"rows_affected":0,"last_insert_id":0},{"statement":1,"rows_affected":0,"last_insert_id":0},{"statement":2,"rows_affected":2,"last_insert_id":0}]`)).
+	SetMetadataKeyValue("rows_affected", "2").
+	SetMetadataKeyValue("result", "ok")
+
 func TestClient_Init(t *testing.T) {
 	dat, err := getTestStructure()
 	require.NoError(t, err)
@@ -232,8 +240,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte(selectPostTable)),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok").
 				SetData(allPosts.marshal()),
@@ -300,8 +307,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -324,8 +330,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("some bad query")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -348,8 +353,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("SELECT id,title,content FROM post where id=100")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok"),
 			wantExecErr:  false,
@@ -373,8 +377,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte(selectPostTable)),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok").
 				SetData(allPosts.marshal()),
@@ -440,8 +443,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query"),
 
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
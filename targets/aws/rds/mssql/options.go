@@ -2,25 +2,14 @@ package mssql
 
 import (
 	"fmt"
-	"math"
 
 	"github.com/kubemq-io/kubemq-targets/config"
-)
-
-const (
-	defaultMaxIdleConnections           = 10
-	defaultMaxOpenConnections           = 100
-	defaultConnectionMaxLifetimeSeconds = 3600
+	"github.com/kubemq-io/kubemq-targets/pkg/sqlengine"
 )
 
 type options struct {
 	connection string
-	// maxIdleConnections sets the maximum number of connections in the idle connection pool
-	maxIdleConnections int
-	// maxOpenConnections sets the maximum number of open connections to the database.
-	maxOpenConnections int
-	// connectionMaxLifetimeSeconds sets the maximum amount of time a connection may be reused.
-	connectionMaxLifetimeSeconds int
+	engine     sqlengine.Options
 }
 
 func parseOptions(cfg config.Spec) (options, error) {
@@ -30,18 +19,9 @@ func parseOptions(cfg config.Spec) (options, error) {
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing connection string, %w", err)
 	}
-
-	o.maxIdleConnections, err = cfg.Properties.ParseIntWithRange("max_idle_connections", defaultMaxIdleConnections, 1, math.MaxInt32)
-	if err != nil {
-		return options{}, fmt.Errorf("error parsing max_idle_connections value, %w", err)
-	}
-	o.maxOpenConnections, err = cfg.Properties.ParseIntWithRange("max_open_connections", defaultMaxOpenConnections, 1, math.MaxInt32)
-	if err != nil {
-		return options{}, fmt.Errorf("error parsing max_open_connections value, %w", err)
-	}
-	o.connectionMaxLifetimeSeconds, err = cfg.Properties.ParseIntWithRange("connection_max_lifetime_seconds", defaultConnectionMaxLifetimeSeconds, 1, math.MaxInt32)
+	o.engine, err = sqlengine.ParseOptions(cfg.Properties)
 	if err != nil {
-		return options{}, fmt.Errorf("error parsing connection_max_lifetime_seconds seconds value, %w", err)
+		return options{}, err
 	}
 	return o, nil
 }
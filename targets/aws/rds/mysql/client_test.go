@@ -6,11 +6,14 @@ import (
 	"testing"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/stretchr/testify/require"
 )
 
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
 type testStructure struct {
 	awsKey       string
 	awsSecretKey string
@@ -112,6 +115,11 @@ const (
 	selectPostTable = `SELECT id,title,content,bignumber,boolvalue FROM post;`
 )
 
+var createPostTableResponse = types.NewResponse().
+	SetData([]byte(`[{"statement":0,"rows_affected":0,"last_insert_id":0},{"statement":1,"rows_affected":0,"last_insert_id":0},{"statement":2,"rows_affected":2,"last_insert_id":0}]`)).
+	SetMetadataKeyValue("rows_affected", "2").
+	SetMetadataKeyValue("result", "ok")
+
 func TestClient_Init(t *testing.T) {
 	dat, err := getTestStructure()
 	require.NoError(t, err)
@@ -266,8 +274,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte(selectPostTable)),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok").
 				SetData(allPosts.marshal()),
@@ -343,8 +350,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -370,8 +376,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("some bad query")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -397,8 +402,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("SELECT id,title,content FROM post where id=100")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok"),
 			wantExecErr:  false,
@@ -425,8 +429,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte(selectPostTable)),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok").
 				SetData(allPosts.marshal()),
@@ -501,8 +504,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query"),
 
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -115,12 +115,34 @@ func Connector() *common.Connector {
 				SetMin(1).
 				SetMax(math.MaxInt32),
 		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("connection_max_idle_time_seconds").
+				SetTitle("Connection Idle Time (Seconds)").
+				SetDescription("Set MySQL connection max idle time seconds, 0 for no limit").
+				SetMust(false).
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("statement_timeout_seconds").
+				SetTitle("Statement Timeout (Seconds)").
+				SetDescription("Set MySQL default request timeout seconds, 0 for no timeout").
+				SetMust(false).
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32),
+		).
 		AddMetadata(
 			common.NewMetadata().
 				SetName("method").
 				SetKind("string").
 				SetDescription("Set MySql execution method").
-				SetOptions([]string{"query", "exec", "transaction"}).
+				SetOptions([]string{"query", "exec", "transaction", "bulk_insert", "health"}).
 				SetDefault("query").
 				SetMust(true),
 		).
@@ -132,5 +154,50 @@ func Connector() *common.Connector {
 				SetOptions([]string{"Default", "ReadUncommitted", "ReadCommitted", "RepeatableRead", "Serializable"}).
 				SetDefault("Default").
 				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("read_only").
+				SetKind("bool").
+				SetDescription("Set MySQL read only transaction").
+				SetDefault("false").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("timeout_seconds").
+				SetKind("int").
+				SetDescription("Set MySQL request timeout seconds, overrides the statement timeout").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("result_format").
+				SetKind("string").
+				SetDescription("Set MySql query result format").
+				SetOptions([]string{"json", "ndjson", "csv"}).
+				SetDefault("json").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("max_rows").
+				SetKind("int").
+				SetDescription("Set MySql query max rows per response, 0 for no limit").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("cursor").
+				SetKind("string").
+				SetDescription("Set MySql query cursor of the next page").
+				SetDefault("").
+				SetMust(false),
 		)
 }
@@ -2,9 +2,9 @@ package postgres
 
 import (
 	"fmt"
-	"math"
 
 	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/pkg/sqlengine"
 )
 
 const (
@@ -26,12 +26,7 @@ type options struct {
 	dbUser   string
 	endPoint string
 
-	// maxIdleConnections sets the maximum number of connections in the idle connection pool
-	maxIdleConnections int
-	// maxOpenConnections sets the maximum number of open connections to the database.
-	maxOpenConnections int
-	// connectionMaxLifetimeSeconds sets the maximum amount of time a connection may be reused.
-	connectionMaxLifetimeSeconds int
+	engine sqlengine.Options
 }
 
 func parseOptions(cfg config.Spec) (options, error) {
@@ -71,17 +66,9 @@ func parseOptions(cfg config.Spec) (options, error) {
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing end_point , %w", err)
 	}
-	o.maxIdleConnections, err = cfg.Properties.ParseIntWithRange("max_idle_connections", defaultMaxIdleConnections, 1, math.MaxInt32)
+	o.engine, err = sqlengine.ParseOptions(cfg.Properties)
 	if err != nil {
-		return options{}, fmt.Errorf("error parsing max idle connections value, %w", err)
-	}
-	o.maxOpenConnections, err = cfg.Properties.ParseIntWithRange("max_open_connections", defaultMaxOpenConnections, 1, math.MaxInt32)
-	if err != nil {
-		return options{}, fmt.Errorf("error parsing max open connections value, %w", err)
-	}
-	o.connectionMaxLifetimeSeconds, err = cfg.Properties.ParseIntWithRange("connection_max_lifetime_seconds", defaultConnectionMaxLifetimeSeconds, 1, math.MaxInt32)
-	if err != nil {
-		return options{}, fmt.Errorf("error parsing connection max lifetime seconds value, %w", err)
+		return options{}, err
 	}
 	return o, nil
 }
@@ -6,11 +6,14 @@ import (
 	"testing"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/stretchr/testify/require"
 )
 
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
 type post struct {
 	Id      string `json:"id"`
 	Title   string `json:"title,omitempty"`
@@ -60,6 +63,11 @@ const (
 	selectPostTable = `SELECT id,title,content FROM post;`
 )
 
+var createPostTableResponse = types.NewResponse().
+	SetData([]byte(`[{"statement":0,"rows_affected":0},{"statement":1,"rows_affected":0},{"statement":2,"rows_affected":2}]`)).
+	SetMetadataKeyValue("rows_affected", "2").
+	SetMetadataKeyValue("result", "ok")
+
 type testStructure struct {
 	connection string
 }
@@ -225,8 +233,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte(selectPostTable)),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok").
 				SetData(allPosts.marshal()),
@@ -292,8 +299,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -316,8 +322,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("some bad query")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -340,8 +345,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("SELECT id,title,content FROM post where id=100")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok"),
 			wantExecErr:  false,
@@ -365,8 +369,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte(selectPostTable)),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok").
 				SetData(allPosts.marshal()),
@@ -432,8 +435,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
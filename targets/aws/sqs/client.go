@@ -13,6 +13,7 @@ import (
 	"github.com/kubemq-hub/builder/connector/common"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
@@ -67,6 +68,14 @@ func (c *Client) Do(ctx context.Context, request *types.Request) (*types.Respons
 	}
 	m := &sqs.SendMessageInput{}
 	c.setMessageMeta(m, eventMetadata)
+	attributes := attributesCarrier{}
+	for key, value := range m.MessageAttributes {
+		attributes[key] = value
+	}
+	tracing.Inject(ctx, attributes)
+	if len(attributes) > 0 {
+		m.SetMessageAttributes(attributes)
+	}
 	m.SetMessageBody(string(request.Data))
 	tries := 0
 	for tries <= c.opts.retries {
@@ -36,3 +36,28 @@ func parseMetadata(meta types.Metadata, opts options) (metadata, error) {
 	}
 	return m, nil
 }
+
+// attributesCarrier adapts sqs message attributes to a trace context carrier
+type attributesCarrier map[string]*sqs.MessageAttributeValue
+
+func (ac attributesCarrier) Get(key string) string {
+	if val, ok := ac[key]; ok && val.StringValue != nil {
+		return *val.StringValue
+	}
+	return ""
+}
+
+func (ac attributesCarrier) Set(key, value string) {
+	ac[key] = &sqs.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (ac attributesCarrier) Keys() []string {
+	var keys []string
+	for key := range ac {
+		keys = append(keys, key)
+	}
+	return keys
+}
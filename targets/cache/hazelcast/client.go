@@ -5,20 +5,34 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hazelcast/hazelcast-go-client"
 	hazelconfig "github.com/hazelcast/hazelcast-go-client/config"
+	"github.com/hazelcast/hazelcast-go-client/core"
 	"github.com/kubemq-hub/builder/connector/common"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
+const (
+	// lockGuardTimeout and lockGuardLease bound the key lock guarding the lock methods check and update
+	lockGuardTimeout = 5 * time.Second
+	lockGuardLease   = 10 * time.Second
+)
+
 // Client is a Client state store
 type Client struct {
 	log    *logger.Logger
 	client hazelcast.Client
 	opts   options
+	// lockMu serializes the lock methods of this client, hazelcast key locks are reentrant for the whole client
+	lockMu sync.Mutex
 }
 
 func New() *Client {
@@ -66,7 +80,12 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 		return c.set(meta, req.Data)
 	case "delete":
 		return c.delete(meta)
-
+	case "lock":
+		return c.lock(meta)
+	case "unlock":
+		return c.unlock(meta)
+	case "renew":
+		return c.renew(meta)
 	}
 	return nil, nil
 }
@@ -136,6 +155,141 @@ func (c *Client) delete(meta metadata) (*types.Response, error) {
 		SetMetadataKeyValue("key", meta.key), nil
 }
 
+// guardLock locks the lock key of the map for the check and update of a lock method
+func (c *Client) guardLock(Map core.Map, key string) (func(), error) {
+	c.lockMu.Lock()
+	locked, err := Map.TryLockWithTimeoutAndLease(key, lockGuardTimeout, lockGuardLease)
+	if err != nil || !locked {
+		c.lockMu.Unlock()
+		if err == nil {
+			err = fmt.Errorf("timeout waiting for key %s", key)
+		}
+		return nil, err
+	}
+	return func() {
+		_ = Map.Unlock(key)
+		c.lockMu.Unlock()
+	}, nil
+}
+
+// lockValue is the map entry value of a lock, the lock ttl is kept with the token so a renew extends the lock by it
+func lockValue(token string, ttl time.Duration) string {
+	return fmt.Sprintf("%d:%s", int64(ttl/time.Second), token)
+}
+
+// lockHolder returns the token and ttl of the lock key holder
+func lockHolder(Map core.Map, key string) (string, time.Duration, bool, error) {
+	v, err := Map.Get(key)
+	if err != nil {
+		return "", 0, false, err
+	}
+	if v == nil {
+		return "", 0, false, nil
+	}
+	value, _ := v.(string)
+	ttlValue, token, found := strings.Cut(value, ":")
+	ttl, err := strconv.ParseInt(ttlValue, 10, 64)
+	if !found || err != nil {
+		return "", 0, true, fmt.Errorf("key %s holds a value which is not a lock", key)
+	}
+	return token, time.Duration(ttl) * time.Second, true, nil
+}
+
+// lock acquires the lock key for a new token, a request with the token of the lock owner renews the lock
+func (c *Client) lock(meta metadata) (*types.Response, error) {
+	if meta.token != "" {
+		return c.renew(meta)
+	}
+	Map, err := c.client.GetMap(meta.mapName)
+	if err != nil {
+		return nil, err
+	}
+	release, err := c.guardLock(Map, meta.key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	_, _, held, err := lockHolder(Map, meta.key)
+	if err != nil {
+		return nil, err
+	}
+	if held {
+		return nil, fmt.Errorf("lock %s is held by another owner", meta.key)
+	}
+	meta.token = uuid.New().String()
+	expiresAt := time.Now().Add(meta.ttl)
+	err = Map.SetWithTTL(meta.key, lockValue(meta.token, meta.ttl), meta.ttl)
+	if err != nil {
+		return nil, err
+	}
+	return lockResponse(meta, expiresAt), nil
+}
+
+// unlock deletes the lock key when it is held by the request token
+func (c *Client) unlock(meta metadata) (*types.Response, error) {
+	Map, err := c.client.GetMap(meta.mapName)
+	if err != nil {
+		return nil, err
+	}
+	release, err := c.guardLock(Map, meta.key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	holder, _, held, err := lockHolder(Map, meta.key)
+	if err != nil {
+		return nil, err
+	}
+	if !held || holder != meta.token {
+		return nil, fmt.Errorf("lock %s is not held by this token", meta.key)
+	}
+	err = Map.Delete(meta.key)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("result", "ok").
+		SetMetadataKeyValue("token", meta.token).
+		SetMetadataKeyValue("key", meta.key), nil
+}
+
+// renew extends the lock key by its ttl when it is held by the request token, the ttl of a lock cannot be changed after it is created
+func (c *Client) renew(meta metadata) (*types.Response, error) {
+	Map, err := c.client.GetMap(meta.mapName)
+	if err != nil {
+		return nil, err
+	}
+	release, err := c.guardLock(Map, meta.key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	holder, ttl, held, err := lockHolder(Map, meta.key)
+	if err != nil {
+		return nil, err
+	}
+	if !held || holder != meta.token {
+		return nil, fmt.Errorf("lock %s is not held by this token", meta.key)
+	}
+	if meta.ttlSet && meta.ttl != ttl {
+		return nil, fmt.Errorf("lock %s ttl is %s, ttl_seconds cannot be changed after the lock is created", meta.key, ttl)
+	}
+	expiresAt := time.Now().Add(ttl)
+	err = Map.SetWithTTL(meta.key, lockValue(meta.token, ttl), ttl)
+	if err != nil {
+		return nil, err
+	}
+	return lockResponse(meta, expiresAt), nil
+}
+
+func lockResponse(meta metadata, expiresAt time.Time) *types.Response {
+	return types.NewResponse().
+		SetMetadataKeyValue("result", "ok").
+		SetMetadataKeyValue("token", meta.token).
+		SetMetadataKeyValue("expires_at", expiresAt.UTC().Format(time.RFC3339Nano)).
+		SetMetadataKeyValue("key", meta.key)
+}
+
 func setConfig(opts options) (*hazelconfig.Config, error) {
 	c := hazelcast.NewConfig()
 
@@ -186,3 +186,74 @@ func TestClient_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Lock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New()
+	err := c.Init(ctx, config.Spec{
+		Name: "hazelcast-target",
+		Kind: "hazelcast.target",
+		Properties: map[string]string{
+			"address": "localhost:5701",
+		},
+	}, nil)
+	require.NoError(t, err)
+	lockRequest := types.NewRequest().
+		SetMetadataKeyValue("method", "lock").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("map_name", "locks")
+	resp, err := c.Do(ctx, lockRequest)
+	require.NoError(t, err)
+	token := resp.Metadata["token"]
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, resp.Metadata["expires_at"])
+	_, err = c.Do(ctx, lockRequest)
+	require.Error(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "lock").
+		SetMetadataKeyValue("key", "other-lock").
+		SetMetadataKeyValue("map_name", "locks").
+		SetMetadataKeyValue("token", "caller-token"))
+	require.Error(t, err)
+
+	resp, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "renew").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("map_name", "locks").
+		SetMetadataKeyValue("token", token))
+	require.NoError(t, err)
+	require.Equal(t, token, resp.Metadata["token"])
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "renew").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("map_name", "locks").
+		SetMetadataKeyValue("token", token).
+		SetMetadataKeyValue("ttl_seconds", "60"))
+	require.Error(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "lock").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("map_name", "locks").
+		SetMetadataKeyValue("token", token).
+		SetMetadataKeyValue("ttl_seconds", "30"))
+	require.NoError(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "unlock").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("map_name", "locks").
+		SetMetadataKeyValue("token", "bad-token"))
+	require.Error(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "unlock").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("map_name", "locks").
+		SetMetadataKeyValue("token", token))
+	require.NoError(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "renew").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("map_name", "locks").
+		SetMetadataKeyValue("token", token))
+	require.Error(t, err)
+}
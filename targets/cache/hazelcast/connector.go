@@ -139,8 +139,26 @@ func Connector() *common.Connector {
 				SetName("method").
 				SetKind("string").
 				SetDescription("Set execution method").
-				SetOptions([]string{"get", "set", "delete", "get_list"}).
+				SetOptions([]string{"get", "set", "delete", "get_list", "lock", "unlock", "renew"}).
 				SetDefault("get").
 				SetMust(true),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("token").
+				SetDescription("Set lock owner token of a lock response, required for unlock and renew").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("int").
+				SetName("ttl_seconds").
+				SetDescription("Set lock ttl in seconds").
+				SetMust(false).
+				SetDefault("30").
+				SetMin(10).
+				SetMax(86400),
 		)
 }
@@ -2,13 +2,19 @@ package hazelcast
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
 const (
-	defaultListName = ""
-	defaultKeyName  = ""
+	defaultListName  = ""
+	defaultKeyName   = ""
+	defaultLockToken = ""
+	// lock ttl is limited to 10 seconds up to 24 hours, the same range as the other lock backends
+	defaultLockTTLSeconds = 30
+	minLockTTLSeconds     = 10
+	maxLockTTLSeconds     = 86400
 )
 
 var methodsMap = map[string]string{
@@ -16,6 +22,9 @@ var methodsMap = map[string]string{
 	"set":      "set",
 	"get_list": "get_list",
 	"delete":   "delete",
+	"lock":     "lock",
+	"unlock":   "unlock",
+	"renew":    "renew",
 }
 
 type metadata struct {
@@ -23,6 +32,10 @@ type metadata struct {
 	mapName  string
 	key      string
 	listName string
+	token    string
+	ttl      time.Duration
+	// ttlSet is set when the request sets ttl_seconds
+	ttlSet bool
 }
 
 func parseMetadata(meta types.Metadata) (metadata, error) {
@@ -42,5 +55,22 @@ func parseMetadata(meta types.Metadata) (metadata, error) {
 
 	m.listName = meta.ParseString("list_name", defaultListName)
 
+	switch m.method {
+	case "lock", "unlock", "renew":
+		if m.key == "" {
+			return metadata{}, fmt.Errorf("error on parsing key value, missing key")
+		}
+		m.token = meta.ParseString("token", defaultLockToken)
+		if m.method != "lock" && m.token == "" {
+			return metadata{}, fmt.Errorf("error on parsing token value, missing token")
+		}
+		ttl, err := meta.ParseIntWithRange("ttl_seconds", defaultLockTTLSeconds, minLockTTLSeconds, maxLockTTLSeconds)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error on parsing ttl_seconds value, %w", err)
+		}
+		m.ttl = time.Duration(ttl) * time.Second
+		_, m.ttlSet = meta["ttl_seconds"]
+	}
+
 	return m, nil
 }
@@ -14,21 +14,92 @@ func Connector() *common.Connector {
 		SetProvider("").
 		SetCategory("Cache").
 		SetTags("db").
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("mode").
+				SetTitle("Connection Mode").
+				SetDescription("Set Redis connection mode").
+				SetOptions([]string{"standalone", "cluster", "sentinel"}).
+				SetDefault("standalone").
+				SetMust(false),
+		).
 		AddProperty(
 			common.NewProperty().
 				SetKind("string").
 				SetName("url").
 				SetTitle("Connection String").
-				SetDescription("Set Redis url").
-				SetMust(true).
+				SetDescription("Set Redis url of a standalone connection").
+				SetMust(false).
 				SetDefault("redis://redis.host:6379"),
 		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("addresses").
+				SetTitle("Addresses").
+				SetDescription("Set Redis cluster nodes or sentinel addresses, comma separated").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("master_name").
+				SetTitle("Sentinel Master Name").
+				SetDescription("Set Redis sentinel master name").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("username").
+				SetDescription("Set Redis cluster or sentinel username").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("password").
+				SetDescription("Set Redis cluster or sentinel password").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("db").
+				SetDescription("Set Redis sentinel database").
+				SetMust(false).
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt16),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("tls").
+				SetDescription("Set Redis cluster or sentinel tls connection").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("allowed_commands").
+				SetTitle("Allowed Commands").
+				SetDescription("Set Redis commands allowed for the command method, comma separated, * allows all").
+				SetMust(false).
+				SetDefault(""),
+		).
 		AddMetadata(
 			common.NewMetadata().
 				SetName("method").
 				SetKind("string").
 				SetDescription("Set Redis execution method").
-				SetOptions([]string{"get", "set", "delete"}).
+				SetOptions([]string{"get", "set", "delete", "mget", "mset", "incr", "decr", "hset", "hget", "hgetall", "hdel", "lpush", "rpush", "lpop", "rpop", "lrange", "sadd", "srem", "smembers", "sismember", "zadd", "zrem", "zrange", "zrangebyscore", "xadd", "xrange", "publish", "command", "lock", "unlock", "renew"}).
 				SetDefault("get").
 				SetMust(true),
 		).
@@ -37,7 +108,7 @@ func Connector() *common.Connector {
 				SetName("key").
 				SetKind("string").
 				SetDescription("Set Redis key").
-				SetMust(true),
+				SetMust(false),
 		).
 		AddMetadata(
 			common.NewMetadata().
@@ -66,5 +137,125 @@ func Connector() *common.Connector {
 				SetOptions([]string{"strong", "eventual", ""}).
 				SetDefault("").
 				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("ttl_seconds").
+				SetKind("int").
+				SetDescription("Set Redis key expiry in seconds, 0 keeps the key, lock ttl is 10 to 86400 seconds, default 30").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("field").
+				SetKind("string").
+				SetDescription("Set Redis hash field for hget and hdel").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("member").
+				SetKind("string").
+				SetDescription("Set Redis set member for sismember").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("value").
+				SetKind("int").
+				SetDescription("Set Redis incr and decr amount").
+				SetDefault("1").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("start").
+				SetKind("string").
+				SetDescription("Set Redis lrange and zrange start index or xrange start id").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("stop").
+				SetKind("string").
+				SetDescription("Set Redis lrange and zrange stop index").
+				SetDefault("-1").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("end").
+				SetKind("string").
+				SetDescription("Set Redis xrange end id").
+				SetDefault("+").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("min").
+				SetKind("string").
+				SetDescription("Set Redis zrangebyscore min score").
+				SetDefault("-inf").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("max").
+				SetKind("string").
+				SetDescription("Set Redis zrangebyscore max score").
+				SetDefault("+inf").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("count").
+				SetKind("int").
+				SetDescription("Set Redis zrangebyscore and xrange results limit, 0 returns all").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("id").
+				SetKind("string").
+				SetDescription("Set Redis xadd message id").
+				SetDefault("*").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("max_len").
+				SetKind("int").
+				SetDescription("Set Redis xadd stream approximate max length, 0 is unlimited").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("token").
+				SetKind("string").
+				SetDescription("Set Redis lock owner token, required for unlock and renew").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("channel").
+				SetKind("string").
+				SetDescription("Set Redis publish channel").
+				SetDefault("").
+				SetMust(false),
 		)
 }
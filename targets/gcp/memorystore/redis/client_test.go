@@ -37,6 +37,29 @@ func TestClient_Init(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid init - cluster with no addresses",
+			cfg: config.Spec{
+				Name: "gcp-redis",
+				Kind: "gcp-cache-redis",
+				Properties: map[string]string{
+					"mode": "cluster",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid init - sentinel with no master name",
+			cfg: config.Spec{
+				Name: "gcp-redis",
+				Kind: "gcp-cache-redis",
+				Properties: map[string]string{
+					"mode":      "sentinel",
+					"addresses": "localhost:26379",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid init - error",
 			cfg: config.Spec{
@@ -139,7 +162,9 @@ func TestClient_Set_Get(t *testing.T) {
 			}
 			require.NoError(t, err)
 			require.NotNil(t, gotSetResponse)
-			require.EqualValues(t, tt.wantSetResponse, gotSetResponse)
+			require.EqualValues(t, tt.wantSetResponse.Metadata["key"], gotSetResponse.Metadata["key"])
+			require.EqualValues(t, tt.wantSetResponse.Metadata["result"], gotSetResponse.Metadata["result"])
+			require.NotEmpty(t, gotSetResponse.Metadata["etag"])
 			gotGetResponse, err := c.Do(ctx, tt.getRequest)
 			if tt.wantGetErr {
 				require.Error(t, err)
@@ -147,7 +172,9 @@ func TestClient_Set_Get(t *testing.T) {
 			}
 			require.NoError(t, err)
 			require.NotNil(t, gotGetResponse)
-			require.EqualValues(t, tt.wantGetResponse, gotGetResponse)
+			require.EqualValues(t, tt.wantGetResponse.Data, gotGetResponse.Data)
+			require.EqualValues(t, tt.wantGetResponse.Metadata["key"], gotGetResponse.Metadata["key"])
+			require.EqualValues(t, gotSetResponse.Metadata["etag"], gotGetResponse.Metadata["etag"])
 		})
 	}
 }
@@ -261,6 +288,34 @@ func TestClient_Do(t *testing.T) {
 				SetData([]byte("some-data")),
 			wantErr: true,
 		},
+		{
+			name: "invalid request - publish with no channel",
+			cfg: config.Spec{
+				Name: "redis",
+				Kind: "redis",
+				Properties: map[string]string{
+					"url": "redis://localhost:6379",
+				},
+			},
+			request: types.NewRequest().
+				SetMetadataKeyValue("method", "publish").
+				SetData([]byte("some-data")),
+			wantErr: true,
+		},
+		{
+			name: "invalid request - command not allowed",
+			cfg: config.Spec{
+				Name: "redis",
+				Kind: "redis",
+				Properties: map[string]string{
+					"url": "redis://localhost:6379",
+				},
+			},
+			request: types.NewRequest().
+				SetMetadataKeyValue("method", "command").
+				SetData([]byte(`["FLUSHALL"]`)),
+			wantErr: true,
+		},
 		{
 			name: "invalid request - bad concurrency",
 			cfg: config.Spec{
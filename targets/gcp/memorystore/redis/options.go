@@ -1,23 +1,21 @@
 package redis
 
 import (
-	"fmt"
-
 	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/pkg/redisengine"
 )
 
 type options struct {
-	url string
+	// engine holds the connection mode settings
+	engine redisengine.Options
 }
 
 func parseOptions(cfg config.Spec) (options, error) {
-	o := options{
-		url: "",
-	}
+	o := options{}
 	var err error
-	o.url, err = cfg.Properties.MustParseString("url")
+	o.engine, err = redisengine.ParseOptions(cfg.Properties)
 	if err != nil {
-		return options{}, fmt.Errorf("error parsing url, %w", err)
+		return options{}, err
 	}
 	return o, nil
 }
@@ -12,7 +12,9 @@ import (
 	"github.com/kubemq-hub/builder/connector/common"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/types"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 type Client struct {
@@ -73,6 +75,7 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 	httpReq := c.client.R().
 		SetHeaders(meta.headers).
 		SetContext(ctx)
+	tracing.Inject(ctx, propagation.HeaderCarrier(httpReq.Header))
 
 	if req.Data != nil {
 		httpReq.SetBody(req.Data)
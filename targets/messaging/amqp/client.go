@@ -11,6 +11,7 @@ import (
 	"github.com/kubemq-hub/builder/connector/common"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
@@ -115,7 +116,10 @@ func (c *Client) Publish(ctx context.Context, meta metadata, data []byte) (*type
 		c.senders[meta.address] = newSender
 		queueSender = newSender
 	}
-	err := queueSender.Send(ctx, meta.amqpMessage(data), nil)
+	msg := meta.amqpMessage(data)
+	msg.ApplicationProperties = map[string]interface{}{}
+	tracing.Inject(ctx, applicationPropertiesCarrier(msg.ApplicationProperties))
+	err := queueSender.Send(ctx, msg, nil)
 	if err != nil {
 		return &types.Response{
 			Metadata: nil,
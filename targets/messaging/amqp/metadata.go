@@ -96,3 +96,25 @@ func (m metadata) amqpMessage(data []byte) *amqp.Message {
 
 	return msg
 }
+
+// applicationPropertiesCarrier adapts amqp message application properties to a trace context carrier
+type applicationPropertiesCarrier map[string]interface{}
+
+func (ac applicationPropertiesCarrier) Get(key string) string {
+	if val, ok := ac[key].(string); ok {
+		return val
+	}
+	return ""
+}
+
+func (ac applicationPropertiesCarrier) Set(key, value string) {
+	ac[key] = value
+}
+
+func (ac applicationPropertiesCarrier) Keys() []string {
+	var keys []string
+	for key := range ac {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -4,22 +4,35 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"sync"
 
 	"github.com/kubemq-hub/builder/connector/common"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
 
 	kafka "github.com/Shopify/sarama"
 	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
 type Client struct {
 	log      *logger.Logger
-	producer kafka.SyncProducer
+	producer kafka.AsyncProducer
 	opts     options
 	config   *kafka.Config
+	done     chan struct{}
+	// txnMu serializes the transactions, a transactional producer runs one transaction at a time
+	txnMu sync.Mutex
+}
+
+type sendResult struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Error     string `json:"error,omitempty"`
 }
 
 func New() *Client {
@@ -37,7 +50,19 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, log *logger.Logger)
 	if err != nil {
 		return err
 	}
+	c.config, err = c.newConfig()
+	if err != nil {
+		return err
+	}
+	producer, err := kafka.NewAsyncProducer(c.opts.brokers, c.config)
+	if err != nil {
+		return err
+	}
+	c.start(producer)
+	return nil
+}
 
+func (c *Client) newConfig() (*kafka.Config, error) {
 	kc := kafka.NewConfig()
 	kc.Version = kafka.V2_0_0_0
 	isSSL, isSASL := c.opts.parseSecurityProtocol()
@@ -56,7 +81,7 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, log *logger.Logger)
 		if c.opts.cacert != "" {
 			caCertPool := x509.NewCertPool()
 			if !caCertPool.AppendCertsFromPEM([]byte(c.opts.cacert)) {
-				return fmt.Errorf("error loading Root CA Cert")
+				return nil, fmt.Errorf("error loading Root CA Cert")
 			}
 			tlsCfg.RootCAs = caCertPool
 			c.log.Infof("TLS CA Cert Loaded for Kafka Connection")
@@ -64,20 +89,39 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, log *logger.Logger)
 		if c.opts.clientCert != "" && c.opts.clientKey != "" {
 			cert, err := tls.X509KeyPair([]byte(c.opts.clientCert), []byte(c.opts.clientKey))
 			if err != nil {
-				return fmt.Errorf("error loading tls client key pair, %s", err.Error())
+				return nil, fmt.Errorf("error loading tls client key pair, %s", err.Error())
 			}
 			tlsCfg.Certificates = []tls.Certificate{cert}
 			c.log.Infof("TLS Client Key Pair Loaded for Kafka Connection")
 		}
 		kc.Net.TLS.Config = tlsCfg
 	}
-	c.config = kc
-	c.producer, err = kafka.NewSyncProducer(c.opts.brokers, kc)
-	if err != nil {
-		return err
+	kc.Producer.Return.Successes = true
+	kc.Producer.Return.Errors = true
+	kc.Producer.Partitioner = newRequestPartitioner
+	kc.Producer.RequiredAcks = c.opts.parseRequiredAcks()
+	kc.Producer.Compression = c.opts.parseCompression()
+	if kc.Producer.Compression == kafka.CompressionZSTD {
+		kc.Version = kafka.V2_1_0_0
+	}
+	kc.Producer.Flush.Messages = c.opts.batchSize
+	kc.Producer.Flush.Bytes = c.opts.batchBytes
+	kc.Producer.Flush.Frequency = c.opts.linger
+	if c.opts.idempotent {
+		kc.Producer.Idempotent = true
+		kc.Net.MaxOpenRequests = 1
 	}
+	kc.Producer.Transaction.ID = c.opts.transactionalID
+	if err := kc.Validate(); err != nil {
+		return nil, err
+	}
+	return kc, nil
+}
 
-	return nil
+func (c *Client) start(producer kafka.AsyncProducer) {
+	c.producer = producer
+	c.done = make(chan struct{})
+	go c.dispatch()
 }
 
 func (c *Client) Do(ctx context.Context, request *types.Request) (*types.Response, error) {
@@ -85,22 +129,113 @@ func (c *Client) Do(ctx context.Context, request *types.Request) (*types.Respons
 	if err != nil {
 		return nil, err
 	}
+	switch m.Method {
+	case "send_batch":
+		return c.sendBatch(ctx, m, request.Data)
+	default:
+		return c.sendMessage(ctx, m, request.Data)
+	}
+}
 
-	partition, offset, err := c.producer.SendMessage(&kafka.ProducerMessage{
-		Headers: m.Headers,
-		Key:     kafka.ByteEncoder(m.Key),
-		Value:   kafka.ByteEncoder(request.Data),
-		Topic:   c.opts.topic,
-	})
+func (c *Client) sendMessage(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	msg := c.newMessage(ctx, meta.Topic, meta.Partition, meta.Key, meta.Headers, data)
+	errs, err := c.produce(ctx, []*kafka.ProducerMessage{msg})
+	if err == nil {
+		err = errs[0]
+	}
 	if err != nil {
 		return nil, err
 	}
 	r := types.NewResponse().
-		SetMetadataKeyValue("partition", strconv.FormatInt(int64(partition), 10)).
-		SetMetadataKeyValue("offset", strconv.FormatInt(offset, 10))
+		SetMetadataKeyValue("topic", msg.Topic).
+		SetMetadataKeyValue("partition", strconv.FormatInt(int64(msg.Partition), 10)).
+		SetMetadataKeyValue("offset", strconv.FormatInt(msg.Offset, 10))
 	return r, nil
 }
 
+// sendBatch returns the result of each record, failed records do not fail the request unless the producer is transactional,
+// in which case any failed record aborts the transaction and the request returns an error
+func (c *Client) sendBatch(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	records, err := meta.parseRecords(data, c.opts)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]*kafka.ProducerMessage, len(records))
+	for i, rec := range records {
+		messages[i] = c.newMessage(ctx, rec.Topic, *rec.Partition, rec.Key, rec.Headers, rec.Value)
+	}
+	errs, err := c.produce(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]sendResult, len(messages))
+	failed := 0
+	for i, msg := range messages {
+		results[i] = sendResult{
+			Topic:     msg.Topic,
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+		}
+		if errs[i] != nil {
+			results[i].Error = errs[i].Error()
+			failed++
+		}
+	}
+	b, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("count", strconv.Itoa(len(messages))).
+		SetMetadataKeyValue("failed", strconv.Itoa(failed)).
+		SetData(b), nil
+}
+
+// produce sends the messages, on a transactional producer the messages are sent in a single transaction which is aborted when any message fails
+func (c *Client) produce(ctx context.Context, messages []*kafka.ProducerMessage) ([]error, error) {
+	if !c.producer.IsTransactional() {
+		return c.send(ctx, messages), nil
+	}
+	c.txnMu.Lock()
+	defer c.txnMu.Unlock()
+	if err := c.producer.BeginTxn(); err != nil {
+		return nil, fmt.Errorf("error beginning transaction, %w", err)
+	}
+	errs := c.send(ctx, messages)
+	for _, err := range errs {
+		if err != nil {
+			c.abortTxn()
+			return nil, fmt.Errorf("transaction aborted, %w", err)
+		}
+	}
+	if err := c.producer.CommitTxn(); err != nil {
+		c.abortTxn()
+		return nil, fmt.Errorf("error committing transaction, %w", err)
+	}
+	return errs, nil
+}
+
+func (c *Client) abortTxn() {
+	if c.producer.TxnStatus()&kafka.ProducerTxnFlagFatalError != 0 {
+		return
+	}
+	if err := c.producer.AbortTxn(); err != nil {
+		c.log.Errorf("error aborting transaction, %s", err.Error())
+	}
+}
+
+func (c *Client) newMessage(ctx context.Context, topic string, partition int32, key []byte, headers []kafka.RecordHeader, value []byte) *kafka.ProducerMessage {
+	carrier := &headersCarrier{headers: append([]kafka.RecordHeader{}, headers...)}
+	tracing.Inject(ctx, carrier)
+	return &kafka.ProducerMessage{
+		Headers:  carrier.headers,
+		Key:      kafka.ByteEncoder(key),
+		Value:    kafka.ByteEncoder(value),
+		Topic:    topic,
+		Metadata: &delivery{partition: partition},
+	}
+}
+
 func (c *Client) Connector() *common.Connector {
 	return Connector()
 }
@@ -108,7 +243,8 @@ func (c *Client) Connector() *common.Connector {
 func (c *Client) Stop() error {
 	if c.producer != nil {
 		c.config.MetricRegistry.UnregisterAll()
-		return c.producer.Close()
+		c.producer.AsyncClose()
+		<-c.done
 	}
 	return nil
 }
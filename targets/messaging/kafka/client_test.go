@@ -3,11 +3,16 @@ package kafka
 import (
 	"context"
 	b64 "encoding/base64"
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
+	kafka "github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
 	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/stretchr/testify/require"
 )
@@ -94,6 +99,7 @@ func TestClient_Do(t *testing.T) {
 				SetMetadataKeyValue("Key", "S2V5").
 				SetData([]byte("new-data")),
 			wantResponse: types.NewResponse().
+				SetMetadataKeyValue("topic", "TestTopic").
 				SetMetadataKeyValue("partition", "0").
 				SetMetadataKeyValue("offset", "1"),
 			wantErr: false,
@@ -113,6 +119,7 @@ func TestClient_Do(t *testing.T) {
 				SetData([]byte("new-data")).SetMetadataKeyValue(
 				"Headers", `[{"Key": "_replaceHK_","Value": "_replaceHV_"}]`),
 			wantResponse: types.NewResponse().
+				SetMetadataKeyValue("topic", "TestTopic").
 				SetMetadataKeyValue("partition", "0").
 				SetMetadataKeyValue("offset", "2"),
 			wantErr: false,
@@ -137,3 +144,125 @@ func TestClient_Do(t *testing.T) {
 		})
 	}
 }
+
+func newMockClient(t *testing.T, properties map[string]string) (*Client, *mocks.AsyncProducer) {
+	properties["brokers"] = "localhost:9092"
+	properties["topic"] = "TestTopic"
+	opts, err := parseOptions(config.Spec{
+		Name:       "messaging-kafka",
+		Kind:       "messaging.kafka",
+		Properties: properties,
+	})
+	require.NoError(t, err)
+	c := &Client{
+		log:  logger.NewLogger("messaging-kafka"),
+		opts: opts,
+	}
+	c.config, err = c.newConfig()
+	require.NoError(t, err)
+	producer := mocks.NewAsyncProducer(t, c.config)
+	producer.SetDefaultPartitions(4)
+	c.start(producer)
+	t.Cleanup(func() {
+		_ = c.Stop()
+	})
+	return c, producer
+}
+
+func TestClient_Send_Mock(t *testing.T) {
+	c, producer := newMockClient(t, map[string]string{
+		"allowed_topics": "orders.*",
+	})
+	ctx := context.Background()
+	producer.ExpectInputWithMessageCheckerFunctionAndSucceed(func(msg *kafka.ProducerMessage) error {
+		if msg.Topic != "orders.eu" || msg.Partition != 2 {
+			return errors.New("unexpected topic or partition")
+		}
+		return nil
+	})
+	resp, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("topic", "orders.eu").
+		SetMetadataKeyValue("partition", "2").
+		SetData([]byte("data")))
+	require.NoError(t, err)
+	require.Equal(t, "orders.eu", resp.Metadata["topic"])
+	require.Equal(t, "2", resp.Metadata["partition"])
+	require.Equal(t, "1", resp.Metadata["offset"])
+
+	producer.ExpectInputAndFail(errors.New("broker error"))
+	_, err = c.Do(ctx, types.NewRequest().SetData([]byte("data")))
+	require.Error(t, err)
+
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("topic", "payments").
+		SetData([]byte("data")))
+	require.Error(t, err)
+}
+
+func TestClient_SendBatch_Mock(t *testing.T) {
+	c, producer := newMockClient(t, map[string]string{
+		"allowed_topics": "orders",
+	})
+	ctx := context.Background()
+	data := []byte(`[
+		{"value":"` + b64.StdEncoding.EncodeToString([]byte("first")) + `"},
+		{"topic":"orders","partition":3,"key":"a2V5","value":"` + b64.StdEncoding.EncodeToString([]byte("second")) + `"},
+		{"value":"` + b64.StdEncoding.EncodeToString([]byte("third")) + `"}
+	]`)
+	producer.ExpectInputAndSucceed()
+	producer.ExpectInputWithMessageCheckerFunctionAndSucceed(func(msg *kafka.ProducerMessage) error {
+		key, _ := msg.Key.Encode()
+		if msg.Topic != "orders" || msg.Partition != 3 || string(key) != "key" {
+			return errors.New("unexpected record")
+		}
+		return nil
+	})
+	producer.ExpectInputAndFail(errors.New("broker error"))
+	resp, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "send_batch").
+		SetData(data))
+	require.NoError(t, err)
+	require.False(t, resp.IsError)
+	require.Equal(t, "3", resp.Metadata["count"])
+	require.Equal(t, "1", resp.Metadata["failed"])
+	var results []sendResult
+	require.NoError(t, json.Unmarshal(resp.Data, &results))
+	require.Len(t, results, 3)
+	require.Equal(t, "TestTopic", results[0].Topic)
+	require.Empty(t, results[0].Error)
+	require.Equal(t, sendResult{Topic: "orders", Partition: 3, Offset: 2}, results[1])
+	require.Equal(t, "broker error", results[2].Error)
+
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "send_batch").
+		SetData([]byte(`[{"topic":"payments","value":"ZGF0YQ=="}]`)))
+	require.Error(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "send_batch").
+		SetData([]byte(`[]`)))
+	require.Error(t, err)
+}
+
+func TestClient_Transaction_Mock(t *testing.T) {
+	c, producer := newMockClient(t, map[string]string{
+		"transactional_id": "kafka-target",
+	})
+	ctx := context.Background()
+	data := []byte(`[{"value":"ZGF0YQ=="},{"value":"ZGF0YQ=="}]`)
+	producer.ExpectInputAndSucceed()
+	producer.ExpectInputAndSucceed()
+	resp, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "send_batch").
+		SetData(data))
+	require.NoError(t, err)
+	require.False(t, resp.IsError)
+	require.Equal(t, "0", resp.Metadata["failed"])
+
+	producer.ExpectInputAndSucceed()
+	producer.ExpectInputAndFail(errors.New("broker error"))
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "send_batch").
+		SetData(data))
+	require.ErrorContains(t, err, "transaction aborted")
+	require.Equal(t, kafka.ProducerTxnFlagReady, producer.TxnStatus())
+}
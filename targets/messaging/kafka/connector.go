@@ -1,6 +1,8 @@
 package kafka
 
 import (
+	"math"
+
 	"github.com/kubemq-hub/builder/connector/common"
 )
 
@@ -97,6 +99,114 @@ func Connector() *common.Connector {
 				SetMust(false).
 				SetDefault("false"),
 		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("allowed_topics").
+				SetDescription("Set comma separated list of topics or topic patterns requests may send to").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("required_acks").
+				SetDescription("Set broker acks required for a successful send, none, leader or all (default leader, all for idempotent producer)").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("compression").
+				SetDescription("Set messages compression codec").
+				SetOptions([]string{"none", "gzip", "snappy", "lz4", "zstd"}).
+				SetMust(false).
+				SetDefault("none"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("batch_size").
+				SetDescription("Set number of messages that triggers a batch send, 0 for no limit").
+				SetMust(false).
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("batch_bytes").
+				SetDescription("Set batch size in bytes that triggers a batch send, 0 for no limit").
+				SetMust(false).
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("linger_ms").
+				SetDescription("Set max time in milliseconds messages wait for a batch send").
+				SetMust(false).
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("idempotent").
+				SetDescription("Set idempotent producer").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("transactional_id").
+				SetDescription("Set transactional id, each request is sent in a transaction").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("delivery_timeout_seconds").
+				SetDescription("Set max time in seconds to wait for delivery reports").
+				SetMust(false).
+				SetDefault("30").
+				SetMin(1).
+				SetMax(math.MaxInt32),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("method").
+				SetKind("string").
+				SetDescription("Set Kafka execution method").
+				SetOptions([]string{"send", "send_batch"}).
+				SetDefault("send").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("topic").
+				SetKind("string").
+				SetDescription("Set Kafka topic, must be the configured topic or an allowed topic").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("partition").
+				SetKind("int").
+				SetDescription("Set Kafka partition, -1 for partition by key").
+				SetDefault("-1").
+				SetMin(-1).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
 		AddMetadata(
 			common.NewMetadata().
 				SetName("headers").
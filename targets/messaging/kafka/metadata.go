@@ -3,6 +3,7 @@ package kafka
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 
 	b64 "encoding/base64"
 
@@ -10,14 +11,45 @@ import (
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
+var methodsMap = map[string]string{
+	"":           "send",
+	"send":       "send",
+	"send_batch": "send_batch",
+}
+
 type metadata struct {
-	Headers []kafka.RecordHeader
-	Key     []byte
+	Method    string
+	Topic     string
+	Partition int32
+	Headers   []kafka.RecordHeader
+	Key       []byte
+}
+
+// record is a single message of a send_batch request, empty fields are taken from the request metadata
+type record struct {
+	Topic     string               `json:"topic"`
+	Partition *int32               `json:"partition"`
+	Key       []byte               `json:"key"`
+	Headers   []kafka.RecordHeader `json:"headers"`
+	Value     []byte               `json:"value"`
 }
 
 func parseMetadata(meta types.Metadata, opts options) (metadata, error) {
 	m := metadata{}
 	var err error
+	m.Method, err = meta.ParseStringMap("method", methodsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing method, %w", err)
+	}
+	m.Topic = meta.ParseString("topic", opts.topic)
+	if !opts.isTopicAllowed(m.Topic) {
+		return metadata{}, fmt.Errorf("error parsing topic, topic %s is not allowed", m.Topic)
+	}
+	partition, err := meta.ParseIntWithRange("partition", -1, -1, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing partition, %w", err)
+	}
+	m.Partition = int32(partition)
 	err = m.parseHeaders(meta.ParseString("headers", ""))
 	if err != nil {
 		return metadata{}, fmt.Errorf("error parsing headers, %w", err)
@@ -55,3 +87,66 @@ func (meta *metadata) parseKey(key string) error {
 	}
 	return nil
 }
+
+// parseRecords parses the send_batch request data, a json array of records
+func (meta *metadata) parseRecords(data []byte, opts options) ([]record, error) {
+	var records []record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("error parsing records, %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("error parsing records, no records found")
+	}
+	for i := range records {
+		if records[i].Topic == "" {
+			records[i].Topic = meta.Topic
+		} else if !opts.isTopicAllowed(records[i].Topic) {
+			return nil, fmt.Errorf("error parsing record %d, topic %s is not allowed", i, records[i].Topic)
+		}
+		if records[i].Partition == nil {
+			partition := meta.Partition
+			records[i].Partition = &partition
+		} else if *records[i].Partition < -1 {
+			return nil, fmt.Errorf("error parsing record %d, invalid partition %d", i, *records[i].Partition)
+		}
+		if records[i].Key == nil {
+			records[i].Key = meta.Key
+		}
+		if records[i].Headers == nil {
+			records[i].Headers = meta.Headers
+		}
+	}
+	return records, nil
+}
+
+// headersCarrier adapts kafka record headers to a trace context carrier
+type headersCarrier struct {
+	headers []kafka.RecordHeader
+}
+
+func (hc *headersCarrier) Get(key string) string {
+	for _, h := range hc.headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (hc *headersCarrier) Set(key, value string) {
+	for i, h := range hc.headers {
+		if string(h.Key) == key {
+			hc.headers[i].Value = []byte(value)
+			return
+		}
+	}
+	hc.headers = append(hc.headers, kafka.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (hc *headersCarrier) Keys() []string {
+	var keys []string
+	for _, h := range hc.headers {
+		keys = append(keys, string(h.Key))
+	}
+	return keys
+}
@@ -24,7 +24,9 @@ func TestMetadata_parseMeta(t *testing.T) {
 				"key": "_replaceKey_",
 			},
 			wantMetadata: metadata{
-				Key: []byte("key"),
+				Method:    "send",
+				Partition: -1,
+				Key:       []byte("key"),
 			},
 			wantErr: false,
 		},
@@ -35,6 +37,8 @@ func TestMetadata_parseMeta(t *testing.T) {
 				"key":     "_replaceKey_",
 			},
 			wantMetadata: metadata{
+				Method:    "send",
+				Partition: -1,
 				Headers: []kafka.RecordHeader{
 					{
 						Key:   []byte("meta1"),
@@ -1,15 +1,38 @@
 package kafka
 
 import (
+	"fmt"
+	"math"
+	"path"
 	"strings"
+	"time"
 
 	kafka "github.com/Shopify/sarama"
 	"github.com/kubemq-io/kubemq-targets/config"
 )
 
+const defaultDeliveryTimeout = 30
+
+var requiredAcksMap = map[string]string{
+	"":       "",
+	"none":   "none",
+	"leader": "leader",
+	"all":    "all",
+}
+
+var compressionMap = map[string]string{
+	"":       "none",
+	"none":   "none",
+	"gzip":   "gzip",
+	"snappy": "snappy",
+	"lz4":    "lz4",
+	"zstd":   "zstd",
+}
+
 type options struct {
 	brokers          []string
 	topic            string
+	allowedTopics    []string
 	saslUsername     string
 	saslPassword     string
 	saslMechanism    string
@@ -18,6 +41,14 @@ type options struct {
 	clientCert       string
 	clientKey        string
 	insecure         bool
+	requiredAcks     string
+	compression      string
+	batchSize        int
+	batchBytes       int
+	linger           time.Duration
+	idempotent       bool
+	transactionalID  string
+	deliveryTimeout  time.Duration
 }
 
 func parseOptions(cfg config.Spec) (options, error) {
@@ -31,6 +62,10 @@ func parseOptions(cfg config.Spec) (options, error) {
 	if err != nil {
 		return m, err
 	}
+	m.allowedTopics, err = parseAllowedTopics(cfg.Properties.ParseString("allowed_topics", ""))
+	if err != nil {
+		return m, fmt.Errorf("error parsing allowed topics, %w", err)
+	}
 	m.saslUsername = cfg.Properties.ParseString("sasl_username", "")
 	m.saslPassword = cfg.Properties.ParseString("sasl_password", "")
 	m.saslMechanism = cfg.Properties.ParseString("sasl_mechanism", "")
@@ -39,10 +74,99 @@ func parseOptions(cfg config.Spec) (options, error) {
 	m.clientCert = cfg.Properties.ParseString("client_certificate", "")
 	m.clientKey = cfg.Properties.ParseString("client_key", "")
 	m.insecure = cfg.Properties.ParseBool("insecure", false)
-
+	m.transactionalID = cfg.Properties.ParseString("transactional_id", "")
+	m.idempotent = cfg.Properties.ParseBool("idempotent", false) || m.transactionalID != ""
+	m.requiredAcks, err = cfg.Properties.ParseStringMap("required_acks", requiredAcksMap)
+	if err != nil {
+		return m, fmt.Errorf("error parsing required acks, %w", err)
+	}
+	if m.idempotent && m.requiredAcks != "" && m.requiredAcks != "all" {
+		return m, fmt.Errorf("error parsing required acks, idempotent producer requires all acks")
+	}
+	m.compression, err = cfg.Properties.ParseStringMap("compression", compressionMap)
+	if err != nil {
+		return m, fmt.Errorf("error parsing compression, %w", err)
+	}
+	m.batchSize, err = cfg.Properties.ParseIntWithRange("batch_size", 0, 0, math.MaxInt32)
+	if err != nil {
+		return m, fmt.Errorf("error parsing batch size, %w", err)
+	}
+	m.batchBytes, err = cfg.Properties.ParseIntWithRange("batch_bytes", 0, 0, math.MaxInt32)
+	if err != nil {
+		return m, fmt.Errorf("error parsing batch bytes, %w", err)
+	}
+	linger, err := cfg.Properties.ParseIntWithRange("linger_ms", 0, 0, math.MaxInt32)
+	if err != nil {
+		return m, fmt.Errorf("error parsing linger ms, %w", err)
+	}
+	m.linger = time.Duration(linger) * time.Millisecond
+	deliveryTimeout, err := cfg.Properties.ParseIntWithRange("delivery_timeout_seconds", defaultDeliveryTimeout, 1, math.MaxInt32)
+	if err != nil {
+		return m, fmt.Errorf("error parsing delivery timeout seconds, %w", err)
+	}
+	m.deliveryTimeout = time.Duration(deliveryTimeout) * time.Second
 	return m, nil
 }
 
+func parseAllowedTopics(value string) ([]string, error) {
+	var topics []string
+	for _, topic := range strings.Split(value, ",") {
+		topic = strings.TrimSpace(topic)
+		if topic == "" {
+			continue
+		}
+		if _, err := path.Match(topic, ""); err != nil {
+			return nil, fmt.Errorf("invalid topic pattern %s, %w", topic, err)
+		}
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
+
+// isTopicAllowed returns true for the configured topic and for topics matching one of the allowed topics patterns
+func (m *options) isTopicAllowed(topic string) bool {
+	if topic == m.topic {
+		return true
+	}
+	for _, pattern := range m.allowedTopics {
+		if matched, _ := path.Match(pattern, topic); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *options) parseRequiredAcks() kafka.RequiredAcks {
+	switch m.requiredAcks {
+	case "none":
+		return kafka.NoResponse
+	case "all":
+		return kafka.WaitForAll
+	case "leader":
+		return kafka.WaitForLocal
+	default:
+		if m.idempotent {
+			return kafka.WaitForAll
+		}
+		return kafka.WaitForLocal
+	}
+}
+
+func (m *options) parseCompression() kafka.CompressionCodec {
+	switch m.compression {
+	case "gzip":
+		return kafka.CompressionGZIP
+	case "snappy":
+		return kafka.CompressionSnappy
+	case "lz4":
+		return kafka.CompressionLZ4
+	case "zstd":
+		return kafka.CompressionZSTD
+	default:
+		return kafka.CompressionNone
+	}
+}
+
 func (m *options) parseASLMechanism() kafka.SASLMechanism {
 	switch strings.ToLower(m.saslMechanism) {
 	case "plain":
@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/stretchr/testify/require"
@@ -25,8 +26,10 @@ func TestMetadata_parseOptions(t *testing.T) {
 				},
 			},
 			wantOpts: options{
-				brokers: []string{"localhost:9092", "localhost:9093"},
-				topic:   "TestTopic",
+				brokers:         []string{"localhost:9092", "localhost:9093"},
+				topic:           "TestTopic",
+				compression:     "none",
+				deliveryTimeout: 30 * time.Second,
 			},
 			wantErr: false,
 		}, {
@@ -42,12 +45,81 @@ func TestMetadata_parseOptions(t *testing.T) {
 				},
 			},
 			wantOpts: options{
-				brokers:      []string{"localhost:9092", "localhost:9093"},
-				topic:        "TestTopic",
-				saslUsername: "admin",
-				saslPassword: "password",
+				brokers:         []string{"localhost:9092", "localhost:9093"},
+				topic:           "TestTopic",
+				saslUsername:    "admin",
+				saslPassword:    "password",
+				compression:     "none",
+				deliveryTimeout: 30 * time.Second,
 			},
 			wantErr: false,
+		}, {
+			name: "valid options with batching and transactions",
+			meta: config.Spec{
+				Name: "Kafka options conf",
+				Kind: "kafka",
+				Properties: map[string]string{
+					"brokers":                  "localhost:9092",
+					"topic":                    "TestTopic",
+					"allowed_topics":           "orders, events.*",
+					"compression":              "lz4",
+					"batch_size":               "100",
+					"batch_bytes":              "1048576",
+					"linger_ms":                "10",
+					"transactional_id":         "kafka-target",
+					"delivery_timeout_seconds": "5",
+				},
+			},
+			wantOpts: options{
+				brokers:         []string{"localhost:9092"},
+				topic:           "TestTopic",
+				allowedTopics:   []string{"orders", "events.*"},
+				compression:     "lz4",
+				batchSize:       100,
+				batchBytes:      1048576,
+				linger:          10 * time.Millisecond,
+				idempotent:      true,
+				transactionalID: "kafka-target",
+				deliveryTimeout: 5 * time.Second,
+			},
+			wantErr: false,
+		}, {
+			name: "invalid options - bad compression",
+			meta: config.Spec{
+				Name: "Kafka options conf",
+				Kind: "kafka",
+				Properties: map[string]string{
+					"brokers":     "localhost:9092",
+					"topic":       "TestTopic",
+					"compression": "brotli",
+				},
+			},
+			wantErr: true,
+		}, {
+			name: "invalid options - idempotent with leader acks",
+			meta: config.Spec{
+				Name: "Kafka options conf",
+				Kind: "kafka",
+				Properties: map[string]string{
+					"brokers":       "localhost:9092",
+					"topic":         "TestTopic",
+					"idempotent":    "true",
+					"required_acks": "leader",
+				},
+			},
+			wantErr: true,
+		}, {
+			name: "invalid options - bad allowed topics pattern",
+			meta: config.Spec{
+				Name: "Kafka options conf",
+				Kind: "kafka",
+				Properties: map[string]string{
+					"brokers":        "localhost:9092",
+					"topic":          "TestTopic",
+					"allowed_topics": "orders[",
+				},
+			},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -55,9 +127,9 @@ func TestMetadata_parseOptions(t *testing.T) {
 			gotOpts, err := parseOptions(tt.meta)
 			if tt.wantErr {
 				require.Error(t, err)
-			} else {
-				require.NoError(t, err)
+				return
 			}
+			require.NoError(t, err)
 			require.EqualValues(t, tt.wantOpts, gotOpts)
 		})
 	}
@@ -0,0 +1,102 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	kafka "github.com/Shopify/sarama"
+)
+
+// delivery is attached to each produced message and receives its delivery report
+type delivery struct {
+	partition int32
+	result    chan error
+}
+
+// requestPartitioner sends messages with a requested partition to that partition, other messages are hash partitioned by key
+type requestPartitioner struct {
+	kafka.Partitioner
+}
+
+func newRequestPartitioner(topic string) kafka.Partitioner {
+	return &requestPartitioner{Partitioner: kafka.NewHashPartitioner(topic)}
+}
+
+func (p *requestPartitioner) Partition(message *kafka.ProducerMessage, numPartitions int32) (int32, error) {
+	if d, ok := message.Metadata.(*delivery); ok && d.partition >= 0 {
+		if d.partition >= numPartitions {
+			return -1, fmt.Errorf("partition %d is out of range, topic %s has %d partitions", d.partition, message.Topic, numPartitions)
+		}
+		return d.partition, nil
+	}
+	return p.Partitioner.Partition(message, numPartitions)
+}
+
+func (p *requestPartitioner) RequiresConsistency() bool {
+	return true
+}
+
+// dispatch routes the producer delivery reports to the waiting requests until the producer is closed
+func (c *Client) dispatch() {
+	defer close(c.done)
+	successes := c.producer.Successes()
+	errors := c.producer.Errors()
+	for successes != nil || errors != nil {
+		select {
+		case msg, ok := <-successes:
+			if !ok {
+				successes = nil
+				continue
+			}
+			deliver(msg, nil)
+		case perr, ok := <-errors:
+			if !ok {
+				errors = nil
+				continue
+			}
+			deliver(perr.Msg, perr.Err)
+		}
+	}
+}
+
+func deliver(msg *kafka.ProducerMessage, err error) {
+	if msg == nil {
+		return
+	}
+	if d, ok := msg.Metadata.(*delivery); ok {
+		d.result <- err
+	}
+}
+
+// send produces the messages and waits for all of their delivery reports, the returned errors match the messages order
+func (c *Client) send(ctx context.Context, messages []*kafka.ProducerMessage) []error {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.deliveryTimeout)
+	defer cancel()
+	errs := make([]error, len(messages))
+	deliveries := make([]*delivery, len(messages))
+	for i, msg := range messages {
+		d, _ := msg.Metadata.(*delivery)
+		if d == nil {
+			d = &delivery{partition: -1}
+			msg.Metadata = d
+		}
+		d.result = make(chan error, 1)
+		select {
+		case c.producer.Input() <- msg:
+			deliveries[i] = d
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+	for i, d := range deliveries {
+		if d == nil {
+			continue
+		}
+		select {
+		case errs[i] = <-d.result:
+		case <-ctx.Done():
+			errs[i] = fmt.Errorf("error waiting for delivery report, %w", ctx.Err())
+		}
+	}
+	return errs
+}
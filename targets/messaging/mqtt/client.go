@@ -23,6 +23,7 @@ type Client struct {
 	client         mqtt.Client
 	isConnected    *atomic.Bool
 	reconnectCount *atomic.Int32
+	v5             *v5Client
 }
 
 func New() *Client {
@@ -47,6 +48,10 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, log *logger.Logger)
 	if err != nil {
 		return err
 	}
+	if c.opts.protocolVersion == "5" {
+		c.v5, err = newV5Client(ctx, c.opts, c.log)
+		return err
+	}
 	opts := mqtt.NewClientOptions()
 	opts.AddBroker(fmt.Sprintf("tcp://%s", c.opts.host))
 	opts.SetUsername(c.opts.username)
@@ -79,12 +84,18 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 			return nil, err
 		}
 	}
+	if c.v5 != nil {
+		return c.v5.do(ctx, meta, req.Data)
+	}
+	if meta.method == "request" {
+		return nil, fmt.Errorf("request method requires mqtt protocol version 5")
+	}
 	if c.isConnected.Load() == false {
 		c.log.Errorf("publish message to topic %s failed, mqtt client is not connected", meta.topic)
 		return nil, fmt.Errorf("mqtt client is not connected")
 	}
 	c.log.Infof("publish message to topic: %s , with qos %d, payload size: %d, payload: %s", meta.topic, meta.qos, len(req.Data), req.String())
-	token := c.client.Publish(meta.topic, byte(meta.qos), false, req.Data)
+	token := c.client.Publish(meta.topic, byte(meta.qos), meta.retain, req.Data)
 	token.WaitTimeout(time.Second)
 	if token.Error() != nil {
 		c.log.Errorf("publish message to topic %s failed, error: %s", meta.topic, token.Error().Error())
@@ -95,6 +106,10 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 }
 
 func (c *Client) Stop() error {
+	if c.v5 != nil {
+		c.log.Info("client stopping")
+		return c.v5.stop()
+	}
 	if c.client != nil {
 		c.log.Info("client stopping")
 		c.client.Disconnect(0)
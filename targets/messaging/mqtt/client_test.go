@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/stretchr/testify/require"
@@ -139,6 +141,61 @@ func TestClient_Do(t *testing.T) {
 				SetMetadataKeyValue("result", "ok"),
 			wantErr: true,
 		},
+		{
+			name: "valid publish request with mqtt 5 properties",
+			cfg: config.Spec{
+				Name: "messaging.mqtt",
+				Kind: "messaging.mqtt",
+				Properties: map[string]string{
+					"host":             "localhost:1883",
+					"protocol_version": "5",
+				},
+			},
+			request: types.NewRequest().
+				SetMetadataKeyValue("topic", "some-queue").
+				SetMetadataKeyValue("qos", "1").
+				SetMetadataKeyValue("retain", "true").
+				SetMetadataKeyValue("user_properties", `{"tenant":"some-tenant"}`).
+				SetMetadataKeyValue("message_expiry_seconds", "60").
+				SetMetadataKeyValue("content_type", "application/json").
+				SetData([]byte(`{"key":"value"}`)),
+			wantResponse: types.NewResponse().
+				SetMetadataKeyValue("result", "ok"),
+			wantErr: false,
+		},
+		{
+			name: "invalid publish request - bad user properties",
+			cfg: config.Spec{
+				Name: "messaging.mqtt",
+				Kind: "messaging.mqtt",
+				Properties: map[string]string{
+					"host":             "localhost:1883",
+					"protocol_version": "5",
+				},
+			},
+			request: types.NewRequest().
+				SetMetadataKeyValue("topic", "some-queue").
+				SetMetadataKeyValue("user_properties", "bad-properties").
+				SetData([]byte("some-data")),
+			wantResponse: nil,
+			wantErr:      true,
+		},
+		{
+			name: "invalid request - mqtt 3 protocol",
+			cfg: config.Spec{
+				Name: "messaging.mqtt",
+				Kind: "messaging.mqtt",
+				Properties: map[string]string{
+					"host": "localhost:1883",
+				},
+			},
+			request: types.NewRequest().
+				SetMetadataKeyValue("method", "request").
+				SetMetadataKeyValue("topic", "some-queue").
+				SetData([]byte("some-data")),
+			wantResponse: nil,
+			wantErr:      true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -158,3 +215,113 @@ func TestClient_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Request(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	responder := New()
+	err := responder.Init(ctx, config.Spec{
+		Name: "messaging.mqtt",
+		Kind: "messaging.mqtt",
+		Properties: map[string]string{
+			"host":             "localhost:1883",
+			"protocol_version": "5",
+		},
+	}, nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = responder.Stop()
+	}()
+	responder.v5.cm.AddOnPublishReceived(func(pr autopaho.PublishReceived) (bool, error) {
+		if pr.Packet.Topic != "requests" {
+			return false, nil
+		}
+		_, err := pr.ConnectionManager.Publish(context.Background(), &paho.Publish{
+			Topic: pr.Packet.Properties.ResponseTopic,
+			Properties: &paho.PublishProperties{
+				CorrelationData: pr.Packet.Properties.CorrelationData,
+				ContentType:     "text/plain",
+				User:            paho.UserProperties{{Key: "tenant", Value: pr.Packet.Properties.User.Get("tenant")}},
+			},
+			Payload: append([]byte("reply-"), pr.Packet.Payload...),
+		})
+		return true, err
+	})
+	_, err = responder.v5.cm.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: "requests"}},
+	})
+	require.NoError(t, err)
+
+	c := New()
+	err = c.Init(ctx, config.Spec{
+		Name: "messaging.mqtt",
+		Kind: "messaging.mqtt",
+		Properties: map[string]string{
+			"host":             "localhost:1883",
+			"protocol_version": "5",
+		},
+	}, nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = c.Stop()
+	}()
+	tests := []struct {
+		name         string
+		request      *types.Request
+		wantResponse *types.Response
+		wantErr      bool
+	}{
+		{
+			name: "valid request",
+			request: types.NewRequest().
+				SetMetadataKeyValue("method", "request").
+				SetMetadataKeyValue("topic", "requests").
+				SetMetadataKeyValue("response_topic", "responses").
+				SetMetadataKeyValue("correlation_data", "some-correlation").
+				SetMetadataKeyValue("user_properties", `{"tenant":"some-tenant"}`).
+				SetData([]byte("some-data")),
+			wantResponse: types.NewResponse().
+				SetData([]byte("reply-some-data")).
+				SetMetadataKeyValue("topic", "responses").
+				SetMetadataKeyValue("correlation_data", "some-correlation").
+				SetMetadataKeyValue("content_type", "text/plain").
+				SetMetadataKeyValue("user_properties", `{"tenant":"some-tenant"}`),
+			wantErr: false,
+		},
+		{
+			name: "valid request - default response topic",
+			request: types.NewRequest().
+				SetMetadataKeyValue("method", "request").
+				SetMetadataKeyValue("topic", "requests").
+				SetMetadataKeyValue("correlation_data", "other-correlation").
+				SetData([]byte("some-data")),
+			wantResponse: types.NewResponse().
+				SetData([]byte("reply-some-data")).
+				SetMetadataKeyValue("topic", "kubemq-targets/responses/"+c.opts.clientId).
+				SetMetadataKeyValue("correlation_data", "other-correlation").
+				SetMetadataKeyValue("content_type", "text/plain").
+				SetMetadataKeyValue("user_properties", `{"tenant":""}`),
+			wantErr: false,
+		},
+		{
+			name: "invalid request - no reply",
+			request: types.NewRequest().
+				SetMetadataKeyValue("method", "request").
+				SetMetadataKeyValue("topic", "no-responder").
+				SetMetadataKeyValue("timeout_seconds", "1").
+				SetData([]byte("some-data")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResponse, err := c.Do(ctx, tt.request)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tt.wantResponse, gotResponse)
+		})
+	}
+}
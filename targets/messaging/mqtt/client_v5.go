@@ -0,0 +1,240 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
+	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
+	"github.com/kubemq-io/kubemq-targets/types"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	defaultKeepAlive           = 30
+	defaultResponseTopicPrefix = "kubemq-targets/responses"
+)
+
+// v5Client publishes with the MQTT 5 protocol, replies of request calls are routed back to the waiting caller by their correlation data
+type v5Client struct {
+	sync.Mutex
+	log            *logger.Logger
+	opts           options
+	cm             *autopaho.ConnectionManager
+	cancel         context.CancelFunc
+	pending        sync.Map
+	responseTopics map[string]byte
+}
+
+func newV5Client(ctx context.Context, opts options, log *logger.Logger) (*v5Client, error) {
+	serverUrl, err := url.Parse(fmt.Sprintf("mqtt://%s", opts.host))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing host, %w", err)
+	}
+	c := &v5Client{
+		log:            log,
+		opts:           opts,
+		responseTopics: map[string]byte{},
+	}
+	cfg := autopaho.ClientConfig{
+		ServerUrls:                    []*url.URL{serverUrl},
+		KeepAlive:                     defaultKeepAlive,
+		CleanStartOnInitialConnection: true,
+		ConnectRetryDelay:             time.Second,
+		ConnectTimeout:                defaultConnectTimeout,
+		ConnectUsername:               opts.username,
+		ConnectPassword:               []byte(opts.password),
+		OnConnectionUp:                c.onConnectionUp,
+		OnConnectError: func(err error) {
+			c.log.Errorf("mqtt client connection error: %s", err.Error())
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID:          opts.clientId,
+			OnPublishReceived: []func(paho.PublishReceived) (bool, error){c.onPublishReceived},
+			OnClientError: func(err error) {
+				c.log.Errorf("mqtt client connection lost, error: %s", err.Error())
+			},
+		},
+	}
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.cm, err = autopaho.NewConnection(ctx, cfg)
+	if err != nil {
+		c.cancel()
+		return nil, fmt.Errorf("error connecting to mqtt broker, %w", err)
+	}
+	connCtx, connCancel := context.WithTimeout(ctx, defaultConnectTimeout)
+	defer connCancel()
+	if err := c.cm.AwaitConnection(connCtx); err != nil {
+		c.cancel()
+		return nil, fmt.Errorf("error connecting to mqtt broker, %w", err)
+	}
+	return c, nil
+}
+
+// onConnectionUp restores the response topics subscriptions, the session is not kept between connections
+func (c *v5Client) onConnectionUp(cm *autopaho.ConnectionManager, _ *paho.Connack) {
+	c.log.Infof("mqtt client connected")
+	c.Lock()
+	defer c.Unlock()
+	if len(c.responseTopics) == 0 {
+		return
+	}
+	sub := &paho.Subscribe{}
+	for topic, qos := range c.responseTopics {
+		sub.Subscriptions = append(sub.Subscriptions, paho.SubscribeOptions{Topic: topic, QoS: qos})
+	}
+	if _, err := cm.Subscribe(context.Background(), sub); err != nil {
+		c.log.Errorf("error subscribing to response topics, %s", err.Error())
+	}
+}
+
+func (c *v5Client) onPublishReceived(pr paho.PublishReceived) (bool, error) {
+	if pr.Packet.Properties == nil || len(pr.Packet.Properties.CorrelationData) == 0 {
+		return false, nil
+	}
+	if replyCh, ok := c.pending.LoadAndDelete(string(pr.Packet.Properties.CorrelationData)); ok {
+		replyCh.(chan *paho.Publish) <- pr.Packet
+		return true, nil
+	}
+	return false, nil
+}
+
+func (c *v5Client) do(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	if meta.method == "request" {
+		return c.request(ctx, meta, data)
+	}
+	return c.publish(ctx, meta, data)
+}
+
+func (c *v5Client) newPublish(ctx context.Context, meta metadata, data []byte) *paho.Publish {
+	props := &paho.PublishProperties{
+		ContentType:   meta.contentType,
+		ResponseTopic: meta.responseTopic,
+	}
+	if meta.correlationData != "" {
+		props.CorrelationData = []byte(meta.correlationData)
+	}
+	if meta.messageExpiry > 0 {
+		expiry := uint32(meta.messageExpiry)
+		props.MessageExpiry = &expiry
+	}
+	userProperties := map[string]string{}
+	for key, value := range meta.userProperties {
+		userProperties[key] = value
+	}
+	tracing.Inject(ctx, propagation.MapCarrier(userProperties))
+	keys := make([]string, 0, len(userProperties))
+	for key := range userProperties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		props.User.Add(key, userProperties[key])
+	}
+	return &paho.Publish{
+		QoS:        byte(meta.qos),
+		Retain:     meta.retain,
+		Topic:      meta.topic,
+		Properties: props,
+		Payload:    data,
+	}
+}
+
+func (c *v5Client) publish(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	pubCtx, cancel := context.WithTimeout(ctx, time.Duration(meta.timeout)*time.Second)
+	defer cancel()
+	resp, err := c.cm.Publish(pubCtx, c.newPublish(ctx, meta, data))
+	if err != nil {
+		c.log.Errorf("publish message to topic %s failed, error: %s", meta.topic, err.Error())
+		return nil, err
+	}
+	if resp != nil && resp.ReasonCode >= 0x80 {
+		return nil, fmt.Errorf("publish message to topic %s failed, reason code: %d", meta.topic, resp.ReasonCode)
+	}
+	c.log.Infof("publish message to topic: %s , with qos %d, payload size: %d", meta.topic, meta.qos, len(data))
+	return types.NewResponse().SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (c *v5Client) subscribeResponseTopic(ctx context.Context, topic string, qos byte) error {
+	c.Lock()
+	defer c.Unlock()
+	if _, ok := c.responseTopics[topic]; ok {
+		return nil
+	}
+	_, err := c.cm.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: topic, QoS: qos}},
+	})
+	if err != nil {
+		return fmt.Errorf("error subscribing to response topic %s, %w", topic, err)
+	}
+	c.responseTopics[topic] = qos
+	return nil
+}
+
+// request publishes the message with a response topic and correlation data and waits for the correlated reply
+func (c *v5Client) request(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, time.Duration(meta.timeout)*time.Second)
+	defer cancel()
+	if meta.responseTopic == "" {
+		meta.responseTopic = fmt.Sprintf("%s/%s", defaultResponseTopicPrefix, c.opts.clientId)
+	}
+	if meta.correlationData == "" {
+		meta.correlationData = uuid.New().String()
+	}
+	if err := c.subscribeResponseTopic(reqCtx, meta.responseTopic, byte(meta.qos)); err != nil {
+		return nil, err
+	}
+	replyCh := make(chan *paho.Publish, 1)
+	c.pending.Store(meta.correlationData, replyCh)
+	defer c.pending.Delete(meta.correlationData)
+	resp, err := c.cm.Publish(reqCtx, c.newPublish(ctx, meta, data))
+	if err != nil {
+		return nil, fmt.Errorf("publish request to topic %s failed, %w", meta.topic, err)
+	}
+	if resp != nil && resp.ReasonCode >= 0x80 {
+		return nil, fmt.Errorf("publish request to topic %s failed, reason code: %d", meta.topic, resp.ReasonCode)
+	}
+	select {
+	case reply := <-replyCh:
+		return replyResponse(reply), nil
+	case <-reqCtx.Done():
+		return nil, fmt.Errorf("timeout waiting for reply on topic %s, %w", meta.responseTopic, reqCtx.Err())
+	}
+}
+
+func replyResponse(reply *paho.Publish) *types.Response {
+	resp := types.NewResponse().
+		SetData(reply.Payload).
+		SetMetadataKeyValue("topic", reply.Topic).
+		SetMetadataKeyValue("correlation_data", string(reply.Properties.CorrelationData))
+	if reply.Properties.ContentType != "" {
+		resp.SetMetadataKeyValue("content_type", reply.Properties.ContentType)
+	}
+	if len(reply.Properties.User) > 0 {
+		userProperties := map[string]string{}
+		for _, prop := range reply.Properties.User {
+			userProperties[prop.Key] = prop.Value
+		}
+		data, err := json.Marshal(userProperties)
+		if err == nil {
+			resp.SetMetadataKeyValue("user_properties", string(data))
+		}
+	}
+	return resp
+}
+
+func (c *v5Client) stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultConnectTimeout)
+	defer cancel()
+	err := c.cm.Disconnect(ctx)
+	c.cancel()
+	return err
+}
@@ -1,6 +1,8 @@
 package mqtt
 
 import (
+	"math"
+
 	"github.com/kubemq-hub/builder/connector/common"
 )
 
@@ -46,6 +48,15 @@ func Connector() *common.Connector {
 				SetMust(false).
 				SetDefault(""),
 		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("string").
+				SetName("protocol_version").
+				SetDescription("Set MQTT protocol version").
+				SetOptions([]string{"3", "5"}).
+				SetMust(false).
+				SetDefault("3"),
+		).
 		AddProperty(
 			common.NewProperty().
 				SetKind("string").
@@ -64,6 +75,23 @@ func Connector() *common.Connector {
 				SetMax(2).
 				SetDefault("0"),
 		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("default_retain").
+				SetDescription("Set MQTT default retain flag").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("method").
+				SetDescription("Set MQTT execution method").
+				SetOptions([]string{"publish", "request"}).
+				SetDefault("publish").
+				SetMust(false),
+		).
 		AddMetadata(
 			common.NewMetadata().
 				SetKind("string").
@@ -81,5 +109,65 @@ func Connector() *common.Connector {
 				SetMin(0).
 				SetMax(2).
 				SetDefault("0"),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("bool").
+				SetName("retain").
+				SetDescription("Set MQTT retain flag").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("user_properties").
+				SetDescription("Set MQTT 5 user properties as json object").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("int").
+				SetName("message_expiry_seconds").
+				SetDescription("Set MQTT 5 message expiry in seconds").
+				SetMust(false).
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetDefault("0"),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("content_type").
+				SetDescription("Set MQTT 5 content type").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("response_topic").
+				SetDescription("Set MQTT 5 response topic").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("correlation_data").
+				SetDescription("Set MQTT 5 correlation data").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("int").
+				SetName("timeout_seconds").
+				SetDescription("Set MQTT 5 request reply timeout in seconds").
+				SetMust(false).
+				SetMin(1).
+				SetMax(math.MaxInt32).
+				SetDefault("30"),
 		)
 }
@@ -2,18 +2,39 @@ package mqtt
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
+const defaultTimeoutSeconds = 30
+
+var methodsMap = map[string]string{
+	"":        "publish",
+	"publish": "publish",
+	"request": "request",
+}
+
 type metadata struct {
-	topic string
-	qos   int
+	method          string
+	topic           string
+	qos             int
+	retain          bool
+	userProperties  map[string]string
+	messageExpiry   int
+	contentType     string
+	responseTopic   string
+	correlationData string
+	timeout         int
 }
 
 func parseMetadata(meta types.Metadata) (metadata, error) {
 	m := metadata{}
 	var err error
+	m.method, err = meta.ParseStringMap("method", methodsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing method, %w", err)
+	}
 	m.topic, err = meta.MustParseString("topic")
 	if err != nil {
 		return metadata{}, fmt.Errorf("error parsing topic name, %w", err)
@@ -22,5 +43,21 @@ func parseMetadata(meta types.Metadata) (metadata, error) {
 	if err != nil {
 		return metadata{}, fmt.Errorf("error parsing qos, %w", err)
 	}
+	m.retain = meta.ParseBool("retain", false)
+	m.userProperties, err = meta.MustParseJsonMap("user_properties")
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing user_properties, %w", err)
+	}
+	m.messageExpiry, err = meta.ParseIntWithRange("message_expiry_seconds", 0, 0, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing message_expiry_seconds, %w", err)
+	}
+	m.contentType = meta.ParseString("content_type", "")
+	m.responseTopic = meta.ParseString("response_topic", "")
+	m.correlationData = meta.ParseString("correlation_data", "")
+	m.timeout, err = meta.ParseIntWithRange("timeout_seconds", defaultTimeoutSeconds, 1, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing timeout_seconds, %w", err)
+	}
 	return m, nil
 }
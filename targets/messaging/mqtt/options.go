@@ -7,13 +7,22 @@ import (
 	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
 )
 
+var protocolVersionsMap = map[string]string{
+	"":      "3",
+	"3":     "3",
+	"3.1.1": "3",
+	"5":     "5",
+}
+
 type options struct {
-	host         string
-	username     string
-	password     string
-	clientId     string
-	defaultTopic string
-	defaultQos   int
+	host            string
+	username        string
+	password        string
+	clientId        string
+	protocolVersion string
+	defaultTopic    string
+	defaultQos      int
+	defaultRetain   bool
 }
 
 func parseOptions(cfg config.Spec) (options, error) {
@@ -26,19 +35,27 @@ func parseOptions(cfg config.Spec) (options, error) {
 	o.username = cfg.Properties.ParseString("username", "")
 	o.password = cfg.Properties.ParseString("password", "")
 	o.clientId = cfg.Properties.ParseString("client_id", uuid.New().String())
+	o.protocolVersion, err = cfg.Properties.ParseStringMap("protocol_version", protocolVersionsMap)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing protocol_version, %w", err)
+	}
 	o.defaultTopic = cfg.Properties.ParseString("default_topic", "")
 	o.defaultQos, err = cfg.Properties.ParseIntWithRange("default_qos", 0, 0, 2)
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing default_qos, %w", err)
 	}
+	o.defaultRetain = cfg.Properties.ParseBool("default_retain", false)
 	return o, nil
 }
 
 func (o options) defaultMetadata() (metadata, bool) {
 	if o.defaultTopic != "" {
 		return metadata{
-			topic: o.defaultTopic,
-			qos:   o.defaultQos,
+			method:  "publish",
+			topic:   o.defaultTopic,
+			qos:     o.defaultQos,
+			retain:  o.defaultRetain,
+			timeout: defaultTimeoutSeconds,
 		}, true
 	}
 	return metadata{}, false
@@ -4,21 +4,27 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/kubemq-hub/builder/connector/common"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 type Client struct {
 	log    *logger.Logger
 	opts   options
 	client *nats.Conn
+	js     nats.JetStreamContext
 }
 
 func New() *Client {
@@ -45,6 +51,10 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, log *logger.Logger)
 	if err != nil {
 		return err
 	}
+	c.js, err = c.client.JetStream()
+	if err != nil {
+		return fmt.Errorf("error creating jetstream context, %w", err)
+	}
 	return nil
 }
 
@@ -53,13 +63,147 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 	if err != nil {
 		return nil, err
 	}
-	err = c.client.Publish(meta.subject, req.Data)
+	switch meta.method {
+	case "request":
+		return c.request(ctx, meta, req.Data)
+	case "jetstream_publish":
+		return c.jetStreamPublish(ctx, meta, req.Data)
+	case "kv_get":
+		return c.kvGet(meta)
+	case "kv_put":
+		return c.kvPut(meta, req.Data)
+	case "kv_delete":
+		return c.kvDelete(meta)
+	default:
+		return c.publish(ctx, meta, req.Data)
+	}
+}
+
+// newMsg creates a message with the request headers and the trace context of ctx
+func newMsg(ctx context.Context, meta metadata, data []byte) *nats.Msg {
+	msg := nats.NewMsg(meta.subject)
+	msg.Data = data
+	for key, value := range meta.headers {
+		msg.Header.Set(key, value)
+	}
+	tracing.Inject(ctx, propagation.HeaderCarrier(http.Header(msg.Header)))
+	return msg
+}
+
+func (c *Client) publish(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	err := c.client.PublishMsg(newMsg(ctx, meta, data))
 	if err != nil {
 		return nil, err
 	}
 	return types.NewResponse().SetMetadataKeyValue("result", "ok"), nil
 }
 
+func (c *Client) request(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, time.Duration(meta.timeout)*time.Second)
+	defer cancel()
+	reply, err := c.client.RequestMsgWithContext(reqCtx, newMsg(ctx, meta, data))
+	if err != nil {
+		return nil, fmt.Errorf("error sending request to subject %s, %w", meta.subject, err)
+	}
+	resp := types.NewResponse().
+		SetData(reply.Data).
+		SetMetadataKeyValue("subject", reply.Subject).
+		SetMetadataKeyValue("result", "ok")
+	if len(reply.Header) > 0 {
+		headers := map[string]string{}
+		for key := range reply.Header {
+			headers[key] = reply.Header.Get(key)
+		}
+		headersData, err := json.Marshal(headers)
+		if err == nil {
+			resp.SetMetadataKeyValue("headers", string(headersData))
+		}
+	}
+	return resp, nil
+}
+
+func (c *Client) jetStreamPublish(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, time.Duration(meta.timeout)*time.Second)
+	defer cancel()
+	opts := []nats.PubOpt{nats.Context(reqCtx)}
+	if meta.stream != "" {
+		opts = append(opts, nats.ExpectStream(meta.stream))
+	}
+	if meta.msgId != "" {
+		opts = append(opts, nats.MsgId(meta.msgId))
+	}
+	ack, err := c.js.PublishMsg(newMsg(ctx, meta, data), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error publishing to jetstream subject %s, %w", meta.subject, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("stream", ack.Stream).
+		SetMetadataKeyValue("sequence", strconv.FormatUint(ack.Sequence, 10)).
+		SetMetadataKeyValue("duplicate", strconv.FormatBool(ack.Duplicate)).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (c *Client) kvGet(meta metadata) (*types.Response, error) {
+	kv, err := c.js.KeyValue(meta.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("error binding to key-value bucket %s, %w", meta.bucket, err)
+	}
+	var entry nats.KeyValueEntry
+	if meta.revision > 0 {
+		entry, err = kv.GetRevision(meta.key, meta.revision)
+	} else {
+		entry, err = kv.Get(meta.key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting key %s, %w", meta.key, err)
+	}
+	return types.NewResponse().
+		SetData(entry.Value()).
+		SetMetadataKeyValue("bucket", meta.bucket).
+		SetMetadataKeyValue("key", meta.key).
+		SetMetadataKeyValue("revision", strconv.FormatUint(entry.Revision(), 10)), nil
+}
+
+// kvPut stores the value of the key, when a revision is set the value is stored only if the key last revision matches it
+func (c *Client) kvPut(meta metadata, data []byte) (*types.Response, error) {
+	kv, err := c.js.KeyValue(meta.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("error binding to key-value bucket %s, %w", meta.bucket, err)
+	}
+	var revision uint64
+	if meta.revision > 0 {
+		revision, err = kv.Update(meta.key, data, meta.revision)
+	} else {
+		revision, err = kv.Put(meta.key, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error putting key %s, %w", meta.key, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("bucket", meta.bucket).
+		SetMetadataKeyValue("key", meta.key).
+		SetMetadataKeyValue("revision", strconv.FormatUint(revision, 10)).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (c *Client) kvDelete(meta metadata) (*types.Response, error) {
+	kv, err := c.js.KeyValue(meta.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("error binding to key-value bucket %s, %w", meta.bucket, err)
+	}
+	var opts []nats.DeleteOpt
+	if meta.revision > 0 {
+		opts = append(opts, nats.LastRevision(meta.revision))
+	}
+	if err := kv.Delete(meta.key, opts...); err != nil {
+		return nil, fmt.Errorf("error deleting key %s, %w", meta.key, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("bucket", meta.bucket).
+		SetMetadataKeyValue("key", meta.key).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
 func (c *Client) Stop() error {
 	if c.client != nil {
 		c.client.Close()
@@ -9,6 +9,7 @@ import (
 	"github.com/kubemq-io/kubemq-targets/types"
 
 	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/nats-io/nats.go"
 
 	"github.com/stretchr/testify/require"
 )
@@ -197,3 +198,174 @@ func TestClient_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Request(t *testing.T) {
+	dat, err := getTestStructure()
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New()
+	err = c.Init(ctx, config.Spec{
+		Name: "messaging-nats",
+		Kind: "messaging.nats",
+		Properties: map[string]string{
+			"url":      dat.url,
+			"username": dat.username,
+			"password": dat.password,
+			"token":    dat.password,
+			"tls":      "false",
+		},
+	}, nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = c.Stop()
+	}()
+	sub, err := c.client.Subscribe("foo.request", func(msg *nats.Msg) {
+		reply := nats.NewMsg(msg.Reply)
+		reply.Data = append([]byte("reply-"), msg.Data...)
+		reply.Header.Set("x-request-header", msg.Header.Get("x-request-header"))
+		_ = msg.RespondMsg(reply)
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = sub.Unsubscribe()
+	}()
+	tests := []struct {
+		name         string
+		request      *types.Request
+		wantResponse *types.Response
+		wantErr      bool
+	}{
+		{
+			name: "valid request",
+			request: types.NewRequest().
+				SetMetadataKeyValue("method", "request").
+				SetMetadataKeyValue("subject", "foo.request").
+				SetMetadataKeyValue("headers", `{"x-request-header":"value"}`).
+				SetData([]byte("some-data")),
+			wantResponse: types.NewResponse().
+				SetData([]byte("reply-some-data")).
+				SetMetadataKeyValue("headers", `{"x-request-header":"value"}`),
+			wantErr: false,
+		},
+		{
+			name: "invalid request - no responders",
+			request: types.NewRequest().
+				SetMetadataKeyValue("method", "request").
+				SetMetadataKeyValue("subject", "foo.no-responders").
+				SetMetadataKeyValue("timeout_seconds", "1").
+				SetData([]byte("some-data")),
+			wantErr: true,
+		},
+		{
+			name: "invalid request - bad headers",
+			request: types.NewRequest().
+				SetMetadataKeyValue("method", "request").
+				SetMetadataKeyValue("subject", "foo.request").
+				SetMetadataKeyValue("headers", "bad-headers").
+				SetData([]byte("some-data")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResponse, err := c.Do(ctx, tt.request)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tt.wantResponse.Data, gotResponse.Data)
+			require.EqualValues(t, tt.wantResponse.Metadata["headers"], gotResponse.Metadata["headers"])
+		})
+	}
+}
+
+func TestClient_JetStream(t *testing.T) {
+	dat, err := getTestStructure()
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New()
+	err = c.Init(ctx, config.Spec{
+		Name: "messaging-nats",
+		Kind: "messaging.nats",
+		Properties: map[string]string{
+			"url":      dat.url,
+			"username": dat.username,
+			"password": dat.password,
+			"token":    dat.password,
+			"tls":      "false",
+		},
+	}, nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = c.Stop()
+	}()
+	_ = c.js.DeleteStream("kubemq-targets-test")
+	_, err = c.js.AddStream(&nats.StreamConfig{
+		Name:     "kubemq-targets-test",
+		Subjects: []string{"kubemq-targets-test.>"},
+	})
+	require.NoError(t, err)
+	_ = c.js.DeleteKeyValue("kubemq-targets-test")
+	_, err = c.js.CreateKeyValue(&nats.KeyValueConfig{Bucket: "kubemq-targets-test"})
+	require.NoError(t, err)
+
+	publish := types.NewRequest().
+		SetMetadataKeyValue("method", "jetstream_publish").
+		SetMetadataKeyValue("subject", "kubemq-targets-test.foo").
+		SetMetadataKeyValue("stream", "kubemq-targets-test").
+		SetMetadataKeyValue("msg_id", "msg-1").
+		SetData([]byte("some-data"))
+	resp, err := c.Do(ctx, publish)
+	require.NoError(t, err)
+	require.EqualValues(t, "kubemq-targets-test", resp.Metadata["stream"])
+	require.EqualValues(t, "1", resp.Metadata["sequence"])
+	require.EqualValues(t, "false", resp.Metadata["duplicate"])
+	resp, err = c.Do(ctx, publish)
+	require.NoError(t, err)
+	require.EqualValues(t, "true", resp.Metadata["duplicate"])
+
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "jetstream_publish").
+		SetMetadataKeyValue("subject", "kubemq-targets-test.foo").
+		SetMetadataKeyValue("stream", "bad-stream").
+		SetData([]byte("some-data")))
+	require.Error(t, err)
+
+	resp, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "kv_put").
+		SetMetadataKeyValue("bucket", "kubemq-targets-test").
+		SetMetadataKeyValue("key", "some-key").
+		SetData([]byte("some-value")))
+	require.NoError(t, err)
+	require.EqualValues(t, "1", resp.Metadata["revision"])
+
+	resp, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "kv_get").
+		SetMetadataKeyValue("bucket", "kubemq-targets-test").
+		SetMetadataKeyValue("key", "some-key"))
+	require.NoError(t, err)
+	require.EqualValues(t, []byte("some-value"), resp.Data)
+
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "kv_put").
+		SetMetadataKeyValue("bucket", "kubemq-targets-test").
+		SetMetadataKeyValue("key", "some-key").
+		SetMetadataKeyValue("revision", "5").
+		SetData([]byte("other-value")))
+	require.Error(t, err)
+
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "kv_delete").
+		SetMetadataKeyValue("bucket", "kubemq-targets-test").
+		SetMetadataKeyValue("key", "some-key"))
+	require.NoError(t, err)
+
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "kv_get").
+		SetMetadataKeyValue("bucket", "kubemq-targets-test").
+		SetMetadataKeyValue("key", "some-key"))
+	require.Error(t, err)
+}
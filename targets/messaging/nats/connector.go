@@ -1,6 +1,8 @@
 package nats
 
 import (
+	"math"
+
 	"github.com/kubemq-hub/builder/connector/common"
 )
 
@@ -77,12 +79,81 @@ func Connector() *common.Connector {
 						SetDefault(""),
 				}),
 		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("method").
+				SetKind("string").
+				SetDescription("Set NATS execution method").
+				SetOptions([]string{"publish", "request", "jetstream_publish", "kv_get", "kv_put", "kv_delete"}).
+				SetDefault("publish").
+				SetMust(false),
+		).
 		AddMetadata(
 			common.NewMetadata().
 				SetKind("string").
 				SetName("subject").
 				SetDescription("Set subject").
-				SetMust(true).
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("headers").
+				SetDescription("Set message headers as json object").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("int").
+				SetName("timeout_seconds").
+				SetDescription("Set request and jetstream publish timeout in seconds").
+				SetMust(false).
+				SetDefault("5").
+				SetMin(1).
+				SetMax(math.MaxInt32),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("stream").
+				SetDescription("Set expected jetstream stream name").
+				SetMust(false).
 				SetDefault(""),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("msg_id").
+				SetDescription("Set jetstream message id for duplicate detection").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("bucket").
+				SetDescription("Set key-value bucket name").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("key").
+				SetDescription("Set key-value key").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("int").
+				SetName("revision").
+				SetDescription("Set key-value revision to get or expected last revision on put and delete").
+				SetMust(false).
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32),
 		)
 }
@@ -2,20 +2,72 @@ package nats
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
+const defaultTimeoutSeconds = 5
+
+var methodsMap = map[string]string{
+	"":                  "publish",
+	"publish":           "publish",
+	"request":           "request",
+	"jetstream_publish": "jetstream_publish",
+	"kv_get":            "kv_get",
+	"kv_put":            "kv_put",
+	"kv_delete":         "kv_delete",
+}
+
 type metadata struct {
-	subject string
+	method   string
+	subject  string
+	headers  map[string]string
+	timeout  int
+	stream   string
+	msgId    string
+	bucket   string
+	key      string
+	revision uint64
 }
 
 func parseMetadata(meta types.Metadata) (metadata, error) {
 	m := metadata{}
 	var err error
-	m.subject, err = meta.MustParseString("subject")
+	m.method, err = meta.ParseStringMap("method", methodsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing method, %w", err)
+	}
+	switch m.method {
+	case "kv_get", "kv_put", "kv_delete":
+		m.bucket, err = meta.MustParseString("bucket")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing bucket name, %w", err)
+		}
+		m.key, err = meta.MustParseString("key")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing key name, %w", err)
+		}
+		revision, err := meta.ParseIntWithRange("revision", 0, 0, math.MaxInt32)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing revision, %w", err)
+		}
+		m.revision = uint64(revision)
+	default:
+		m.subject, err = meta.MustParseString("subject")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing subject name, %w", err)
+		}
+		m.headers, err = meta.MustParseJsonMap("headers")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing headers, %w", err)
+		}
+		m.stream = meta.ParseString("stream", "")
+		m.msgId = meta.ParseString("msg_id", "")
+	}
+	m.timeout, err = meta.ParseIntWithRange("timeout_seconds", defaultTimeoutSeconds, 1, math.MaxInt32)
 	if err != nil {
-		return metadata{}, fmt.Errorf("error parsing subject name, %w", err)
+		return metadata{}, fmt.Errorf("error parsing timeout seconds, %w", err)
 	}
 	return m, nil
 }
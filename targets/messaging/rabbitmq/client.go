@@ -4,24 +4,33 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/kubemq-hub/builder/connector/common"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/pkg/logger"
+	"github.com/kubemq-io/kubemq-targets/pkg/tracing"
+	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/streadway/amqp"
 )
 
 type Client struct {
 	sync.Mutex
-	log         *logger.Logger
-	opts        options
-	channel     *amqp.Channel
-	conn        *amqp.Connection
-	isConnected bool
+	log            *logger.Logger
+	opts           options
+	channel        *amqp.Channel
+	conn           *amqp.Connection
+	isConnected    bool
+	confirmChannel *amqp.Channel
+	confirms       chan amqp.Confirmation
+	returns        chan amqp.Return
+	deliveryTag    uint64
 }
 
 func New() *Client {
@@ -72,6 +81,10 @@ func (c *Client) getTLSConfig() (*tls.Config, error) {
 }
 
 func (c *Client) connect() error {
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+	c.confirmChannel = nil
 	if strings.HasPrefix(c.opts.url, "amqps://") {
 		tlsCfg, err := c.getTLSConfig()
 		if err != nil {
@@ -108,6 +121,9 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 			return nil, err
 		}
 	}
+	if meta.method == "rpc" {
+		return c.RPC(ctx, meta, req.Data)
+	}
 	return c.Publish(ctx, meta, req.Data)
 }
 
@@ -120,6 +136,10 @@ func (c *Client) Publish(ctx context.Context, meta metadata, data []byte) (*type
 		}
 	}
 	msg := meta.amqpMessage(data)
+	tracing.Inject(ctx, headersCarrier(msg.Headers))
+	if meta.confirm {
+		return c.publishWithConfirm(ctx, meta, msg)
+	}
 	err := c.channel.Publish(meta.exchange, meta.queue, meta.mandatory, meta.immediate, msg)
 	if err != nil {
 		c.isConnected = false
@@ -130,7 +150,173 @@ func (c *Client) Publish(ctx context.Context, meta metadata, data []byte) (*type
 		nil
 }
 
+// getConfirmChannel returns the channel used for confirmed publishing, it is opened on first use so
+// bindings that never ask for confirms keep publishing on a plain channel
+func (c *Client) getConfirmChannel() (*amqp.Channel, error) {
+	if c.confirmChannel != nil {
+		return c.confirmChannel, nil
+	}
+	ch, err := c.conn.Channel()
+	if err != nil {
+		c.isConnected = false
+		return nil, fmt.Errorf("error getting rabbitmq channel, %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		_ = ch.Close()
+		return nil, fmt.Errorf("error setting rabbitmq channel to confirm mode, %w", err)
+	}
+	c.confirms = ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	c.returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	c.deliveryTag = 0
+	c.confirmChannel = ch
+	return ch, nil
+}
+
+func (c *Client) closeConfirmChannel() {
+	if c.confirmChannel != nil {
+		_ = c.confirmChannel.Close()
+		c.confirmChannel = nil
+	}
+}
+
+// publishWithConfirm publishes the message and waits for the broker ack, a mandatory message returned by the broker
+// is received before its ack, so it is checked once the ack arrives
+func (c *Client) publishWithConfirm(ctx context.Context, meta metadata, msg amqp.Publishing) (*types.Response, error) {
+	ch, err := c.getConfirmChannel()
+	if err != nil {
+		return nil, err
+	}
+	for len(c.returns) > 0 {
+		<-c.returns
+	}
+	if err := ch.Publish(meta.exchange, meta.queue, meta.mandatory, meta.immediate, msg); err != nil {
+		c.closeConfirmChannel()
+		c.isConnected = false
+		return nil, err
+	}
+	c.deliveryTag++
+	timer := time.NewTimer(c.opts.confirmTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case confirm, ok := <-c.confirms:
+			if !ok {
+				c.closeConfirmChannel()
+				return nil, fmt.Errorf("rabbitmq channel was closed before publish confirmation")
+			}
+			if confirm.DeliveryTag < c.deliveryTag {
+				continue
+			}
+			if !confirm.Ack {
+				return nil, fmt.Errorf("message was nacked by the broker")
+			}
+			select {
+			case ret, ok := <-c.returns:
+				if ok {
+					return nil, fmt.Errorf("message was returned by the broker, %d %s", ret.ReplyCode, ret.ReplyText)
+				}
+			default:
+			}
+			return types.NewResponse().
+					SetMetadataKeyValue("delivery_tag", strconv.FormatUint(confirm.DeliveryTag, 10)).
+					SetMetadataKeyValue("result", "ok"),
+				nil
+		case <-timer.C:
+			c.closeConfirmChannel()
+			return nil, fmt.Errorf("timeout waiting for publish confirmation")
+		case <-ctx.Done():
+			c.closeConfirmChannel()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// RPC publishes the message with a reply-to of a temporary exclusive queue and waits for the reply with the same correlation id
+func (c *Client) RPC(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	c.Lock()
+	if !c.isConnected {
+		if err := c.connect(); err != nil {
+			c.Unlock()
+			return nil, err
+		}
+	}
+	ch, err := c.conn.Channel()
+	if err != nil {
+		c.isConnected = false
+		c.Unlock()
+		return nil, fmt.Errorf("error getting rabbitmq channel, %w", err)
+	}
+	c.Unlock()
+	defer func() {
+		_ = ch.Close()
+	}()
+	replyQueue, err := ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error declaring reply queue, %w", err)
+	}
+	deliveries, err := ch.Consume(replyQueue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error consuming reply queue, %w", err)
+	}
+	var returns chan amqp.Return
+	if meta.mandatory {
+		returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	}
+	msg := meta.amqpMessage(data)
+	msg.ReplyTo = replyQueue.Name
+	if msg.CorrelationId == "" {
+		msg.CorrelationId = uuid.New().String()
+	}
+	tracing.Inject(ctx, headersCarrier(msg.Headers))
+	if err := ch.Publish(meta.exchange, meta.queue, meta.mandatory, meta.immediate, msg); err != nil {
+		return nil, err
+	}
+	timer := time.NewTimer(meta.timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return nil, fmt.Errorf("rabbitmq channel was closed before receiving a reply")
+			}
+			if delivery.CorrelationId != msg.CorrelationId {
+				continue
+			}
+			return replyResponse(delivery), nil
+		case ret, ok := <-returns:
+			if ok {
+				return nil, fmt.Errorf("message was returned by the broker, %d %s", ret.ReplyCode, ret.ReplyText)
+			}
+			returns = nil
+		case <-timer.C:
+			return nil, fmt.Errorf("timeout waiting for reply")
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func replyResponse(delivery amqp.Delivery) *types.Response {
+	resp := types.NewResponse().
+		SetData(delivery.Body).
+		SetMetadataKeyValue("correlation_id", delivery.CorrelationId).
+		SetMetadataKeyValue("content_type", delivery.ContentType).
+		SetMetadataKeyValue("message_id", delivery.MessageId)
+	if len(delivery.Headers) > 0 {
+		headers := map[string]string{}
+		for key, value := range delivery.Headers {
+			headers[key] = fmt.Sprintf("%v", value)
+		}
+		headersData, err := json.Marshal(headers)
+		if err == nil {
+			resp.SetMetadataKeyValue("headers", string(headersData))
+		}
+	}
+	return resp
+}
+
 func (c *Client) Stop() error {
+	c.closeConfirmChannel()
 	if c.channel != nil {
 		return c.channel.Close()
 	}
@@ -62,6 +62,33 @@ func Connector() *common.Connector {
 				SetMust(false).
 				SetDefault("true"),
 		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("bool").
+				SetName("default_confirm").
+				SetDescription("Set Default waiting for broker confirmation of routed message").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddProperty(
+			common.NewProperty().
+				SetKind("int").
+				SetName("confirm_timeout_seconds").
+				SetDescription("Set timeout for waiting for broker confirmation").
+				SetMust(false).
+				SetDefault("5").
+				SetMin(1).
+				SetMax(math.MaxInt32),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("method").
+				SetKind("string").
+				SetDescription("Set RabbitMQ execution method").
+				SetOptions([]string{"publish", "rpc"}).
+				SetDefault("publish").
+				SetMust(false),
+		).
 		AddMetadata(
 			common.NewMetadata().
 				SetName("queue").
@@ -138,5 +165,47 @@ func Connector() *common.Connector {
 				SetMust(true).
 				SetMin(0).
 				SetMax(math.MaxInt32),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("bool").
+				SetName("confirm").
+				SetDescription("Set RabbitMQ wait for broker confirmation").
+				SetMust(false).
+				SetDefault("false"),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("message_id").
+				SetKind("string").
+				SetDescription("Set RabbitMQ message id").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("content_type").
+				SetKind("string").
+				SetDescription("Set RabbitMQ content type").
+				SetDefault("text/plain").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("headers").
+				SetKind("string").
+				SetDescription("Set RabbitMQ message headers as json object").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("int").
+				SetName("timeout_seconds").
+				SetDescription("Set RabbitMQ rpc reply timeout in seconds").
+				SetMust(false).
+				SetDefault("30").
+				SetMin(1).
+				SetMax(math.MaxInt32),
 		)
 }
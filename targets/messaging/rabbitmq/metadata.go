@@ -10,26 +10,48 @@ import (
 	"github.com/streadway/amqp"
 )
 
+const (
+	defaultContentType    = "text/plain"
+	defaultTimeoutSeconds = 30
+)
+
+var methodsMap = map[string]string{
+	"":        "publish",
+	"publish": "publish",
+	"rpc":     "rpc",
+}
+
 type metadata struct {
+	method        string
 	queue         string
 	exchange      string
+	confirm       bool
 	mandatory     bool
 	immediate     bool
 	deliveryMode  int
 	priority      int
 	correlationId string
 	replyTo       string
+	messageId     string
+	contentType   string
+	headers       map[string]string
 	expiration    time.Duration
+	timeout       time.Duration
 }
 
 func parseMetadata(meta types.Metadata) (metadata, error) {
 	m := metadata{}
 	var err error
+	m.method, err = meta.ParseStringMap("method", methodsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing method, %w", err)
+	}
 	m.queue, err = meta.MustParseString("queue")
 	if err != nil {
 		return metadata{}, fmt.Errorf("error parsing queue name, %w", err)
 	}
 	m.exchange = meta.ParseString("exchange", "")
+	m.confirm = meta.ParseBool("confirm", false)
 	m.mandatory = meta.ParseBool("mandatory", false)
 	m.immediate = meta.ParseBool("immediate", false)
 	m.deliveryMode, err = meta.ParseIntWithRange("delivery_mode", 1, 0, 2)
@@ -42,24 +64,66 @@ func parseMetadata(meta types.Metadata) (metadata, error) {
 	}
 	m.correlationId = meta.ParseString("correlation_id", "")
 	m.replyTo = meta.ParseString("reply_to", "")
+	m.messageId = meta.ParseString("message_id", "")
+	m.contentType = meta.ParseString("content_type", defaultContentType)
+	m.headers, err = meta.MustParseJsonMap("headers")
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing headers, %w", err)
+	}
 	expirySeconds, err := meta.ParseIntWithRange("expiry_seconds", math.MaxInt32, 0, math.MaxInt32)
 	if err != nil {
 		return metadata{}, fmt.Errorf("error parsing expiry_seconds, %w", err)
 	}
 	m.expiration = time.Duration(expirySeconds) * time.Second
+	timeoutSeconds, err := meta.ParseIntWithRange("timeout_seconds", defaultTimeoutSeconds, 1, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing timeout_seconds, %w", err)
+	}
+	m.timeout = time.Duration(timeoutSeconds) * time.Second
 	return m, nil
 }
 
 func (m metadata) amqpMessage(data []byte) amqp.Publishing {
+	headers := amqp.Table{}
+	for key, value := range m.headers {
+		headers[key] = value
+	}
+	contentType := m.contentType
+	if contentType == "" {
+		contentType = defaultContentType
+	}
 	return amqp.Publishing{
-		Headers:         amqp.Table{},
-		ContentType:     "text/plain",
+		Headers:         headers,
+		ContentType:     contentType,
 		ContentEncoding: "",
 		DeliveryMode:    uint8(m.deliveryMode),
 		Priority:        uint8(m.priority),
 		CorrelationId:   m.correlationId,
 		ReplyTo:         m.replyTo,
+		MessageId:       m.messageId,
 		Expiration:      strconv.FormatInt(m.expiration.Milliseconds(), 10),
 		Body:            data,
 	}
 }
+
+// headersCarrier adapts rabbitmq message headers to a trace context carrier
+type headersCarrier amqp.Table
+
+func (hc headersCarrier) Get(key string) string {
+	if val, ok := hc[key].(string); ok {
+		return val
+	}
+	return ""
+}
+
+func (hc headersCarrier) Set(key, value string) {
+	hc[key] = value
+}
+
+func (hc headersCarrier) Keys() []string {
+	var keys []string
+	for key := range hc {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -2,15 +2,21 @@ package rabbitmq
 
 import (
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/kubemq-io/kubemq-targets/config"
 )
 
+const defaultConfirmTimeoutSeconds = 5
+
 type options struct {
 	url                string
 	defaultExchange    string
 	defaultTopic       string
 	defaultPersistence bool
+	defaultConfirm     bool
+	confirmTimeout     time.Duration
 	caCert             string
 	insecure           bool
 }
@@ -25,6 +31,12 @@ func parseOptions(cfg config.Spec) (options, error) {
 	o.defaultExchange = cfg.Properties.ParseString("default_exchange", "")
 	o.defaultTopic = cfg.Properties.ParseString("default_topic", "")
 	o.defaultPersistence = cfg.Properties.ParseBool("default_persistence", true)
+	o.defaultConfirm = cfg.Properties.ParseBool("default_confirm", false)
+	confirmTimeout, err := cfg.Properties.ParseIntWithRange("confirm_timeout_seconds", defaultConfirmTimeoutSeconds, 1, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing confirm timeout seconds, %w", err)
+	}
+	o.confirmTimeout = time.Duration(confirmTimeout) * time.Second
 	o.caCert = cfg.Properties.ParseString("ca_cert", "")
 	o.insecure = cfg.Properties.ParseBool("skip_insecure", false)
 	return o, nil
@@ -37,14 +49,17 @@ func (o options) defaultMetadata() (metadata, bool) {
 			delMode = 1
 		}
 		return metadata{
+			method:        "publish",
 			queue:         o.defaultTopic,
 			exchange:      o.defaultExchange,
+			confirm:       o.defaultConfirm,
 			mandatory:     false,
 			immediate:     false,
 			deliveryMode:  delMode,
 			priority:      0,
 			correlationId: "",
 			replyTo:       "",
+			contentType:   defaultContentType,
 			expiration:    0,
 		}, true
 	}
@@ -1,11 +1,16 @@
 package filesystem
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/kubemq-hub/builder/connector/common"
 	"github.com/kubemq-io/kubemq-targets/config"
@@ -13,9 +18,16 @@ import (
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
+const (
+	dirMode  = 0o755
+	fileMode = 0o600
+)
+
 type Client struct {
 	opts    options
 	absPath string
+	// realPath is the base path with its symlinks evaluated
+	realPath string
 }
 
 func New() *Client {
@@ -39,6 +51,10 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, log *logger.Logger)
 	if err != nil {
 		return err
 	}
+	c.realPath, err = filepath.EvalSymlinks(c.absPath)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -57,30 +73,81 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 		return c.Delete(ctx, meta)
 	case "list":
 		return c.List(ctx, meta)
+	case "append":
+		return c.Append(ctx, meta, req.Data)
+	case "move":
+		return c.Move(ctx, meta)
+	case "copy":
+		return c.Copy(ctx, meta)
+	case "stat":
+		return c.Stat(ctx, meta)
+	case "mkdir":
+		return c.Mkdir(ctx, meta)
 	}
 	return nil, nil
 }
 
 func (c *Client) Save(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
-	if _, err := os.Stat(filepath.Join(c.absPath, meta.path)); os.IsNotExist(err) {
-		err := os.MkdirAll(filepath.Join(c.absPath, meta.path), 0o600)
-		if err != nil {
-			return types.NewResponse().SetError(err), nil
-		}
-
+	fullPath, err := c.resolveEntry(meta.path, meta.filename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	sum := checksum(meta.checksumAlgorithm, data)
+	if err := verifyChecksum(meta.checksum, sum); err != nil {
+		return types.NewResponse().SetError(err), nil
 	}
-	fullPath := filepath.Join(c.absPath, meta.path, meta.filename)
-	err := ioutil.WriteFile(fullPath, data, 0o600)
+	if err := os.MkdirAll(filepath.Dir(fullPath), dirMode); err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	size, err := writeFileAtomic(fullPath, bytes.NewReader(data), nil)
 	if err != nil {
 		return types.NewResponse().SetError(err), nil
 	}
+	return types.NewResponse().
+			SetMetadataKeyValue("checksum", sum).
+			SetMetadataKeyValue("size", strconv.FormatInt(size, 10)).
+			SetMetadataKeyValue("result", "ok"),
+		nil
+}
 
-	return types.NewResponse().SetMetadataKeyValue("result", "ok"), nil
+func (c *Client) Append(ctx context.Context, meta metadata, data []byte) (*types.Response, error) {
+	fullPath, err := c.resolveEntry(meta.path, meta.filename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	if err := verifyChecksum(meta.checksum, checksum(meta.checksumAlgorithm, data)); err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), dirMode); err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	f, err := os.OpenFile(fullPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fileMode)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("size", strconv.FormatInt(info.Size(), 10)).
+			SetMetadataKeyValue("result", "ok"),
+		nil
 }
 
 func (c *Client) Delete(ctx context.Context, meta metadata) (*types.Response, error) {
-	fullPath := filepath.Join(c.absPath, meta.path, meta.filename)
-	err := os.Remove(fullPath)
+	fullPath, err := c.resolveEntry(meta.path, meta.filename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	err = os.Remove(fullPath)
 	if err != nil {
 		return types.NewResponse().SetError(err), nil
 	}
@@ -88,33 +155,206 @@ func (c *Client) Delete(ctx context.Context, meta metadata) (*types.Response, er
 }
 
 func (c *Client) Load(ctx context.Context, meta metadata) (*types.Response, error) {
-	fullPath := filepath.Join(c.absPath, meta.path, meta.filename)
-	data, err := ioutil.ReadFile(fullPath)
+	fullPath, err := c.resolveEntry(meta.path, meta.filename)
 	if err != nil {
 		return types.NewResponse().SetError(err), nil
 	}
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	sum := checksum(meta.checksumAlgorithm, data)
+	if err := verifyChecksum(meta.checksum, sum); err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
 	return types.NewResponse().
+			SetMetadataKeyValue("checksum", sum).
 			SetMetadataKeyValue("result", "ok").
 			SetData(data),
 		nil
 }
 
+func (c *Client) Move(ctx context.Context, meta metadata) (*types.Response, error) {
+	source, err := c.resolveEntry(meta.path, meta.filename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	destination, err := c.resolveEntry(meta.destinationPath, meta.destinationFilename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	if _, err := os.Stat(source); err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(destination), dirMode); err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	if err := os.Rename(source, destination); err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	return types.NewResponse().SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (c *Client) Copy(ctx context.Context, meta metadata) (*types.Response, error) {
+	source, err := c.resolveEntry(meta.path, meta.filename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	destination, err := c.resolveEntry(meta.destinationPath, meta.destinationFilename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	if info.IsDir() {
+		return types.NewResponse().SetError(fmt.Errorf("%s is a directory", meta.filename)), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(destination), dirMode); err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	h := newHash(meta.checksumAlgorithm)
+	sum := ""
+	size, err := writeFileAtomic(destination, io.TeeReader(f, h), func() error {
+		sum = hex.EncodeToString(h.Sum(nil))
+		return verifyChecksum(meta.checksum, sum)
+	})
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	return types.NewResponse().
+			SetMetadataKeyValue("checksum", sum).
+			SetMetadataKeyValue("size", strconv.FormatInt(size, 10)).
+			SetMetadataKeyValue("result", "ok"),
+		nil
+}
+
+func (c *Client) Stat(ctx context.Context, meta metadata) (*types.Response, error) {
+	fullPath, err := c.resolve(meta.path, meta.filename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	relPath, _ := filepath.Rel(c.absPath, fullPath)
+	return types.NewResponse().
+			SetMetadataKeyValue("result", "ok").
+			SetData(newFromOSFileInfo(info, fullPath, relPath).Marshal()),
+		nil
+}
+
+func (c *Client) Mkdir(ctx context.Context, meta metadata) (*types.Response, error) {
+	fullPath, err := c.resolve(meta.path, meta.filename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	if err := os.MkdirAll(fullPath, dirMode); err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	return types.NewResponse().SetMetadataKeyValue("result", "ok"), nil
+}
+
+// List lists the entries under the requested path down to max_depth levels, sorted by path. Entries are filtered by the glob pattern
+// and paged by offset and limit
 func (c *Client) List(ctx context.Context, meta metadata) (*types.Response, error) {
-	var list FileInfoList
-	err := filepath.Walk(c.absPath, func(path string, info os.FileInfo, err error) error {
+	root, err := c.resolve(meta.path, meta.filename)
+	if err != nil {
+		return types.NewResponse().SetError(err), nil
+	}
+	list := FileInfoList{}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		list = append(list, newFromOSFileInfo(info, path))
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if path == root {
+			if !d.IsDir() {
+				return fmt.Errorf("%s is not a directory", filepath.Join(meta.path, meta.filename))
+			}
+			return nil
+		}
+		relPath, _ := filepath.Rel(root, path)
+		depth := strings.Count(filepath.ToSlash(relPath), "/") + 1
+		if meta.maxDepth > 0 && depth > meta.maxDepth {
+			return fs.SkipDir
+		}
+		matched := true
+		if meta.pattern != "" {
+			matched, _ = matchPattern(meta.pattern, relPath)
+		}
+		if matched {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			basePath, _ := filepath.Rel(c.absPath, path)
+			list = append(list, newFromOSFileInfo(info, path, basePath))
+		}
+		if d.IsDir() && meta.maxDepth > 0 && depth == meta.maxDepth {
+			return fs.SkipDir
+		}
 		return nil
 	})
 	if err != nil {
 		return types.NewResponse().SetError(err), nil
 	}
-	return types.NewResponse().
-			SetMetadataKeyValue("result", "ok").
-			SetData(list.Marshal()),
-		nil
+	total := len(list)
+	if meta.offset < len(list) {
+		list = list[meta.offset:]
+	} else {
+		list = FileInfoList{}
+	}
+	resp := types.NewResponse().
+		SetMetadataKeyValue("total", strconv.Itoa(total)).
+		SetMetadataKeyValue("result", "ok")
+	if meta.limit > 0 && len(list) > meta.limit {
+		list = list[:meta.limit]
+		resp.SetMetadataKeyValue("next_offset", strconv.Itoa(meta.offset+meta.limit))
+	}
+	return resp.SetData(list.Marshal()), nil
+}
+
+// writeFileAtomic writes the reader to a temporary file in the destination directory and renames it to the destination once
+// the write and the optional verify function succeed, readers of the destination never see a partial file
+func writeFileAtomic(path string, r io.Reader, verify func() error) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return 0, err
+	}
+	tmpName := tmp.Name()
+	size, err := io.Copy(tmp, r)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && verify != nil {
+		err = verify()
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, fileMode)
+	}
+	if err == nil {
+		err = os.Rename(tmpName, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpName)
+		return 0, err
+	}
+	return size, nil
 }
 
 func (c *Client) Stop() error {
@@ -2,6 +2,12 @@ package filesystem
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -36,7 +42,7 @@ func TestClient_Init(t *testing.T) {
 					"base_path": "",
 				},
 			},
-			wantErr: false,
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -60,7 +66,7 @@ func TestClient_Files(t *testing.T) {
 		Name: "filesystem-target",
 		Kind: "",
 		Properties: map[string]string{
-			"base_path": "../../../test-fs",
+			"base_path": t.TempDir(),
 		},
 	}
 	c := New()
@@ -94,7 +100,7 @@ func TestClient_Files(t *testing.T) {
 
 	saveErr := types.NewRequest().
 		SetMetadataKeyValue("method", "save").
-		SetMetadataKeyValue("path", "*").
+		SetMetadataKeyValue("path", "../outside").
 		SetMetadataKeyValue("filename", "bad-filename")
 	resp, err = c.Do(ctx, saveErr)
 	require.NoError(t, err)
@@ -116,3 +122,254 @@ func TestClient_Files(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, true, resp.IsError)
 }
+
+func newTestClient(t *testing.T) (*Client, string) {
+	basePath := t.TempDir()
+	c := New()
+	err := c.Init(context.Background(), config.Spec{
+		Name: "filesystem-target",
+		Kind: "",
+		Properties: map[string]string{
+			"base_path": basePath,
+		},
+	}, nil)
+	require.NoError(t, err)
+	return c, basePath
+}
+
+func TestClient_List(t *testing.T) {
+	c, basePath := newTestClient(t)
+	ctx := context.Background()
+	for _, name := range []string{"a/1.txt", "a/2.log", "a/b/3.txt", "a/b/c/4.txt", "other/5.txt"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(basePath, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(basePath, name), []byte(name), 0o600))
+	}
+	tests := []struct {
+		name          string
+		metadata      map[string]string
+		wantPaths     []string
+		wantTotal     string
+		wantNext      string
+		wantErrorResp bool
+	}{
+		{
+			name:      "direct children",
+			metadata:  map[string]string{"path": "a"},
+			wantPaths: []string{"a/1.txt", "a/2.log", "a/b"},
+			wantTotal: "3",
+		},
+		{
+			name:      "recursive with pattern",
+			metadata:  map[string]string{"path": "a", "max_depth": "0", "pattern": "*.txt"},
+			wantPaths: []string{"a/1.txt", "a/b/3.txt", "a/b/c/4.txt"},
+			wantTotal: "3",
+		},
+		{
+			name:      "depth two",
+			metadata:  map[string]string{"path": "a", "max_depth": "2", "pattern": "*.txt"},
+			wantPaths: []string{"a/1.txt", "a/b/3.txt"},
+			wantTotal: "2",
+		},
+		{
+			name:      "path pattern",
+			metadata:  map[string]string{"path": "a", "max_depth": "0", "pattern": "b/*/*.txt"},
+			wantPaths: []string{"a/b/c/4.txt"},
+			wantTotal: "1",
+		},
+		{
+			name:      "first page",
+			metadata:  map[string]string{"max_depth": "0", "pattern": "*.txt", "limit": "2"},
+			wantPaths: []string{"a/1.txt", "a/b/3.txt"},
+			wantTotal: "4",
+			wantNext:  "2",
+		},
+		{
+			name:      "last page",
+			metadata:  map[string]string{"max_depth": "0", "pattern": "*.txt", "limit": "2", "offset": "2"},
+			wantPaths: []string{"a/b/c/4.txt", "other/5.txt"},
+			wantTotal: "4",
+		},
+		{
+			name:          "not a directory",
+			metadata:      map[string]string{"path": "a", "filename": "1.txt"},
+			wantErrorResp: true,
+		},
+		{
+			name:          "outside of base path",
+			metadata:      map[string]string{"path": "../"},
+			wantErrorResp: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := types.NewRequest().SetMetadataKeyValue("method", "list")
+			for key, value := range tt.metadata {
+				req.SetMetadataKeyValue(key, value)
+			}
+			resp, err := c.Do(ctx, req)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantErrorResp, resp.IsError)
+			if tt.wantErrorResp {
+				return
+			}
+			var list FileInfoList
+			require.NoError(t, json.Unmarshal(resp.Data, &list))
+			var paths []string
+			for _, fi := range list {
+				paths = append(paths, fi.Path)
+			}
+			require.Equal(t, tt.wantPaths, paths)
+			require.Equal(t, tt.wantTotal, resp.Metadata["total"])
+			require.Equal(t, tt.wantNext, resp.Metadata["next_offset"])
+		})
+	}
+}
+
+func TestClient_Operations(t *testing.T) {
+	c, basePath := newTestClient(t)
+	ctx := context.Background()
+	do := func(data []byte, kv ...string) *types.Response {
+		req := types.NewRequest().SetData(data)
+		for i := 0; i < len(kv); i += 2 {
+			req.SetMetadataKeyValue(kv[i], kv[i+1])
+		}
+		resp, err := c.Do(ctx, req)
+		require.NoError(t, err)
+		return resp
+	}
+	sum := sha256.Sum256([]byte("hello"))
+	resp := do([]byte("hello"), "method", "save", "path", "dir/sub", "filename", "f.txt", "checksum", hex.EncodeToString(sum[:]))
+	require.False(t, resp.IsError, resp.Error)
+	require.Equal(t, "5", resp.Metadata["size"])
+	info, err := os.Stat(filepath.Join(basePath, "dir", "sub"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	entries, err := os.ReadDir(filepath.Join(basePath, "dir", "sub"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	resp = do([]byte("hello"), "method", "save", "path", "dir", "filename", "bad.txt", "checksum", "00")
+	require.True(t, resp.IsError)
+	_, err = os.Stat(filepath.Join(basePath, "dir", "bad.txt"))
+	require.True(t, os.IsNotExist(err))
+
+	resp = do([]byte(" world"), "method", "append", "path", "dir/sub", "filename", "f.txt")
+	require.False(t, resp.IsError, resp.Error)
+	require.Equal(t, "11", resp.Metadata["size"])
+
+	resp = do(nil, "method", "copy", "path", "dir/sub", "filename", "f.txt", "destination_path", "copies", "checksum_algorithm", "md5")
+	require.False(t, resp.IsError, resp.Error)
+	md5Sum := md5.Sum([]byte("hello world"))
+	require.Equal(t, hex.EncodeToString(md5Sum[:]), resp.Metadata["checksum"])
+
+	resp = do(nil, "method", "copy", "path", "dir/sub", "filename", "f.txt", "destination_filename", "g.txt", "checksum", "00")
+	require.True(t, resp.IsError)
+	_, err = os.Stat(filepath.Join(basePath, "dir", "sub", "g.txt"))
+	require.True(t, os.IsNotExist(err))
+
+	resp = do(nil, "method", "move", "path", "copies", "filename", "f.txt", "destination_path", "moved", "destination_filename", "m.txt")
+	require.False(t, resp.IsError, resp.Error)
+	resp = do(nil, "method", "load", "path", "moved", "filename", "m.txt")
+	require.False(t, resp.IsError, resp.Error)
+	require.Equal(t, []byte("hello world"), resp.Data)
+
+	resp = do(nil, "method", "stat", "path", "moved", "filename", "m.txt")
+	require.False(t, resp.IsError, resp.Error)
+	fi := FileInfo{}
+	require.NoError(t, json.Unmarshal(resp.Data, &fi))
+	require.Equal(t, "moved/m.txt", fi.Path)
+	require.Equal(t, int64(11), fi.Size)
+	require.False(t, fi.IsDir)
+
+	resp = do(nil, "method", "mkdir", "path", "x/y/z")
+	require.False(t, resp.IsError, resp.Error)
+	resp = do(nil, "method", "stat", "path", "x/y/z")
+	require.False(t, resp.IsError, resp.Error)
+	require.NoError(t, json.Unmarshal(resp.Data, &fi))
+	require.True(t, fi.IsDir)
+}
+
+func TestClient_PathEscape(t *testing.T) {
+	c, basePath := newTestClient(t)
+	ctx := context.Background()
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o600))
+	require.NoError(t, os.Symlink(outside, filepath.Join(basePath, "link")))
+	tests := []struct {
+		name     string
+		metadata map[string]string
+	}{
+		{
+			name:     "load parent path",
+			metadata: map[string]string{"method": "load", "path": "../", "filename": "secret.txt"},
+		},
+		{
+			name:     "load parent filename",
+			metadata: map[string]string{"method": "load", "filename": "../../secret.txt"},
+		},
+		{
+			name:     "load through symlink",
+			metadata: map[string]string{"method": "load", "path": "link", "filename": "secret.txt"},
+		},
+		{
+			name:     "save through symlink",
+			metadata: map[string]string{"method": "save", "path": "link/new", "filename": "f.txt"},
+		},
+		{
+			name:     "copy destination outside",
+			metadata: map[string]string{"method": "copy", "path": "", "filename": "f.txt", "destination_path": "../.."},
+		},
+		{
+			name:     "move destination through symlink",
+			metadata: map[string]string{"method": "move", "path": "", "filename": "f.txt", "destination_path": "link"},
+		},
+		{
+			name:     "delete base path",
+			metadata: map[string]string{"method": "delete", "path": "", "filename": "."},
+		},
+		{
+			name:     "delete base path from sub directory",
+			metadata: map[string]string{"method": "delete", "path": "sub", "filename": ".."},
+		},
+		{
+			name:     "save base path",
+			metadata: map[string]string{"method": "save", "path": "sub/..", "filename": "."},
+		},
+		{
+			name:     "move base path",
+			metadata: map[string]string{"method": "move", "path": "", "filename": ".", "destination_path": "moved", "destination_filename": "dir"},
+		},
+		{
+			name:     "copy destination base path",
+			metadata: map[string]string{"method": "copy", "path": "", "filename": "f.txt", "destination_path": "", "destination_filename": "."},
+		},
+		{
+			name:     "mkdir outside",
+			metadata: map[string]string{"method": "mkdir", "path": "../escape"},
+		},
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(basePath, "f.txt"), []byte("data"), 0o600))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := types.NewRequest().SetData([]byte("data"))
+			for key, value := range tt.metadata {
+				req.SetMetadataKeyValue(key, value)
+			}
+			resp, err := c.Do(ctx, req)
+			require.NoError(t, err)
+			require.True(t, resp.IsError)
+			require.Contains(t, resp.Error, "base path")
+		})
+	}
+	_, err := os.Stat(filepath.Join(outside, "new"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(basePath, "f.txt"))
+	require.NoError(t, err)
+	_, err = os.Stat(basePath)
+	require.NoError(t, err)
+	for _, method := range []string{"list", "stat", "mkdir"} {
+		resp, err := c.Do(ctx, types.NewRequest().SetMetadataKeyValue("method", method))
+		require.NoError(t, err)
+		require.False(t, resp.IsError, method)
+	}
+}
@@ -1,6 +1,8 @@
 package filesystem
 
 import (
+	"math"
+
 	"github.com/kubemq-hub/builder/connector/common"
 )
 
@@ -26,7 +28,7 @@ func Connector() *common.Connector {
 				SetName("method").
 				SetKind("string").
 				SetDescription("Set file system method").
-				SetOptions([]string{"save", "load", "delete", "list"}).
+				SetOptions([]string{"save", "load", "delete", "list", "append", "move", "copy", "stat", "mkdir"}).
 				SetDefault("").
 				SetMust(true),
 		).
@@ -42,8 +44,79 @@ func Connector() *common.Connector {
 			common.NewMetadata().
 				SetName("filename").
 				SetKind("string").
-				SetDescription("Set filename").
+				SetDescription("Set filename, optional for list, stat and mkdir").
 				SetDefault("").
-				SetMust(true),
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("destination_path").
+				SetKind("string").
+				SetDescription("Set move and copy destination path").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("destination_filename").
+				SetKind("string").
+				SetDescription("Set move and copy destination filename").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("pattern").
+				SetKind("string").
+				SetDescription("Set list glob pattern filter").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("max_depth").
+				SetKind("int").
+				SetDescription("Set list recursion depth, 0 for unlimited").
+				SetDefault("1").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("offset").
+				SetKind("int").
+				SetDescription("Set list entries to skip").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("limit").
+				SetKind("int").
+				SetDescription("Set list max entries, 0 for no limit").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("checksum").
+				SetKind("string").
+				SetDescription("Set expected hex checksum of the file data").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("checksum_algorithm").
+				SetKind("string").
+				SetDescription("Set checksum algorithm").
+				SetOptions([]string{"sha256", "md5"}).
+				SetDefault("sha256").
+				SetMust(false),
 		)
 }
@@ -4,26 +4,38 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type FileInfo struct {
-	Name     string `json:"name"`
-	FullPath string `json:"full_path"`
-	Size     int64  `json:"size"`
-	IsDir    bool   `json:"is_dir"`
+	Name     string    `json:"name"`
+	Path     string    `json:"path"`
+	FullPath string    `json:"full_path"`
+	Size     int64     `json:"size"`
+	IsDir    bool      `json:"is_dir"`
+	Mode     string    `json:"mode"`
+	ModTime  time.Time `json:"mod_time"`
 }
 
-func newFromOSFileInfo(f os.FileInfo, path string) *FileInfo {
+func newFromOSFileInfo(f os.FileInfo, path string, relPath string) *FileInfo {
 	fi := &FileInfo{
 		Name:     f.Name(),
+		Path:     filepath.ToSlash(relPath),
 		FullPath: "",
 		Size:     f.Size(),
 		IsDir:    f.IsDir(),
+		Mode:     f.Mode().String(),
+		ModTime:  f.ModTime().UTC(),
 	}
 	fi.FullPath, _ = filepath.Abs(path)
 	return fi
 }
 
+func (fi *FileInfo) Marshal() []byte {
+	data, _ := json.Marshal(fi)
+	return data
+}
+
 type FileInfoList []*FileInfo
 
 func (l FileInfoList) Marshal() []byte {
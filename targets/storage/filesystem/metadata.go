@@ -2,6 +2,8 @@ package filesystem
 
 import (
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/kubemq-io/kubemq-targets/types"
 )
@@ -11,12 +13,31 @@ var methodsMap = map[string]string{
 	"load":   "load",
 	"delete": "delete",
 	"list":   "list",
+	"append": "append",
+	"move":   "move",
+	"copy":   "copy",
+	"stat":   "stat",
+	"mkdir":  "mkdir",
+}
+
+var checksumAlgorithmsMap = map[string]string{
+	"":       "sha256",
+	"sha256": "sha256",
+	"md5":    "md5",
 }
 
 type metadata struct {
-	method   string
-	path     string
-	filename string
+	method              string
+	path                string
+	filename            string
+	destinationPath     string
+	destinationFilename string
+	pattern             string
+	maxDepth            int
+	offset              int
+	limit               int
+	checksum            string
+	checksumAlgorithm   string
 }
 
 func parseMetadata(meta types.Metadata) (metadata, error) {
@@ -26,11 +47,43 @@ func parseMetadata(meta types.Metadata) (metadata, error) {
 	if err != nil {
 		return metadata{}, fmt.Errorf("error parsing method, %w", err)
 	}
-	m.filename, err = meta.MustParseString("filename")
-	if err != nil {
-		return metadata{}, fmt.Errorf("error parsing filename, %w", err)
+	switch m.method {
+	case "list", "stat", "mkdir":
+		m.filename = meta.ParseString("filename", "")
+	default:
+		m.filename, err = meta.MustParseString("filename")
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing filename, %w", err)
+		}
 	}
 	m.path = meta.ParseString("path", "")
 	m.path = unixNormalize(m.path)
+	if m.method == "move" || m.method == "copy" {
+		m.destinationPath = unixNormalize(meta.ParseString("destination_path", m.path))
+		m.destinationFilename = meta.ParseString("destination_filename", m.filename)
+	}
+	m.pattern = meta.ParseString("pattern", "")
+	if m.pattern != "" {
+		if _, err := matchPattern(m.pattern, ""); err != nil {
+			return metadata{}, fmt.Errorf("error parsing pattern, %w", err)
+		}
+	}
+	m.maxDepth, err = meta.ParseIntWithRange("max_depth", 1, 0, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing max_depth, %w", err)
+	}
+	m.offset, err = meta.ParseIntWithRange("offset", 0, 0, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing offset, %w", err)
+	}
+	m.limit, err = meta.ParseIntWithRange("limit", 0, 0, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing limit, %w", err)
+	}
+	m.checksum = strings.ToLower(meta.ParseString("checksum", ""))
+	m.checksumAlgorithm, err = meta.ParseStringMap("checksum_algorithm", checksumAlgorithmsMap)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing checksum_algorithm, %w", err)
+	}
 	return m, nil
 }
@@ -0,0 +1,98 @@
+package filesystem
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolve joins the request path parts to the base path, the result must stay inside the base path also after following symlinks
+func (c *Client) resolve(parts ...string) (string, error) {
+	fullPath := filepath.Join(append([]string{c.absPath}, parts...)...)
+	if !isWithin(c.absPath, fullPath) {
+		return "", fmt.Errorf("path %s is outside of the base path", filepath.Join(parts...))
+	}
+	realPath, err := evalExistingSymlinks(fullPath)
+	if err != nil {
+		return "", err
+	}
+	if !isWithin(c.realPath, realPath) {
+		return "", fmt.Errorf("path %s is outside of the base path", filepath.Join(parts...))
+	}
+	return fullPath, nil
+}
+
+// resolveEntry resolves the path of a file or a directory in the base path, the base path itself is rejected
+// so only list, stat and mkdir requests can address it
+func (c *Client) resolveEntry(parts ...string) (string, error) {
+	fullPath, err := c.resolve(parts...)
+	if err != nil {
+		return "", err
+	}
+	if fullPath == c.absPath {
+		return "", fmt.Errorf("path %s is the base path", filepath.Join(parts...))
+	}
+	return fullPath, nil
+}
+
+func isWithin(base, target string) bool {
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// evalExistingSymlinks evaluates the symlinks of the longest existing prefix of the path and appends the rest of the path to it
+func evalExistingSymlinks(path string) (string, error) {
+	var rest []string
+	current := path
+	for {
+		realPath, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			return filepath.Join(append([]string{realPath}, rest...)...), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return path, nil
+		}
+		rest = append([]string{filepath.Base(current)}, rest...)
+		current = parent
+	}
+}
+
+// matchPattern matches a glob pattern against the file name, patterns with a separator are matched against the path relative to the listed directory
+func matchPattern(pattern, relPath string) (bool, error) {
+	if strings.Contains(pattern, "/") {
+		return filepath.Match(pattern, filepath.ToSlash(relPath))
+	}
+	return filepath.Match(pattern, filepath.Base(relPath))
+}
+
+func newHash(algorithm string) hash.Hash {
+	if algorithm == "md5" {
+		return md5.New()
+	}
+	return sha256.New()
+}
+
+func checksum(algorithm string, data []byte) string {
+	h := newHash(algorithm)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func verifyChecksum(expected, actual string) error {
+	if expected != "" && expected != actual {
+		return fmt.Errorf("checksum mismatch, expected %s, got %s", expected, actual)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	consul "github.com/hashicorp/consul/api"
 	"github.com/kubemq-hub/builder/connector/common"
@@ -16,6 +17,10 @@ import (
 	"github.com/kubemq-io/kubemq-targets/types"
 )
 
+// lockDelay is the delay before a lock released by an expired session can be acquired again. Consul applies its
+// 15 seconds default when the session is created with a zero delay, so the shortest delay it accepts is set instead
+const lockDelay = time.Millisecond
+
 // Client is a Client state store
 type Client struct {
 	log    *logger.Logger
@@ -68,7 +73,12 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 		return c.put(ctx, meta, req.Data)
 	case "delete":
 		return c.delete(ctx, meta)
-
+	case "lock":
+		return c.lock(ctx, meta)
+	case "unlock":
+		return c.unlock(ctx, meta)
+	case "renew":
+		return c.renew(ctx, meta)
 	}
 	return nil, nil
 }
@@ -171,6 +181,113 @@ func (c *Client) list(ctx context.Context, meta metadata) (*types.Response, erro
 		SetMetadataKeyValue("key", meta.key), nil
 }
 
+// lock acquires the key with a new session as the lock token, a request with the token of the lock owner renews the lock
+func (c *Client) lock(ctx context.Context, meta metadata) (*types.Response, error) {
+	if meta.token != "" {
+		return c.renew(ctx, meta)
+	}
+	o := c.createWriteOptions(ctx)
+	id, _, err := c.client.Session().Create(&consul.SessionEntry{
+		Name:      fmt.Sprintf("lock %s", meta.key),
+		TTL:       meta.ttl.String(),
+		Behavior:  consul.SessionBehaviorDelete,
+		LockDelay: lockDelay,
+	}, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lock session for key %s, %w", meta.key, err)
+	}
+	meta.token = id
+	acquired, _, err := c.client.KV().Acquire(&consul.KVPair{
+		Key:     meta.key,
+		Session: meta.token,
+	}, o)
+	if err == nil && !acquired {
+		err = fmt.Errorf("lock %s is held by another owner", meta.key)
+	}
+	if err != nil {
+		_, _ = c.client.Session().Destroy(meta.token, c.createWriteOptions(context.Background()))
+		return nil, err
+	}
+	return lockResponse(meta, time.Now().Add(meta.ttl)), nil
+}
+
+// unlock deletes the key when it is held by the lock token and destroys the token session
+func (c *Client) unlock(ctx context.Context, meta metadata) (*types.Response, error) {
+	ok, _, _, err := c.client.KV().Txn(consul.KVTxnOps{
+		&consul.KVTxnOp{
+			Verb:    consul.KVCheckSession,
+			Key:     meta.key,
+			Session: meta.token,
+		},
+		&consul.KVTxnOp{
+			Verb: consul.KVDelete,
+			Key:  meta.key,
+		},
+	}, c.createLockQueryOptions(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock key %s, %w", meta.key, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("lock %s is not held by this token", meta.key)
+	}
+	if _, err := c.client.Session().Destroy(meta.token, c.createWriteOptions(ctx)); err != nil {
+		return nil, fmt.Errorf("lock %s released, failed to destroy its session, %w", meta.key, err)
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("result", "ok").
+		SetMetadataKeyValue("token", meta.token).
+		SetMetadataKeyValue("key", meta.key), nil
+}
+
+// renew renews the lock token session when the key is held by it
+func (c *Client) renew(ctx context.Context, meta metadata) (*types.Response, error) {
+	kvp, _, err := c.client.KV().Get(meta.key, c.createLockQueryOptions(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew lock key %s, %w", meta.key, err)
+	}
+	if kvp == nil || kvp.Session != meta.token {
+		return nil, fmt.Errorf("lock %s is not held by this token", meta.key)
+	}
+	return c.renewSession(ctx, meta)
+}
+
+// renewSession renews the session with the ttl it was created with, consul cannot change a session ttl,
+// so a request ttl_seconds which differs from the session ttl fails the request
+func (c *Client) renewSession(ctx context.Context, meta metadata) (*types.Response, error) {
+	if meta.ttlSet {
+		info, _, err := c.client.Session().Info(meta.token, c.createLockQueryOptions(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lock session for key %s, %w", meta.key, err)
+		}
+		if info == nil {
+			return nil, fmt.Errorf("lock %s is not held by this token", meta.key)
+		}
+		if ttl, err := time.ParseDuration(info.TTL); err == nil && ttl != meta.ttl {
+			return nil, fmt.Errorf("lock %s ttl is %s, ttl_seconds cannot be changed after the lock is created", meta.key, ttl)
+		}
+	}
+	entry, _, err := c.client.Session().Renew(meta.token, c.createWriteOptions(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew lock session for key %s, %w", meta.key, err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("lock %s is not held by this token", meta.key)
+	}
+	ttl, err := time.ParseDuration(entry.TTL)
+	if err != nil {
+		ttl = meta.ttl
+	}
+	return lockResponse(meta, time.Now().Add(ttl)), nil
+}
+
+func lockResponse(meta metadata, expiresAt time.Time) *types.Response {
+	return types.NewResponse().
+		SetMetadataKeyValue("result", "ok").
+		SetMetadataKeyValue("token", meta.token).
+		SetMetadataKeyValue("expires_at", expiresAt.UTC().Format(time.RFC3339Nano)).
+		SetMetadataKeyValue("key", meta.key)
+}
+
 func setConfig(opts options) (*consul.Config, error) {
 	c := &consul.Config{}
 	if opts.address != "" {
@@ -260,3 +377,8 @@ func (c *Client) createQueryOptions(ctx context.Context, meta metadata) *consul.
 
 	return o
 }
+
+// createLockQueryOptions returns consistent read options for the lock methods, ignoring the request cache settings
+func (c *Client) createLockQueryOptions(ctx context.Context) *consul.QueryOptions {
+	return c.createQueryOptions(ctx, metadata{requireConsistent: true})
+}
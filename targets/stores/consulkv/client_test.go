@@ -250,3 +250,80 @@ func TestClient_List(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Lock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New()
+	err := c.Init(ctx, config.Spec{
+		Name: "stores-consulkv",
+		Kind: "stores.consulkv",
+		Properties: map[string]string{
+			"address": "localhost:8500",
+		},
+	}, nil)
+	require.NoError(t, err)
+	lockRequest := types.NewRequest().
+		SetMetadataKeyValue("method", "lock").
+		SetMetadataKeyValue("key", "some-lock")
+	resp, err := c.Do(ctx, lockRequest)
+	require.NoError(t, err)
+	token := resp.Metadata["token"]
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, resp.Metadata["expires_at"])
+	_, err = c.Do(ctx, lockRequest)
+	require.Error(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "lock").
+		SetMetadataKeyValue("key", "other-lock").
+		SetMetadataKeyValue("token", token))
+	require.Error(t, err)
+
+	resp, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "renew").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("token", token))
+	require.NoError(t, err)
+	require.Equal(t, token, resp.Metadata["token"])
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "renew").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("token", token).
+		SetMetadataKeyValue("ttl_seconds", "60"))
+	require.Error(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "renew").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("token", token).
+		SetMetadataKeyValue("ttl_seconds", "30"))
+	require.NoError(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "unlock").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("token", "bad-token"))
+	require.Error(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "unlock").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("token", token))
+	require.NoError(t, err)
+	session, _, err := c.client.Session().Info(token, nil)
+	require.NoError(t, err)
+	require.Nil(t, session)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "renew").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("token", token))
+	require.Error(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "unlock").
+		SetMetadataKeyValue("key", "some-lock"))
+	require.Error(t, err)
+	resp, err = c.Do(ctx, lockRequest)
+	require.NoError(t, err)
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "unlock").
+		SetMetadataKeyValue("key", "some-lock").
+		SetMetadataKeyValue("token", resp.Metadata["token"]))
+	require.NoError(t, err)
+}
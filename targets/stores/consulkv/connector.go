@@ -107,6 +107,15 @@ func Connector() *common.Connector {
 						SetDefault(""),
 				}),
 		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("method").
+				SetDescription("Set execution method").
+				SetOptions([]string{"get", "put", "list", "delete", "lock", "unlock", "renew"}).
+				SetDefault("get").
+				SetMust(true),
+		).
 		AddMetadata(
 			common.NewMetadata().
 				SetKind("string").
@@ -190,5 +199,23 @@ func Connector() *common.Connector {
 				SetDefault("36000").
 				SetMin(1).
 				SetMax(math.MaxInt32),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("string").
+				SetName("token").
+				SetDescription("Set lock owner token of a lock response, required for unlock and renew").
+				SetMust(false).
+				SetDefault(""),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetKind("int").
+				SetName("ttl_seconds").
+				SetDescription("Set lock ttl in seconds").
+				SetMust(false).
+				SetDefault("30").
+				SetMin(10).
+				SetMax(86400),
 		)
 }
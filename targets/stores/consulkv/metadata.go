@@ -18,6 +18,12 @@ const (
 
 	defaultMaxAge       = 36000
 	defaultStaleIfError = 36000
+
+	defaultLockToken = ""
+	// consul session ttl is limited to 10 seconds up to 24 hours
+	defaultLockTTLSeconds = 30
+	minLockTTLSeconds     = 10
+	maxLockTTLSeconds     = 86400
 )
 
 var methodsMap = map[string]string{
@@ -25,6 +31,9 @@ var methodsMap = map[string]string{
 	"put":    "put",
 	"list":   "list",
 	"delete": "delete",
+	"lock":   "lock",
+	"unlock": "unlock",
+	"renew":  "renew",
 }
 
 type metadata struct {
@@ -38,6 +47,10 @@ type metadata struct {
 	useCache          bool
 	maxAge            time.Duration
 	staleIfError      time.Duration
+	token             string
+	ttl               time.Duration
+	// ttlSet is set when the request sets ttl_seconds
+	ttlSet bool
 }
 
 func parseMetadata(meta types.Metadata) (metadata, error) {
@@ -69,5 +82,22 @@ func parseMetadata(meta types.Metadata) (metadata, error) {
 	}
 	m.staleIfError = time.Duration(staleIfError) * time.Millisecond
 
+	switch m.method {
+	case "lock", "unlock", "renew":
+		if m.key == "" {
+			return metadata{}, fmt.Errorf("error parsing key, missing key")
+		}
+		m.token = meta.ParseString("token", defaultLockToken)
+		if m.method != "lock" && m.token == "" {
+			return metadata{}, fmt.Errorf("error parsing token, missing token")
+		}
+		ttl, err := meta.ParseIntWithRange("ttl_seconds", defaultLockTTLSeconds, minLockTTLSeconds, maxLockTTLSeconds)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing ttl_seconds, %w", err)
+		}
+		m.ttl = time.Duration(ttl) * time.Second
+		_, m.ttlSet = meta["ttl_seconds"]
+	}
+
 	return m, nil
 }
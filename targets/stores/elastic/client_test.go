@@ -3,6 +3,7 @@ package elastic
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -432,3 +433,63 @@ func TestClient_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Search_Bulk(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New()
+	err := c.Init(ctx, config.Spec{
+		Name: "elastic-target",
+		Kind: "",
+		Properties: map[string]string{
+			"urls":  "http://localhost:9200",
+			"sniff": "false",
+		},
+	}, nil)
+	require.NoError(t, err)
+	first := uuid.New().String()
+	second := uuid.New().String()
+	actions := fmt.Sprintf("{\"index\":{\"_id\":%q}}\n%s\n{\"index\":{\"_id\":%q}}\n%s\n", first, newLog(first, "bulk-data").marshal(), second, newLog(second, "bulk-data").marshal())
+	bulkResponse, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "bulk").
+		SetMetadataKeyValue("index", testIndex).
+		SetMetadataKeyValue("refresh", "wait_for").
+		SetData([]byte(actions)))
+	require.NoError(t, err)
+	require.Equal(t, "2", bulkResponse.Metadata["count"])
+	require.Equal(t, "0", bulkResponse.Metadata["failed"])
+
+	query := []byte(`{"query":{"match":{"data":"bulk-data"}},"sort":[{"id":"asc"}]}`)
+	searchResponse, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "search").
+		SetMetadataKeyValue("index", testIndex).
+		SetMetadataKeyValue("size", "1").
+		SetData(query))
+	require.NoError(t, err)
+	require.Equal(t, "1", searchResponse.Metadata["count"])
+	require.NotEmpty(t, searchResponse.Metadata["search_after"])
+	nextResponse, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "search").
+		SetMetadataKeyValue("index", testIndex).
+		SetMetadataKeyValue("size", "1").
+		SetMetadataKeyValue("search_after", searchResponse.Metadata["search_after"]).
+		SetData(query))
+	require.NoError(t, err)
+	require.Equal(t, "1", nextResponse.Metadata["count"])
+	require.NotEqual(t, searchResponse.Metadata["search_after"], nextResponse.Metadata["search_after"])
+
+	countResponse, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "count").
+		SetMetadataKeyValue("index", testIndex).
+		SetData([]byte(`{"query":{"match":{"data":"bulk-data"}}}`)))
+	require.NoError(t, err)
+	require.NotEqual(t, "0", countResponse.Metadata["count"])
+
+	deleteResponse, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "delete_by_query").
+		SetMetadataKeyValue("index", testIndex).
+		SetMetadataKeyValue("refresh", "true").
+		SetData([]byte(`{"query":{"match":{"data":"bulk-data"}}}`)))
+	require.NoError(t, err)
+	require.NotEqual(t, "0", deleteResponse.Metadata["deleted"])
+}
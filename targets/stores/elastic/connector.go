@@ -1,6 +1,8 @@
 package elastic
 
 import (
+	"math"
+
 	"github.com/kubemq-hub/builder/connector/common"
 )
 
@@ -51,7 +53,7 @@ func Connector() *common.Connector {
 				SetName("method").
 				SetKind("string").
 				SetDescription("Set Elastic execution method").
-				SetOptions([]string{"get", "set", "delete", "index.exists", "index.create", "index.delete"}).
+				SetOptions([]string{"get", "set", "delete", "index.exists", "index.create", "index.delete", "search", "count", "bulk", "update_by_query", "delete_by_query", "template.create", "template.get", "template.delete", "alias.create", "alias.delete", "alias.get"}).
 				SetDefault("get").
 				SetMust(true),
 		).
@@ -59,15 +61,93 @@ func Connector() *common.Connector {
 			common.NewMetadata().
 				SetName("index").
 				SetKind("string").
-				SetDescription("Select Elastic index").
+				SetDescription("Select Elastic index, comma separated indices for search, count, by query and alias methods").
 				SetDefault("").
-				SetMust(true),
+				SetMust(false),
 		).
 		AddMetadata(
 			common.NewMetadata().
 				SetName("id").
 				SetKind("string").
 				SetDescription("Select Elastic document id").
-				SetMust(true),
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("name").
+				SetKind("string").
+				SetDescription("Set Elastic index template name").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("alias").
+				SetKind("string").
+				SetDescription("Set Elastic index alias").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("size").
+				SetKind("int").
+				SetDescription("Set search page size").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("from").
+				SetKind("int").
+				SetDescription("Set search hits offset").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("search_after").
+				SetKind("string").
+				SetDescription("Set search after sort values as json array").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("scroll").
+				SetKind("string").
+				SetDescription("Set search scroll keep alive").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("scroll_id").
+				SetKind("string").
+				SetDescription("Set search scroll id of the next page").
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("refresh").
+				SetKind("string").
+				SetDescription("Set refresh after write").
+				SetOptions([]string{"true", "false", "wait_for"}).
+				SetDefault("").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("conflicts").
+				SetKind("string").
+				SetDescription("Set by query methods version conflicts handling").
+				SetOptions([]string{"abort", "proceed"}).
+				SetDefault("").
+				SetMust(false),
 		)
 }
@@ -1,29 +1,21 @@
 package elastic
 
 import (
-	"fmt"
-
 	"github.com/kubemq-io/kubemq-targets/config"
+	"github.com/kubemq-io/kubemq-targets/pkg/elasticengine"
 )
 
 type options struct {
-	urls     []string
-	sniff    bool
-	username string
-	password string
+	// engine holds the connection settings
+	engine elasticengine.Options
 }
 
 func parseOptions(cfg config.Spec) (options, error) {
 	o := options{}
-
 	var err error
-	o.urls, err = cfg.Properties.MustParseStringList("urls")
+	o.engine, err = elasticengine.ParseOptions(cfg.Properties)
 	if err != nil {
-		return options{}, fmt.Errorf("error parsing urls, %w", err)
+		return options{}, err
 	}
-	o.sniff = cfg.Properties.ParseBool("sniff", true)
-	o.username = cfg.Properties.ParseString("username", "")
-	o.password = cfg.Properties.ParseString("password", "")
-
 	return o, nil
 }
@@ -25,10 +25,9 @@ type Item struct {
 	Value string `bson:"value"`
 }
 type Client struct {
-	log        *logger.Logger
-	opts       options
-	client     *mongo.Client
-	collection *mongo.Collection
+	log    *logger.Logger
+	opts   options
+	client *mongo.Client
 }
 
 func New() *Client {
@@ -54,12 +53,22 @@ func (c *Client) Init(ctx context.Context, cfg config.Spec, log *logger.Logger)
 	if err != nil {
 		return fmt.Errorf("error in creating mongodb client: %s", err)
 	}
-
-	collection := c.client.Database(c.opts.database).Collection(c.opts.collection)
-	c.collection = collection
 	return nil
 }
 
+// getCollection returns the request collection, the database and collection options are used when the request does not set them
+func (c *Client) getCollection(meta metadata) *mongo.Collection {
+	database := meta.database
+	if database == "" {
+		database = c.opts.database
+	}
+	collection := meta.collection
+	if collection == "" {
+		collection = c.opts.collection
+	}
+	return c.client.Database(database).Collection(collection)
+}
+
 func (c *Client) getMongoDBClient(ctx context.Context) (*mongo.Client, error) {
 	opts := monogOptions.Client().ApplyURI(c.opts.url)
 	client, err := mongo.Connect(ctx, opts)
@@ -90,9 +99,9 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 	case "find_many":
 		return c.Find(ctx, meta)
 	case "insert":
-		return c.Insert(ctx, req.Data)
+		return c.Insert(ctx, meta, req.Data)
 	case "insert_many":
-		return c.InsertMany(ctx, req.Data)
+		return c.InsertMany(ctx, meta, req.Data)
 	case "update":
 		return c.UpdateOne(ctx, meta, req.Data)
 	case "update_many":
@@ -102,9 +111,19 @@ func (c *Client) Do(ctx context.Context, req *types.Request) (*types.Response, e
 	case "delete_many":
 		return c.DeleteMany(ctx, meta)
 	case "aggregate":
-		return c.Aggregate(ctx, req.Data)
+		return c.Aggregate(ctx, meta, req.Data)
 	case "distinct":
 		return c.Distinct(ctx, meta)
+	case "bulk_write":
+		return c.BulkWrite(ctx, meta, req.Data)
+	case "transaction":
+		return c.Transaction(ctx, meta, req.Data)
+	case "create_index":
+		return c.CreateIndex(ctx, meta, req.Data)
+	case "drop_index":
+		return c.DropIndex(ctx, meta)
+	case "list_indexes":
+		return c.ListIndexes(ctx, meta)
 	}
 	return nil, nil
 }
@@ -114,7 +133,7 @@ func (c *Client) FindOne(ctx context.Context, meta metadata) (*types.Response, e
 		return nil, fmt.Errorf("find one document filter is invalid")
 	}
 	result := map[string]interface{}{}
-	err := c.collection.FindOne(ctx, meta.filter).Decode(&result)
+	err := c.getCollection(meta).FindOne(ctx, meta.filter).Decode(&result)
 	if err != nil {
 		return nil, fmt.Errorf("find one error, %s", err.Error())
 	}
@@ -131,8 +150,21 @@ func (c *Client) Find(ctx context.Context, meta metadata) (*types.Response, erro
 	if len(meta.filter) == 0 {
 		return nil, fmt.Errorf("find documents filter is invalid")
 	}
+	findOptions := monogOptions.Find()
+	if len(meta.projection) > 0 {
+		findOptions.SetProjection(meta.projection)
+	}
+	if len(meta.sort) > 0 {
+		findOptions.SetSort(meta.sort)
+	}
+	if meta.limit > 0 {
+		findOptions.SetLimit(meta.limit)
+	}
+	if meta.skip > 0 {
+		findOptions.SetSkip(meta.skip)
+	}
 	results := []map[string]interface{}{}
-	cursor, err := c.collection.Find(ctx, meta.filter)
+	cursor, err := c.getCollection(meta).Find(ctx, meta.filter, findOptions)
 	if err != nil {
 		return nil, fmt.Errorf("find error, %s", err.Error())
 	}
@@ -149,14 +181,14 @@ func (c *Client) Find(ctx context.Context, meta metadata) (*types.Response, erro
 		SetMetadataKeyValue("result", "ok"), nil
 }
 
-func (c *Client) Insert(ctx context.Context, reqData []byte) (*types.Response, error) {
+func (c *Client) Insert(ctx context.Context, meta metadata, reqData []byte) (*types.Response, error) {
 	var doc interface{}
 
 	err := json.Unmarshal(reqData, &doc)
 	if err != nil {
 		return nil, fmt.Errorf("insert document json parsing error, %s", err.Error())
 	}
-	result, err := c.collection.InsertOne(ctx, doc)
+	result, err := c.getCollection(meta).InsertOne(ctx, doc)
 	if err != nil {
 		return nil, fmt.Errorf("insert error, %s", err.Error())
 	}
@@ -169,14 +201,14 @@ func (c *Client) Insert(ctx context.Context, reqData []byte) (*types.Response, e
 		SetMetadataKeyValue("result", "ok"), nil
 }
 
-func (c *Client) InsertMany(ctx context.Context, reqData []byte) (*types.Response, error) {
+func (c *Client) InsertMany(ctx context.Context, meta metadata, reqData []byte) (*types.Response, error) {
 	var docs []interface{}
 	err := json.Unmarshal(reqData, &docs)
 	if err != nil {
 		return nil, fmt.Errorf("insert many documents json parsing error, %s", err.Error())
 	}
 
-	results, err := c.collection.InsertMany(ctx, docs)
+	results, err := c.getCollection(meta).InsertMany(ctx, docs)
 	if err != nil {
 		return nil, fmt.Errorf("insert many error, %s", err.Error())
 	}
@@ -196,7 +228,7 @@ func (c *Client) UpdateOne(ctx context.Context, meta metadata, reqData []byte) (
 		return nil, fmt.Errorf("update one document json parsing error, %s", err.Error())
 	}
 	update := bson.M{"$set": &doc}
-	result, err := c.collection.UpdateOne(ctx, meta.filter, update, monogOptions.Update().SetUpsert(meta.setUpsert))
+	result, err := c.getCollection(meta).UpdateOne(ctx, meta.filter, update, monogOptions.Update().SetUpsert(meta.setUpsert))
 	if err != nil {
 		return nil, fmt.Errorf("update one document error, %s", err.Error())
 	}
@@ -217,7 +249,7 @@ func (c *Client) UpdateMany(ctx context.Context, meta metadata, reqData []byte)
 	}
 
 	update := bson.M{"$set": &doc}
-	result, err := c.collection.UpdateMany(ctx, meta.filter, update, monogOptions.Update().SetUpsert(meta.setUpsert))
+	result, err := c.getCollection(meta).UpdateMany(ctx, meta.filter, update, monogOptions.Update().SetUpsert(meta.setUpsert))
 	if err != nil {
 		return nil, fmt.Errorf("update many documents error, %s", err.Error())
 	}
@@ -234,7 +266,7 @@ func (c *Client) DeleteOne(ctx context.Context, meta metadata) (*types.Response,
 	if len(meta.filter) == 0 {
 		return nil, fmt.Errorf("delete one document filter is invalid")
 	}
-	result, err := c.collection.DeleteOne(ctx, meta.filter)
+	result, err := c.getCollection(meta).DeleteOne(ctx, meta.filter)
 	if err != nil {
 		return nil, fmt.Errorf("delete one document error, %s", err.Error())
 	}
@@ -247,7 +279,7 @@ func (c *Client) DeleteMany(ctx context.Context, meta metadata) (*types.Response
 	if len(meta.filter) == 0 {
 		return nil, fmt.Errorf("delete many documents filter is invalid")
 	}
-	result, err := c.collection.DeleteMany(ctx, meta.filter)
+	result, err := c.getCollection(meta).DeleteMany(ctx, meta.filter)
 	if err != nil {
 		return nil, fmt.Errorf("delete many documents error, %s", err.Error())
 	}
@@ -256,14 +288,14 @@ func (c *Client) DeleteMany(ctx context.Context, meta metadata) (*types.Response
 		SetMetadataKeyValue("result", "ok"), nil
 }
 
-func (c *Client) Aggregate(ctx context.Context, reqData []byte) (*types.Response, error) {
+func (c *Client) Aggregate(ctx context.Context, meta metadata, reqData []byte) (*types.Response, error) {
 	var pipeline interface{}
 	err := json.Unmarshal(reqData, &pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("aggregate pipeline json parsing error, %s", err.Error())
 	}
 	results := []map[string]interface{}{}
-	cursor, err := c.collection.Aggregate(ctx, pipeline)
+	cursor, err := c.getCollection(meta).Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("aggregate error, %s", err.Error())
 	}
@@ -288,7 +320,7 @@ func (c *Client) Distinct(ctx context.Context, meta metadata) (*types.Response,
 		return nil, fmt.Errorf("distinct filter is invalid")
 	}
 
-	results, err := c.collection.Distinct(ctx, meta.fieldName, meta.filter)
+	results, err := c.getCollection(meta).Distinct(ctx, meta.fieldName, meta.filter)
 	if err != nil {
 		return nil, fmt.Errorf("distinct error, %s", err.Error())
 	}
@@ -308,7 +340,7 @@ func (c *Client) Get(ctx context.Context, meta metadata) (*types.Response, error
 	var result Item
 
 	filter := bson.M{id: meta.key}
-	err := c.collection.FindOne(ctx, filter).Decode(&result)
+	err := c.getCollection(meta).FindOne(ctx, filter).Decode(&result)
 	if err != nil {
 		return nil, fmt.Errorf("no data found for this key")
 	}
@@ -326,7 +358,7 @@ func (c *Client) Set(ctx context.Context, meta metadata, data []byte) (*types.Re
 	}
 	filter := bson.M{id: meta.key}
 	update := bson.M{"$set": bson.M{id: meta.key, value: string(data)}}
-	_, err := c.collection.UpdateOne(ctx, filter, update, monogOptions.Update().SetUpsert(true))
+	_, err := c.getCollection(meta).UpdateOne(ctx, filter, update, monogOptions.Update().SetUpsert(true))
 	if err != nil {
 		return nil, fmt.Errorf("failed to set key %s: %s", meta.key, err)
 	}
@@ -342,7 +374,7 @@ func (c *Client) Delete(ctx context.Context, meta metadata) (*types.Response, er
 	}
 
 	filter := bson.M{id: meta.key}
-	_, err := c.collection.DeleteOne(ctx, filter)
+	_, err := c.getCollection(meta).DeleteOne(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete key %s: %s", meta.key, err)
 	}
@@ -10,6 +10,8 @@ import (
 	"github.com/kubemq-io/kubemq-targets/pkg/uuid"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type testDocument struct {
@@ -566,3 +568,139 @@ func TestClient_Insert_Find_Update_Delete(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, delResponse)
 }
+
+func TestParseMetadata_FindOptions(t *testing.T) {
+	meta, err := parseMetadata(types.Metadata{
+		"method":     "find_many",
+		"collection": "other",
+		"projection": `{"name":1}`,
+		"sort":       `{"size":-1,"name":1,"age":1}`,
+		"limit":      "10",
+		"skip":       "5",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "other", meta.collection)
+	require.Equal(t, map[string]interface{}{"name": float64(1)}, meta.projection)
+	require.Equal(t, bson.D{{Key: "size", Value: int32(-1)}, {Key: "name", Value: int32(1)}, {Key: "age", Value: int32(1)}}, meta.sort)
+	require.EqualValues(t, 10, meta.limit)
+	require.EqualValues(t, 5, meta.skip)
+	require.True(t, meta.ordered)
+
+	_, err = parseMetadata(types.Metadata{"method": "find_many", "sort": "bad"})
+	require.Error(t, err)
+	_, err = parseMetadata(types.Metadata{"method": "find_many", "limit": "-1"})
+	require.Error(t, err)
+}
+
+func TestOperation_writeModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation Operation
+		want      mongo.WriteModel
+		wantErr   bool
+	}{
+		{
+			name:      "insert one",
+			operation: Operation{Operation: "insert_one", Document: map[string]interface{}{"a": 1}},
+			want:      mongo.NewInsertOneModel().SetDocument(map[string]interface{}{"a": 1}),
+		},
+		{
+			name:      "update one - fields are set",
+			operation: Operation{Operation: "update_one", Filter: map[string]interface{}{"_id": "1"}, Update: map[string]interface{}{"a": 1}, Upsert: true},
+			want:      mongo.NewUpdateOneModel().SetFilter(map[string]interface{}{"_id": "1"}).SetUpdate(bson.M{"$set": map[string]interface{}{"a": 1}}).SetUpsert(true),
+		},
+		{
+			name:      "update many - update operators",
+			operation: Operation{Operation: "update_many", Filter: map[string]interface{}{"a": 1}, Update: map[string]interface{}{"$inc": map[string]interface{}{"b": 1}}},
+			want:      mongo.NewUpdateManyModel().SetFilter(map[string]interface{}{"a": 1}).SetUpdate(map[string]interface{}{"$inc": map[string]interface{}{"b": 1}}).SetUpsert(false),
+		},
+		{
+			name:      "replace one",
+			operation: Operation{Operation: "replace_one", Filter: map[string]interface{}{"_id": "1"}, Replacement: map[string]interface{}{"a": 2}},
+			want:      mongo.NewReplaceOneModel().SetFilter(map[string]interface{}{"_id": "1"}).SetReplacement(map[string]interface{}{"a": 2}).SetUpsert(false),
+		},
+		{
+			name:      "delete many",
+			operation: Operation{Operation: "delete_many", Filter: map[string]interface{}{"a": 1}},
+			want:      mongo.NewDeleteManyModel().SetFilter(map[string]interface{}{"a": 1}),
+		},
+		{
+			name:      "invalid - insert one no document",
+			operation: Operation{Operation: "insert_one"},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid - delete one no filter",
+			operation: Operation{Operation: "delete_one"},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid - update one no update",
+			operation: Operation{Operation: "update_one", Filter: map[string]interface{}{"a": 1}},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid - bad operation",
+			operation: Operation{Operation: "bad", Filter: map[string]interface{}{"a": 1}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.operation.writeModel()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_BulkWrite_Indexes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New()
+	err := c.Init(ctx, config.Spec{
+		Name: "mongodb",
+		Kind: "mongodb",
+		Properties: map[string]string{
+			"url":        "mongodb://localhost:27017",
+			"database":   "admin",
+			"collection": "test",
+		},
+	}, nil)
+	require.NoError(t, err)
+	collection := uuid.New().String()
+	bulkRequest := types.NewRequest().
+		SetMetadataKeyValue("method", "bulk_write").
+		SetMetadataKeyValue("collection", collection).
+		SetData([]byte(`[{"operation":"insert_one","document":{"_id":"1","size":1}},{"operation":"insert_one","document":{"_id":"2","size":2}},{"operation":"update_one","filter":{"_id":"1"},"update":{"size":3}},{"operation":"delete_one","filter":{"_id":"2"}}]`))
+	bulkResponse, err := c.Do(ctx, bulkRequest)
+	require.NoError(t, err)
+	require.Equal(t, "2", bulkResponse.Metadata["inserted_count"])
+	require.Equal(t, "1", bulkResponse.Metadata["modified_count"])
+	require.Equal(t, "1", bulkResponse.Metadata["deleted_count"])
+
+	findRequest := types.NewRequest().
+		SetMetadataKeyValue("method", "find_many").
+		SetMetadataKeyValue("collection", collection).
+		SetMetadataKeyValue("filter", `{"size":{"$gt":0}}`).
+		SetMetadataKeyValue("projection", `{"_id":0}`)
+	findResponse, err := c.Do(ctx, findRequest)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"size":3}]`, string(findResponse.Data))
+
+	indexResponse, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "create_index").
+		SetMetadataKeyValue("collection", collection).
+		SetData([]byte(`{"keys":{"size":-1},"name":"size_index"}`)))
+	require.NoError(t, err)
+	require.Equal(t, "size_index", indexResponse.Metadata["index_name"])
+	_, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "drop_index").
+		SetMetadataKeyValue("collection", collection).
+		SetMetadataKeyValue("index_name", "size_index"))
+	require.NoError(t, err)
+}
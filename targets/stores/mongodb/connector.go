@@ -100,7 +100,8 @@ func Connector() *common.Connector {
 				SetOptions([]string{
 					"get_by_key", "set_by_key", "delete_by_key", "find", "find_many",
 					"insert", "insert_many", "update", "update_many", "delete_one",
-					"delete_many", "aggregate", "distinct",
+					"delete_many", "aggregate", "distinct", "bulk_write", "transaction",
+					"create_index", "drop_index", "list_indexes",
 				}).
 				SetDefault("get").
 				SetMust(true),
@@ -125,5 +126,68 @@ func Connector() *common.Connector {
 				SetKind("bool").
 				SetDescription("Set Upsert in update mode").
 				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("database").
+				SetKind("string").
+				SetDescription("Set MongoDB database, overrides the database property").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("collection").
+				SetKind("string").
+				SetDescription("Set MongoDB collection, overrides the collection property").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("projection").
+				SetKind("string").
+				SetDescription("Set find many projection json object").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("sort").
+				SetKind("string").
+				SetDescription("Set find many sort json object").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("limit").
+				SetKind("int").
+				SetDescription("Set find many results limit, 0 returns all").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("skip").
+				SetKind("int").
+				SetDescription("Set find many results to skip").
+				SetDefault("0").
+				SetMin(0).
+				SetMax(math.MaxInt32).
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("ordered").
+				SetKind("bool").
+				SetDescription("Set bulk write ordered execution").
+				SetDefault("true").
+				SetMust(false),
+		).
+		AddMetadata(
+			common.NewMetadata().
+				SetName("index_name").
+				SetKind("string").
+				SetDescription("Set index name to drop").
+				SetMust(false),
 		)
 }
@@ -2,8 +2,10 @@ package mongodb
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/kubemq-io/kubemq-targets/types"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 var methodsMap = map[string]string{
@@ -20,14 +22,27 @@ var methodsMap = map[string]string{
 	"delete_many":   "delete_many",
 	"aggregate":     "aggregate",
 	"distinct":      "distinct",
+	"bulk_write":    "bulk_write",
+	"transaction":   "transaction",
+	"create_index":  "create_index",
+	"drop_index":    "drop_index",
+	"list_indexes":  "list_indexes",
 }
 
 type metadata struct {
-	method    string
-	key       string
-	filter    map[string]interface{}
-	fieldName string
-	setUpsert bool
+	method     string
+	key        string
+	filter     map[string]interface{}
+	fieldName  string
+	setUpsert  bool
+	database   string
+	collection string
+	projection map[string]interface{}
+	sort       bson.D
+	limit      int64
+	skip       int64
+	ordered    bool
+	indexName  string
 }
 
 func parseMetadata(meta types.Metadata) (metadata, error) {
@@ -45,5 +60,30 @@ func parseMetadata(meta types.Metadata) (metadata, error) {
 		return metadata{}, fmt.Errorf("error parsing filter, %w", err)
 	}
 	m.setUpsert = meta.ParseBool("set_upsert", false)
+	m.database = meta.ParseString("database", "")
+	m.collection = meta.ParseString("collection", "")
+	m.projection, err = meta.MustParseInterfaceMap("projection")
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing projection, %w", err)
+	}
+	// sort keys order is kept, a json object is parsed into an ordered document
+	if sort := meta.ParseString("sort", ""); sort != "" {
+		err = bson.UnmarshalExtJSON([]byte(sort), false, &m.sort)
+		if err != nil {
+			return metadata{}, fmt.Errorf("error parsing sort, %w", err)
+		}
+	}
+	limit, err := meta.ParseIntWithRange("limit", 0, 0, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing limit, %w", err)
+	}
+	m.limit = int64(limit)
+	skip, err := meta.ParseIntWithRange("skip", 0, 0, math.MaxInt32)
+	if err != nil {
+		return metadata{}, fmt.Errorf("error parsing skip, %w", err)
+	}
+	m.skip = int64(skip)
+	m.ordered = meta.ParseBool("ordered", true)
+	m.indexName = meta.ParseString("index_name", "")
 	return m, nil
 }
@@ -0,0 +1,253 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-targets/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	monogOptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Operation is a write operation of the bulk_write and transaction methods
+type Operation struct {
+	// Operation is one of insert_one, update_one, update_many, replace_one, delete_one and delete_many
+	Operation   string                 `json:"operation"`
+	Database    string                 `json:"database,omitempty"`
+	Collection  string                 `json:"collection,omitempty"`
+	Document    map[string]interface{} `json:"document,omitempty"`
+	Filter      map[string]interface{} `json:"filter,omitempty"`
+	Update      map[string]interface{} `json:"update,omitempty"`
+	Replacement map[string]interface{} `json:"replacement,omitempty"`
+	Upsert      bool                   `json:"upsert,omitempty"`
+}
+
+// WriteResult holds the counts of a bulk write or of a transaction operation
+type WriteResult struct {
+	InsertedCount int64                 `json:"inserted_count"`
+	MatchedCount  int64                 `json:"matched_count"`
+	ModifiedCount int64                 `json:"modified_count"`
+	DeletedCount  int64                 `json:"deleted_count"`
+	UpsertedCount int64                 `json:"upserted_count"`
+	UpsertedIDs   map[int64]interface{} `json:"upserted_ids,omitempty"`
+}
+
+type indexModel struct {
+	Keys               bson.D `bson:"keys"`
+	Name               string `bson:"name"`
+	Unique             bool   `bson:"unique"`
+	Sparse             bool   `bson:"sparse"`
+	ExpireAfterSeconds *int32 `bson:"expire_after_seconds"`
+}
+
+func parseOperations(data []byte) ([]Operation, error) {
+	var operations []Operation
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return nil, fmt.Errorf("operations json parsing error, %s", err.Error())
+	}
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("no operations found")
+	}
+	return operations, nil
+}
+
+// updateDocument returns the update as is when it holds update operators, otherwise the update fields are set like in the update methods
+func updateDocument(update map[string]interface{}) interface{} {
+	for field := range update {
+		if strings.HasPrefix(field, "$") {
+			return update
+		}
+	}
+	return bson.M{"$set": update}
+}
+
+func (o Operation) writeModel() (mongo.WriteModel, error) {
+	if o.Operation != "insert_one" && len(o.Filter) == 0 {
+		return nil, fmt.Errorf("%s operation filter is invalid", o.Operation)
+	}
+	switch o.Operation {
+	case "insert_one":
+		if len(o.Document) == 0 {
+			return nil, fmt.Errorf("insert_one operation document is missing")
+		}
+		return mongo.NewInsertOneModel().SetDocument(o.Document), nil
+	case "update_one", "update_many":
+		if len(o.Update) == 0 {
+			return nil, fmt.Errorf("%s operation update is missing", o.Operation)
+		}
+		if o.Operation == "update_one" {
+			return mongo.NewUpdateOneModel().SetFilter(o.Filter).SetUpdate(updateDocument(o.Update)).SetUpsert(o.Upsert), nil
+		}
+		return mongo.NewUpdateManyModel().SetFilter(o.Filter).SetUpdate(updateDocument(o.Update)).SetUpsert(o.Upsert), nil
+	case "replace_one":
+		if len(o.Replacement) == 0 {
+			return nil, fmt.Errorf("replace_one operation replacement is missing")
+		}
+		return mongo.NewReplaceOneModel().SetFilter(o.Filter).SetReplacement(o.Replacement).SetUpsert(o.Upsert), nil
+	case "delete_one":
+		return mongo.NewDeleteOneModel().SetFilter(o.Filter), nil
+	case "delete_many":
+		return mongo.NewDeleteManyModel().SetFilter(o.Filter), nil
+	}
+	return nil, fmt.Errorf("invalid operation %s", o.Operation)
+}
+
+func newWriteResult(result *mongo.BulkWriteResult) WriteResult {
+	return WriteResult{
+		InsertedCount: result.InsertedCount,
+		MatchedCount:  result.MatchedCount,
+		ModifiedCount: result.ModifiedCount,
+		DeletedCount:  result.DeletedCount,
+		UpsertedCount: result.UpsertedCount,
+		UpsertedIDs:   result.UpsertedIDs,
+	}
+}
+
+// BulkWrite executes mixed write operations on the request collection in a single bulk write
+func (c *Client) BulkWrite(ctx context.Context, meta metadata, reqData []byte) (*types.Response, error) {
+	operations, err := parseOperations(reqData)
+	if err != nil {
+		return nil, fmt.Errorf("bulk write %s", err.Error())
+	}
+	models := make([]mongo.WriteModel, 0, len(operations))
+	for i, operation := range operations {
+		if operation.Database != "" || operation.Collection != "" {
+			return nil, fmt.Errorf("bulk write operation %d error, database and collection are set by the request", i)
+		}
+		model, err := operation.writeModel()
+		if err != nil {
+			return nil, fmt.Errorf("bulk write operation %d error, %s", i, err.Error())
+		}
+		models = append(models, model)
+	}
+	result, err := c.getCollection(meta).BulkWrite(ctx, models, monogOptions.BulkWrite().SetOrdered(meta.ordered))
+	if err != nil {
+		return nil, fmt.Errorf("bulk write error, %s", err.Error())
+	}
+	data, err := json.Marshal(newWriteResult(result))
+	if err != nil {
+		return nil, fmt.Errorf("bulk write result json parsing error, %s", err.Error())
+	}
+	return types.NewResponse().
+		SetData(data).
+		SetMetadataKeyValue("inserted_count", fmt.Sprintf("%d", result.InsertedCount)).
+		SetMetadataKeyValue("modified_count", fmt.Sprintf("%d", result.ModifiedCount)).
+		SetMetadataKeyValue("deleted_count", fmt.Sprintf("%d", result.DeletedCount)).
+		SetMetadataKeyValue("upserted_count", fmt.Sprintf("%d", result.UpsertedCount)).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+// Transaction executes write operations in a multi-document transaction, each operation may set its own database and collection
+func (c *Client) Transaction(ctx context.Context, meta metadata, reqData []byte) (*types.Response, error) {
+	operations, err := parseOperations(reqData)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s", err.Error())
+	}
+	models := make([]mongo.WriteModel, 0, len(operations))
+	collections := make([]*mongo.Collection, 0, len(operations))
+	for i, operation := range operations {
+		model, err := operation.writeModel()
+		if err != nil {
+			return nil, fmt.Errorf("transaction operation %d error, %s", i, err.Error())
+		}
+		models = append(models, model)
+		opMeta := meta
+		if operation.Database != "" {
+			opMeta.database = operation.Database
+		}
+		if operation.Collection != "" {
+			opMeta.collection = operation.Collection
+		}
+		collections = append(collections, c.getCollection(opMeta))
+	}
+	session, err := c.client.StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("transaction session error, %s", err.Error())
+	}
+	defer session.EndSession(context.Background())
+	results, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		results := make([]WriteResult, 0, len(models))
+		for i, model := range models {
+			result, err := collections[i].BulkWrite(sessCtx, []mongo.WriteModel{model})
+			if err != nil {
+				return nil, fmt.Errorf("operation %d error, %w", i, err)
+			}
+			results = append(results, newWriteResult(result))
+		}
+		return results, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("transaction error, %s", err.Error())
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("transaction results json parsing error, %s", err.Error())
+	}
+	return types.NewResponse().
+		SetData(data).
+		SetMetadataKeyValue("count", fmt.Sprintf("%d", len(models))).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+// CreateIndex creates an index on the request collection, the index keys order is kept
+func (c *Client) CreateIndex(ctx context.Context, meta metadata, reqData []byte) (*types.Response, error) {
+	index := indexModel{}
+	err := bson.UnmarshalExtJSON(reqData, false, &index)
+	if err != nil {
+		return nil, fmt.Errorf("create index json parsing error, %s", err.Error())
+	}
+	if len(index.Keys) == 0 {
+		return nil, fmt.Errorf("create index keys are missing")
+	}
+	indexOptions := monogOptions.Index().SetUnique(index.Unique).SetSparse(index.Sparse)
+	if index.Name != "" {
+		indexOptions.SetName(index.Name)
+	}
+	if index.ExpireAfterSeconds != nil {
+		indexOptions.SetExpireAfterSeconds(*index.ExpireAfterSeconds)
+	}
+	name, err := c.getCollection(meta).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    index.Keys,
+		Options: indexOptions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create index error, %s", err.Error())
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("index_name", name).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (c *Client) DropIndex(ctx context.Context, meta metadata) (*types.Response, error) {
+	if meta.indexName == "" {
+		return nil, fmt.Errorf("drop index name missing")
+	}
+	_, err := c.getCollection(meta).Indexes().DropOne(ctx, meta.indexName)
+	if err != nil {
+		return nil, fmt.Errorf("drop index error, %s", err.Error())
+	}
+	return types.NewResponse().
+		SetMetadataKeyValue("index_name", meta.indexName).
+		SetMetadataKeyValue("result", "ok"), nil
+}
+
+func (c *Client) ListIndexes(ctx context.Context, meta metadata) (*types.Response, error) {
+	cursor, err := c.getCollection(meta).Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list indexes error, %s", err.Error())
+	}
+	results := []map[string]interface{}{}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return nil, fmt.Errorf("list indexes results parsing error, %s", err.Error())
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("list indexes json parsing error, %s", err.Error())
+	}
+	return types.NewResponse().
+		SetData(data).
+		SetMetadataKeyValue("result", "ok"), nil
+}
@@ -5,11 +5,14 @@ import (
 	"testing"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/kubemq-io/kubemq-targets/config"
 	"github.com/kubemq-io/kubemq-targets/types"
 	"github.com/stretchr/testify/require"
 )
 
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
 type post struct {
 	Id        int64  `json:"id"`
 	Title     string `json:"title,omitempty"`
@@ -65,6 +68,11 @@ const (
 	selectPostTable = `SELECT id,title,content,bignumber,boolvalue FROM post;`
 )
 
+var createPostTableResponse = types.NewResponse().
+	SetData([]byte(`[{"statement":0,"rows_affected":0,"last_insert_id":0},{"statement":1,"rows_affected":0,"last_insert_id":0},{"statement":2,"rows_affected":2,"last_insert_id":0}]`)).
+	SetMetadataKeyValue("rows_affected", "2").
+	SetMetadataKeyValue("result", "ok")
+
 func TestClient_Init(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -212,8 +220,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte(selectPostTable)),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok").
 				SetData(allPosts.marshal()),
@@ -280,8 +287,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -304,8 +310,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("some bad query")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -328,8 +333,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte("SELECT id,title,content FROM post where id=100")),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok"),
 			wantExecErr:  false,
@@ -353,8 +357,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query").
 				SetData([]byte(selectPostTable)),
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse: createPostTableResponse,
 			wantQueryResponse: types.NewResponse().
 				SetMetadataKeyValue("result", "ok").
 				SetData(allPosts.marshal()),
@@ -420,8 +423,7 @@ func TestClient_Query_Exec_Transaction(t *testing.T) {
 			queryRequest: types.NewRequest().
 				SetMetadataKeyValue("method", "query"),
 
-			wantExecResponse: types.NewResponse().
-				SetMetadataKeyValue("result", "ok"),
+			wantExecResponse:  createPostTableResponse,
 			wantQueryResponse: nil,
 			wantExecErr:       false,
 			wantQueryErr:      true,
@@ -594,3 +596,38 @@ func TestClient_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Parameterised_BulkInsert(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New()
+	err := c.Init(ctx, config.Spec{
+		Name: "mysql-target",
+		Kind: "",
+		Properties: map[string]string{
+			"connection": "root:mysql@(localhost:3306)/store?charset=utf8&parseTime=True&loc=Local",
+		},
+	}, nil)
+	require.NoError(t, err)
+	transaction := `{"statements":[
+		{"sql":"DROP TABLE IF EXISTS post"},
+		{"sql":"CREATE TABLE post (ID bigint, TITLE varchar(40), CONTENT varchar(255), BIGNUMBER bigint, BOOLVALUE boolean, CONSTRAINT pk_post PRIMARY KEY(ID))"},
+		{"sql":"INSERT INTO post(ID,TITLE,CONTENT,BIGNUMBER,BOOLVALUE) VALUES (?,?,'Content; One',?,?)","params":[0,null,1231241241231231123,true]},
+		{"sql":"UPDATE post SET CONTENT=:content WHERE ID=:id","params":{"id":0,"content":"Content One"}}
+	]}`
+	resp, err := c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "transaction").
+		SetData([]byte(transaction)))
+	require.NoError(t, err)
+	require.Equal(t, "2", resp.Metadata["rows_affected"])
+	resp, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "bulk_insert").
+		SetData([]byte(`{"table":"post","columns":["id","title","content","bignumber","boolvalue"],"rows":[{"id":1,"title":"Title Two","content":"Content Two","bignumber":123125241231231123,"boolvalue":false}]}`)))
+	require.NoError(t, err)
+	require.Equal(t, "1", resp.Metadata["rows_affected"])
+	resp, err = c.Do(ctx, types.NewRequest().
+		SetMetadataKeyValue("method", "query").
+		SetData([]byte(`{"sql":"SELECT id,title,content,bignumber,boolvalue FROM post WHERE id >= :id ORDER BY id","params":{"id":0}}`)))
+	require.NoError(t, err)
+	require.EqualValues(t, &allPosts, unmarshal(resp.Data))
+}
@@ -149,6 +149,24 @@ func (m Metadata) ParseIntWithRange(key string, defaultValue, min, max int) (int
 	return val, nil
 }
 
+func (m Metadata) ParseFloat64WithRange(key string, defaultValue, min, max float64) (float64, error) {
+	val := defaultValue
+	if str, ok := m[key]; ok && str != "" {
+		parsedVal, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid float conversion error for value %s", str)
+		}
+		val = parsedVal
+	}
+	if val < min {
+		return 0, fmt.Errorf("conversion value cannot be lower than %v", min)
+	}
+	if val > max {
+		return 0, fmt.Errorf("conversion value cannot be higher than %v", max)
+	}
+	return val, nil
+}
+
 func (m Metadata) MustParseIntWithRange(key string, min, max int) (int, error) {
 	val, err := m.MustParseInt(key)
 	if err != nil {